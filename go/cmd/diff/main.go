@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/diff"
+)
+
+func main() {
+	var (
+		format    string
+		threshold float64
+	)
+	flag.StringVar(&format, "format", "text", "Output format: text or json")
+	flag.Float64Var(&threshold, "threshold", 0.01, "Report analytics metrics that moved by more than this fraction of their old value")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] OLD_DIR NEW_DIR\n\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "Compares two data directories, e.g. ../mcp/data and a recorded snapshot (snapshots/<arena>/<id>).")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 || (format != "text" && format != "json") || threshold < 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	oldDir, newDir := flag.Arg(0), flag.Arg(1)
+	for _, dir := range []string{oldDir, newDir} {
+		if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+			log.Fatalf("%s: not a data directory", dir)
+		}
+	}
+
+	d, err := diff.Compare(data.NewDataLoader(oldDir), data.NewDataLoader(newDir), threshold)
+	if err != nil {
+		log.Fatal(err)
+	}
+	d.From, d.To = oldDir, newDir
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(d)
+	} else {
+		err = diff.WriteText(os.Stdout, d)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
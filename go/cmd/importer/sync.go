@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+// syncStats counts what an import did to one table.
+type syncStats struct {
+	Table     string
+	Inserted  int
+	Updated   int
+	Unchanged int
+	Removed   int
+}
+
+func (s syncStats) String() string {
+	return fmt.Sprintf("%s: inserted=%d updated=%d unchanged=%d removed=%d",
+		s.Table, s.Inserted, s.Updated, s.Unchanged, s.Removed)
+}
+
+// tableSync reconciles rows keyed by a stable id with the source: rows are
+// written only when new or when their content hash changed, and keys that
+// were not seen can be removed afterwards.
+type tableSync struct {
+	syncStats
+	hashes  map[string]string   // key -> stored content hash ("" before hashing)
+	removed map[string]struct{} // stored keys already removed but kept, e.g. closed positions
+	seen    map[string]struct{}
+}
+
+// loadSync reads the current key/hash pairs; query must select the columns
+// key and hash, and may select removed for rows kept after removal.
+func loadSync(ctx context.Context, s sqlx.Session, table, query string, args ...interface{}) (*tableSync, error) {
+	var rows []struct {
+		Key     string         `db:"key"`
+		Hash    sql.NullString `db:"hash"`
+		Removed bool           `db:"removed"`
+	}
+	if err := s.QueryRowsPartialCtx(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("load %s hashes: %w", table, err)
+	}
+	ts := &tableSync{
+		syncStats: syncStats{Table: table},
+		hashes:    make(map[string]string, len(rows)),
+		removed:   map[string]struct{}{},
+		seen:      map[string]struct{}{},
+	}
+	for _, r := range rows {
+		ts.hashes[r.Key] = r.Hash.String
+		if r.Removed {
+			ts.removed[r.Key] = struct{}{}
+		}
+	}
+	return ts, nil
+}
+
+// apply calls write unless the row with key already has hash. Removed rows
+// are always written, which restores them. Repeated keys in the source are
+// written once.
+func (ts *tableSync) apply(key, hash string, write func() error) error {
+	if _, ok := ts.seen[key]; ok {
+		return nil
+	}
+	ts.seen[key] = struct{}{}
+	old, ok := ts.hashes[key]
+	_, removed := ts.removed[key]
+	switch {
+	case !ok:
+		ts.Inserted++
+	case old == hash && !removed:
+		ts.Unchanged++
+		return nil
+	default:
+		ts.Updated++
+	}
+	return write()
+}
+
+// removeStale calls remove for every stored key missing from the source
+// that is not removed already.
+func (ts *tableSync) removeStale(remove func(key string) error) error {
+	var stale []string
+	for key := range ts.hashes {
+		_, seen := ts.seen[key]
+		_, removed := ts.removed[key]
+		if !seen && !removed {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(stale)
+	for _, key := range stale {
+		if err := remove(key); err != nil {
+			return err
+		}
+		ts.Removed++
+	}
+	return nil
+}
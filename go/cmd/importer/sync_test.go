@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableSync(t *testing.T) {
+	ts := &tableSync{
+		syncStats: syncStats{Table: "trades"},
+		hashes:    map[string]string{"same": "h1", "changed": "old", "legacy": "", "gone": "h2", "closed": "h5", "reopened": "h6"},
+		removed:   map[string]struct{}{"closed": {}, "reopened": {}},
+		seen:      map[string]struct{}{},
+	}
+	var written []string
+	write := func(key string) func() error {
+		return func() error {
+			written = append(written, key)
+			return nil
+		}
+	}
+
+	require.NoError(t, ts.apply("same", "h1", write("same")))
+	require.NoError(t, ts.apply("changed", "new", write("changed")))
+	require.NoError(t, ts.apply("legacy", "h3", write("legacy")))
+	require.NoError(t, ts.apply("fresh", "h4", write("fresh")))
+	require.NoError(t, ts.apply("fresh", "h4", write("fresh")), "Repeated keys are written once")
+	require.NoError(t, ts.apply("reopened", "h6", write("reopened")), "Removed rows are restored")
+	assert.Equal(t, []string{"changed", "legacy", "fresh", "reopened"}, written)
+
+	var removed []string
+	require.NoError(t, ts.removeStale(func(key string) error {
+		removed = append(removed, key)
+		return nil
+	}))
+	assert.Equal(t, []string{"gone"}, removed, "Removed rows are not removed again")
+	assert.Equal(t, "trades: inserted=1 updated=3 unchanged=1 removed=1", ts.String())
+
+	failing := &tableSync{hashes: map[string]string{"x": "h"}, seen: map[string]struct{}{}}
+	assert.Error(t, failing.removeStale(func(string) error { return errors.New("boom") }))
+	assert.Zero(t, failing.Removed)
+}
@@ -0,0 +1,117 @@
+// Package auth authenticates API keys for the ingest, admin and model write
+// routes and checks their role. Keys come from Auth.Keys in the config and,
+// with Postgres, from the api_keys table (see KeyStore). Public GET routes
+// take no credentials.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"nof0-api/internal/config"
+	"nof0-api/internal/errs"
+)
+
+// Role is what a key may do. Roles are ordered: admin includes ingester,
+// which includes reader.
+type Role string
+
+const (
+	RoleReader   Role = "reader"   // names the client for rate limiting only
+	RoleIngester Role = "ingester" // POST /api/ingest/*
+	RoleAdmin    Role = "admin"    // model writes and /api/admin/*
+)
+
+var rank = map[Role]int{RoleReader: 1, RoleIngester: 2, RoleAdmin: 3}
+
+var (
+	ErrUnauthorized = errs.Unauthenticated("missing or invalid API key")
+	ErrForbidden    = errs.PermissionDenied("API key lacks the required role")
+)
+
+// ParseRole returns the role named s; unknown names are InvalidArgument.
+func ParseRole(s string) (Role, error) {
+	if _, ok := rank[Role(s)]; !ok {
+		return "", errs.InvalidArgument("unknown role %q (want reader, ingester or admin)", s)
+	}
+	return Role(s), nil
+}
+
+// Allows reports whether r grants need.
+func (r Role) Allows(need Role) bool {
+	return rank[r] > 0 && rank[r] >= rank[need]
+}
+
+// Principal is the key a request authenticated with.
+type Principal struct {
+	KeyId string // empty for config keys
+	Name  string
+	Role  Role
+}
+
+// Authenticator checks keys against the config keys, then the key store.
+type Authenticator struct {
+	static map[[sha256.Size]byte]Principal
+	store  *KeyStore
+}
+
+// NewAuthenticator indexes keys by hash; store may be nil without Postgres.
+func NewAuthenticator(keys []config.ApiKeyConf, store *KeyStore) (*Authenticator, error) {
+	a := &Authenticator{static: map[[sha256.Size]byte]Principal{}, store: store}
+	for _, k := range keys {
+		role, err := ParseRole(k.Role)
+		if err != nil {
+			return nil, fmt.Errorf("auth key %s: %w", k.Name, err)
+		}
+		if k.Key == "" {
+			return nil, fmt.Errorf("auth key %s: empty key", k.Name)
+		}
+		a.static[sha256.Sum256([]byte(k.Key))] = Principal{Name: k.Name, Role: role}
+	}
+	return a, nil
+}
+
+// Authenticate returns the principal of token, or ErrUnauthorized.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	if p, ok := a.Configured(token); ok {
+		return p, nil
+	}
+	if token == "" || a.store == nil {
+		return nil, ErrUnauthorized
+	}
+	return a.store.Authenticate(ctx, token)
+}
+
+// Configured returns the principal of token if it is one of the config keys,
+// which needs no store query.
+func (a *Authenticator) Configured(token string) (*Principal, bool) {
+	if token == "" {
+		return nil, false
+	}
+	p, ok := a.static[sha256.Sum256([]byte(token))]
+	return &p, ok
+}
+
+// Token extracts the key from "Authorization: Bearer <key>" or "X-Api-Key".
+func Token(r *http.Request) string {
+	if t, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(t)
+	}
+	return strings.TrimSpace(r.Header.Get("X-Api-Key"))
+}
+
+type principalKey struct{}
+
+// WithPrincipal stores p on ctx for logic and logs.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored by the auth middleware, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
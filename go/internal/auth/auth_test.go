@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/config"
+	"nof0-api/internal/errs"
+)
+
+var keyColumns = []string{"id", "name", "role", "secret_hash", "created_at", "last_used_at", "revoked_at"}
+
+func TestRoleAllows(t *testing.T) {
+	assert.True(t, RoleAdmin.Allows(RoleIngester))
+	assert.True(t, RoleIngester.Allows(RoleReader))
+	assert.True(t, RoleReader.Allows(RoleReader))
+	assert.False(t, RoleIngester.Allows(RoleAdmin))
+	assert.False(t, RoleReader.Allows(RoleIngester))
+	assert.False(t, Role("root").Allows(RoleReader))
+
+	_, err := ParseRole("root")
+	assert.True(t, errs.Is(err, errs.KindInvalidArgument), "%v", err)
+	_, err = NewKeyStore(nil).Create(context.Background(), "ops", Role("root"))
+	assert.True(t, errs.Is(err, errs.KindInvalidArgument), "%v", err)
+}
+
+func TestStaticKeys(t *testing.T) {
+	a, err := NewAuthenticator([]config.ApiKeyConf{{Name: "runner", Role: "ingester", Key: "s3cret"}}, nil)
+	require.NoError(t, err)
+
+	p, err := a.Authenticate(context.Background(), "s3cret")
+	require.NoError(t, err)
+	assert.Equal(t, Principal{Name: "runner", Role: RoleIngester}, *p)
+	for _, token := range []string{"", "s3cret ", "nof0_ab_cd"} {
+		_, err = a.Authenticate(context.Background(), token)
+		assert.ErrorIs(t, err, ErrUnauthorized, token)
+	}
+
+	_, err = NewAuthenticator([]config.ApiKeyConf{{Name: "x", Role: "root", Key: "k"}}, nil)
+	assert.Error(t, err)
+}
+
+func TestKeyStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	store := NewKeyStore(sqlx.NewSqlConnFromDB(db))
+	ctx := context.Background()
+	created := time.Date(2025, 10, 26, 0, 0, 0, 0, time.UTC)
+
+	var secretHash string
+	mock.ExpectQuery("INSERT INTO api_keys").
+		WithArgs(sqlmock.AnyArg(), "runner", "ingester", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(keyColumns).AddRow("0011223344556677", "runner", "ingester", "h", created, nil, nil))
+	k, err := store.Create(ctx, "runner", RoleIngester)
+	require.NoError(t, err)
+	assert.Equal(t, created.UnixMilli(), k.CreatedAt)
+	id, secret, ok := parseToken(k.Key)
+	require.True(t, ok, k.Key)
+	assert.Len(t, id, 16)
+	assert.Len(t, secret, 64)
+	secretHash = hashSecret(secret)
+	assert.NotContains(t, secretHash, secret, "Only the hash is stored")
+
+	mock.ExpectQuery("SELECT .* FROM api_keys").WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(keyColumns).AddRow(id, "runner", "ingester", secretHash, created, nil, nil))
+	mock.ExpectExec("UPDATE api_keys SET last_used_at").WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+	p, err := store.Authenticate(ctx, k.Key)
+	require.NoError(t, err)
+	assert.Equal(t, Principal{KeyId: id, Name: "runner", Role: RoleIngester}, *p)
+
+	mock.ExpectQuery("SELECT .* FROM api_keys").WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(keyColumns).AddRow(id, "runner", "ingester", secretHash, created, nil, nil))
+	_, err = store.Authenticate(ctx, tokenPrefix+id+"_"+strings.Repeat("0", 64))
+	assert.ErrorIs(t, err, ErrUnauthorized, "Wrong secret")
+
+	mock.ExpectQuery("SELECT .* FROM api_keys").WithArgs("revoked").WillReturnRows(sqlmock.NewRows(keyColumns))
+	_, err = store.Authenticate(ctx, tokenPrefix+"revoked_x")
+	assert.ErrorIs(t, err, ErrUnauthorized, "Revoked or unknown key")
+
+	mock.ExpectQuery("UPDATE api_keys SET revoked_at").WithArgs("missing").WillReturnRows(sqlmock.NewRows(keyColumns))
+	_, err = store.Revoke(ctx, "missing")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestToken(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer abc")
+	assert.Equal(t, "abc", Token(r))
+
+	r = httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Api-Key", "def")
+	assert.Equal(t, "def", Token(r))
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // register pgx driver
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+const usage = `usage: nof0 keys [-dsn DSN] <command>
+
+commands:
+  create -name NAME -role reader|ingester|admin   create a key and print it (shown once)
+  list                                          list keys
+  revoke ID                                     revoke a key
+`
+
+// Command runs the keys subcommand with args (after "keys"), e.g. to create
+// the first admin key. dsn is the default for -dsn, normally Postgres.DSN.
+func Command(ctx context.Context, dsn string, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("keys", flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.Usage = func() { fmt.Fprint(w, usage) }
+	fs.StringVar(&dsn, "dsn", dsn, "Postgres DSN (defaults to Postgres.DSN of the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("missing command")
+	}
+	if dsn == "" {
+		return errors.New("no Postgres DSN: set Postgres.DSN in the config or pass -dsn")
+	}
+	store := NewKeyStore(sqlx.NewSqlConn("pgx", dsn))
+
+	switch fs.Arg(0) {
+	case "create":
+		cfs := flag.NewFlagSet("create", flag.ContinueOnError)
+		cfs.SetOutput(w)
+		name := cfs.String("name", "", "what the key is for")
+		role := cfs.String("role", "", "reader, ingester or admin")
+		if err := cfs.Parse(fs.Args()[1:]); err != nil {
+			return err
+		}
+		r, err := ParseRole(*role)
+		if err != nil {
+			return err
+		}
+		k, err := store.Create(ctx, *name, r)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "created %s key %s (%s)\n%s\n", k.Role, k.Id, k.Name, k.Key)
+		return nil
+	case "list":
+		keys, err := store.List(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tROLE\tCREATED\tLAST USED\tREVOKED")
+		for _, k := range keys {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", k.Id, k.Name, k.Role,
+				formatMs(k.CreatedAt), formatMs(k.LastUsedAt), formatMs(k.RevokedAt))
+		}
+		return tw.Flush()
+	case "revoke":
+		if fs.NArg() < 2 {
+			fs.Usage()
+			return errors.New("missing key id")
+		}
+		k, err := store.Revoke(ctx, fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "revoked %s (%s)\n", k.Id, k.Name)
+		return nil
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+}
+
+func formatMs(ms int64) string {
+	if ms == 0 {
+		return "-"
+	}
+	return time.UnixMilli(ms).UTC().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/errs"
+	"nof0-api/internal/types"
+)
+
+// Keys look like nof0_<id>_<secret>: the id finds the row and only the sha256
+// of the random secret is stored, so a leaked table does not leak keys. The
+// secrets are 256-bit random values, so a fast hash is sufficient.
+const tokenPrefix = "nof0_"
+
+var ErrKeyNotFound = errs.NotFound("api key not found")
+
+// KeyStore manages keys in the Postgres api_keys table.
+type KeyStore struct {
+	conn sqlx.SqlConn
+}
+
+func NewKeyStore(conn sqlx.SqlConn) *KeyStore {
+	return &KeyStore{conn: conn}
+}
+
+type keyRow struct {
+	Id         string       `db:"id"`
+	Name       string       `db:"name"`
+	Role       string       `db:"role"`
+	SecretHash string       `db:"secret_hash"`
+	CreatedAt  time.Time    `db:"created_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+}
+
+func (r keyRow) toApiKey() types.ApiKey {
+	k := types.ApiKey{Id: r.Id, Name: r.Name, Role: r.Role, CreatedAt: r.CreatedAt.UnixMilli()}
+	if r.LastUsedAt.Valid {
+		k.LastUsedAt = r.LastUsedAt.Time.UnixMilli()
+	}
+	if r.RevokedAt.Valid {
+		k.RevokedAt = r.RevokedAt.Time.UnixMilli()
+	}
+	return k
+}
+
+// Create stores a new key and returns it with the full key in Key; the key
+// cannot be recovered later.
+func (s *KeyStore) Create(ctx context.Context, name string, role Role) (*types.ApiKey, error) {
+	if _, err := ParseRole(string(role)); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, errs.InvalidArgument("api key name is required")
+	}
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	var row keyRow
+	q := `INSERT INTO api_keys(id, name, role, secret_hash) VALUES ($1,$2,$3,$4)
+          RETURNING id, name, role, secret_hash, created_at, last_used_at, revoked_at`
+	if err := s.conn.QueryRowCtx(ctx, &row, q, id, name, string(role), hashSecret(secret)); err != nil {
+		return nil, err
+	}
+	k := row.toApiKey()
+	k.Key = tokenPrefix + id + "_" + secret
+	return &k, nil
+}
+
+// List returns every key, revoked ones included, oldest first.
+func (s *KeyStore) List(ctx context.Context) ([]types.ApiKey, error) {
+	var rows []keyRow
+	q := `SELECT id, name, role, secret_hash, created_at, last_used_at, revoked_at FROM api_keys ORDER BY created_at, id`
+	if err := s.conn.QueryRowsCtx(ctx, &rows, q); err != nil {
+		return nil, err
+	}
+	keys := make([]types.ApiKey, 0, len(rows))
+	for _, r := range rows {
+		keys = append(keys, r.toApiKey())
+	}
+	return keys, nil
+}
+
+// Revoke disables a key for good and returns it.
+func (s *KeyStore) Revoke(ctx context.Context, id string) (*types.ApiKey, error) {
+	var row keyRow
+	q := `UPDATE api_keys SET revoked_at = coalesce(revoked_at, now()) WHERE id = $1
+          RETURNING id, name, role, secret_hash, created_at, last_used_at, revoked_at`
+	switch err := s.conn.QueryRowCtx(ctx, &row, q, id); {
+	case errors.Is(err, sqlx.ErrNotFound):
+		return nil, ErrKeyNotFound
+	case err != nil:
+		return nil, err
+	}
+	k := row.toApiKey()
+	return &k, nil
+}
+
+// Authenticate looks up an unrevoked key and records when it was last used
+// (at most once a minute).
+func (s *KeyStore) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	id, secret, ok := parseToken(token)
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+	var row keyRow
+	q := `SELECT id, name, role, secret_hash, created_at, last_used_at, revoked_at FROM api_keys
+          WHERE id = $1 AND revoked_at IS NULL`
+	switch err := s.conn.QueryRowCtx(ctx, &row, q, id); {
+	case errors.Is(err, sqlx.ErrNotFound):
+		return nil, ErrUnauthorized
+	case err != nil:
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(row.SecretHash), []byte(hashSecret(secret))) != 1 {
+		return nil, ErrUnauthorized
+	}
+	if _, err := s.conn.ExecCtx(ctx, `UPDATE api_keys SET last_used_at = now()
+        WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')`, id); err != nil {
+		logx.WithContext(ctx).Errorf("api key %s: record use: %v", id, err)
+	}
+	return &Principal{KeyId: row.Id, Name: row.Name, Role: Role(row.Role)}, nil
+}
+
+func parseToken(token string) (id, secret string, ok bool) {
+	rest, ok := strings.CutPrefix(token, tokenPrefix)
+	if !ok {
+		return "", "", false
+	}
+	id, secret, ok = strings.Cut(rest, "_")
+	return id, secret, ok && id != "" && secret != ""
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,171 @@
+// Package cache implements the Redis keyspace from docs/data-architecture.md:
+// read-through payloads with stale-while-revalidate, ingest idempotency
+// marks, and invalidation for writers such as the ingest API and cmd/importer.
+// A nil *redis.Redis turns every operation into a pass-through or no-op.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"github.com/zeromicro/go-zero/core/syncx"
+	"github.com/zeromicro/go-zero/core/threading"
+
+	"nof0-api/internal/metrics"
+)
+
+// refreshLockTTL bounds a background revalidation (seconds).
+const refreshLockTTL = 30
+
+// Policy says how long an entry is fresh and for how long after that it may
+// still be served while one caller reloads it in the background.
+type Policy struct {
+	Fresh time.Duration
+	Stale time.Duration
+}
+
+// Cache wraps Redis; the zero value and New(nil) are usable no-op caches.
+type Cache struct {
+	rds   *redis.Redis
+	group syncx.SingleFlight
+	now   func() time.Time
+}
+
+func New(rds *redis.Redis) *Cache {
+	return &Cache{rds: rds, group: syncx.NewSingleFlight(), now: time.Now}
+}
+
+// Enabled reports whether a Redis is behind the cache.
+func (c *Cache) Enabled() bool {
+	return c != nil && c.rds != nil
+}
+
+// entry is the stored envelope of a Fetch value.
+type entry struct {
+	Value      json.RawMessage `json:"v"`
+	FreshUntil int64           `json:"f"` // epoch ms
+}
+
+// Fetch returns the value cached under key, calling load on a miss. Entries
+// older than p.Fresh but within p.Stale are returned as is while a single
+// caller across instances reloads them in the background. Redis failures
+// degrade to calling load. Callers own the returned value: one shared by
+// concurrent misses is handed out as a copy.
+func Fetch[T any](ctx context.Context, c *Cache, key string, p Policy, load func(context.Context) (T, error)) (T, error) {
+	if !c.Enabled() || p.Fresh <= 0 {
+		return load(ctx)
+	}
+
+	var e entry
+	if s, err := c.rds.GetCtx(ctx, key); err != nil {
+		logx.WithContext(ctx).Errorf("cache get %s: %v", key, err)
+	} else if s != "" && json.Unmarshal([]byte(s), &e) == nil {
+		var v T
+		if json.Unmarshal(e.Value, &v) == nil {
+			if c.now().UnixMilli() >= e.FreshUntil {
+				metrics.CacheRequests.Inc(metrics.CacheStale)
+				revalidate(c, key, p, load)
+			} else {
+				metrics.CacheRequests.Inc(metrics.CacheHit)
+			}
+			return v, nil
+		}
+	}
+	metrics.CacheRequests.Inc(metrics.CacheMiss)
+
+	// Concurrent misses in this process share one load.
+	v, fresh, err := c.group.DoEx(key, func() (interface{}, error) {
+		v, err := load(ctx)
+		if err == nil {
+			store(ctx, c, key, p, v)
+		}
+		return v, err
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if !fresh {
+		return clone(v.(T))
+	}
+	return v.(T), nil
+}
+
+// revalidate reloads key in the background; the nof0:lock:refresh:{key}
+// lock keeps other callers and instances from reloading it too.
+func revalidate[T any](c *Cache, key string, p Policy, load func(context.Context) (T, error)) {
+	threading.GoSafe(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshLockTTL*time.Second)
+		defer cancel()
+		lock := "nof0:lock:refresh:" + key
+		if ok, err := c.rds.SetnxExCtx(ctx, lock, "1", refreshLockTTL); err != nil || !ok {
+			return
+		}
+		defer c.rds.DelCtx(ctx, lock)
+
+		v, err := load(ctx)
+		if err != nil {
+			logx.WithContext(ctx).Errorf("cache revalidate %s: %v", key, err)
+			return
+		}
+		store(ctx, c, key, p, v)
+	})
+}
+
+func clone[T any](v T) (T, error) {
+	var c T
+	b, err := json.Marshal(v)
+	if err == nil {
+		err = json.Unmarshal(b, &c)
+	}
+	return c, err
+}
+
+func store[T any](ctx context.Context, c *Cache, key string, p Policy, v T) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("cache marshal %s: %v", key, err)
+		return
+	}
+	e, _ := json.Marshal(entry{Value: b, FreshUntil: c.now().Add(p.Fresh).UnixMilli()})
+	if err := c.rds.SetexCtx(ctx, key, string(e), seconds(p.Fresh+p.Stale)); err != nil {
+		logx.WithContext(ctx).Errorf("cache set %s: %v", key, err)
+	}
+}
+
+// Invalidate deletes keys so the next read reloads them.
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) error {
+	if !c.Enabled() || len(keys) == 0 {
+		return nil
+	}
+	_, err := c.rds.DelCtx(ctx, keys...)
+	return err
+}
+
+// InvalidateMatch deletes every key matching the glob pattern.
+func (c *Cache) InvalidateMatch(ctx context.Context, pattern string) error {
+	if !c.Enabled() {
+		return nil
+	}
+	var cursor uint64
+	for {
+		keys, next, err := c.rds.ScanCtx(ctx, cursor, pattern, 200)
+		if err != nil {
+			return err
+		}
+		if err := c.Invalidate(ctx, keys...); err != nil {
+			return err
+		}
+		if cursor = next; cursor == 0 {
+			return nil
+		}
+	}
+}
+
+func seconds(d time.Duration) int {
+	s := int((d + time.Second - 1) / time.Second)
+	return max(s, 1)
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+
+	"nof0-api/internal/types"
+)
+
+func newTestCache(t *testing.T) (*Cache, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	return New(redis.New(mr.Addr())), mr
+}
+
+func TestFetchStaleWhileRevalidate(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	var loads atomic.Int32
+	load := func(ctx context.Context) (*types.CryptoPrice, error) {
+		n := loads.Add(1)
+		return &types.CryptoPrice{Symbol: "BTC", Price: float64(n)}, nil
+	}
+	p := Policy{Fresh: 10 * time.Second, Stale: time.Minute}
+
+	v, err := Fetch(ctx, c, CryptoPricesKey, p, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, v.Price)
+	assert.Equal(t, 70*time.Second, mr.TTL(CryptoPricesKey), "Kept for fresh + stale")
+
+	v, err = Fetch(ctx, c, CryptoPricesKey, p, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, v.Price, "Fresh hit")
+	assert.EqualValues(t, 1, loads.Load())
+
+	now = now.Add(15 * time.Second)
+	v, err = Fetch(ctx, c, CryptoPricesKey, p, load)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, v.Price, "Stale value is served immediately")
+	assert.Eventually(t, func() bool {
+		v, err := Fetch(ctx, c, CryptoPricesKey, p, load)
+		return err == nil && v.Price == 2
+	}, time.Second, 10*time.Millisecond, "and replaced in the background")
+	assert.False(t, mr.Exists("nof0:lock:refresh:"+CryptoPricesKey))
+
+	require.NoError(t, c.Invalidate(ctx, CryptoPricesKey))
+	v, err = Fetch(ctx, c, CryptoPricesKey, p, load)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, v.Price, "Invalidated keys reload synchronously")
+}
+
+func TestFetchSharedMissIsCopied(t *testing.T) {
+	c, _ := newTestCache(t)
+	started, release := make(chan struct{}), make(chan struct{})
+	load := func(ctx context.Context) (*types.CryptoPrice, error) {
+		close(started)
+		<-release
+		return &types.CryptoPrice{Symbol: "BTC", Price: 1}, nil
+	}
+	p := Policy{Fresh: time.Minute}
+
+	results := make(chan *types.CryptoPrice, 2)
+	fetch := func() {
+		v, err := Fetch(context.Background(), c, CryptoPricesKey, p, load)
+		assert.NoError(t, err)
+		results <- v
+	}
+	go fetch()
+	<-started
+	go fetch()
+	time.Sleep(20 * time.Millisecond) // let the second caller join the load
+	close(release)
+
+	a, b := <-results, <-results
+	assert.Equal(t, a, b)
+	assert.NotSame(t, a, b, "Callers may modify what they get")
+}
+
+func TestFetchWithoutRedis(t *testing.T) {
+	var c *Cache
+	calls := 0
+	load := func(ctx context.Context) (int, error) { calls++; return calls, nil }
+	for i := 1; i <= 2; i++ {
+		v, err := Fetch(context.Background(), c, "k", Policy{Fresh: time.Minute}, load)
+		require.NoError(t, err)
+		assert.Equal(t, i, v)
+	}
+
+	_, err := Fetch(context.Background(), New(nil), "k", Policy{Fresh: time.Minute}, func(ctx context.Context) (int, error) {
+		return 0, errors.New("db down")
+	})
+	assert.EqualError(t, err, "db down")
+	assert.NoError(t, c.InvalidateArena(context.Background(), "default"))
+}
+
+func TestKeyspace(t *testing.T) {
+	c, mr := newTestCache(t)
+	ctx := context.Background()
+
+	fresh, err := c.MarkIngested(ctx, "t1", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, fresh)
+	fresh, err = c.MarkIngested(ctx, "t1", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, fresh)
+
+	for _, key := range []string{
+		CryptoPricesKey, ArenaPositionsKey("s1"), TradesKey("s1"), AccountTotalsKey("s1"), SinceInceptionKey("s1"),
+		LeaderboardCacheKey("s1"), ConversationsKey("s1"), ArenaAnalyticsKey("s1"), AnalyticsKey("s1", "a"),
+	} {
+		mr.Set(key, "{}")
+	}
+	mr.Set(TradesKey("s2"), "other arena")
+	require.NoError(t, c.InvalidateArena(ctx, "s1"))
+	for _, key := range mr.Keys() {
+		assert.NotContains(t, key, ":s1", "%s should be invalidated", key)
+	}
+	assert.False(t, mr.Exists(CryptoPricesKey))
+	assert.True(t, mr.Exists(TradesKey("s2")))
+	assert.True(t, mr.Exists(IngestTradeKey("t1")))
+}
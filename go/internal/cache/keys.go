@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Keyspace (see docs/data-architecture.md). Arena-scoped keys carry the arena
+// id before the model id.
+const (
+	CryptoPricesKey = "nof0:crypto_prices"
+)
+
+// ArenaPositionsKey caches the whole /positions payload of an arena.
+func ArenaPositionsKey(arena string) string { return "nof0:positions:" + arena }
+
+// TradesKey caches the /trades payload of an arena.
+func TradesKey(arena string) string { return "nof0:trades:" + arena }
+
+// LeaderboardCacheKey caches the /leaderboard payload of an arena.
+func LeaderboardCacheKey(arena string) string { return "nof0:leaderboard:cache:" + arena }
+
+// AccountTotalsKey caches the /account-totals payload of an arena.
+func AccountTotalsKey(arena string) string { return "nof0:account_totals:" + arena }
+
+// SinceInceptionKey caches the /since-inception-values payload of an arena.
+func SinceInceptionKey(arena string) string { return "nof0:since_inception:" + arena }
+
+// ConversationsKey caches the /conversations payload of an arena.
+func ConversationsKey(arena string) string { return "nof0:conversations:" + arena }
+
+// ArenaAnalyticsKey caches the /analytics payload of an arena; AnalyticsKey
+// caches one model's.
+func ArenaAnalyticsKey(arena string) string { return "nof0:analytics:" + arena }
+
+func AnalyticsKey(arena, modelId string) string {
+	return "nof0:analytics:" + arena + ":" + modelId
+}
+
+// IngestTradeKey marks a trade id as ingested (idempotency).
+func IngestTradeKey(tradeId string) string { return "nof0:ingest:trade:" + tradeId }
+
+// MarkIngested records tradeId as ingested for ttl and reports whether it
+// was new. Without Redis every trade is new.
+func (c *Cache) MarkIngested(ctx context.Context, tradeId string, ttl time.Duration) (bool, error) {
+	if !c.Enabled() {
+		return true, nil
+	}
+	return c.rds.SetnxExCtx(ctx, IngestTradeKey(tradeId), "1", seconds(ttl))
+}
+
+// InvalidateArena drops every cached payload of an arena plus the price map,
+// e.g. after a bulk import. Ingest idempotency marks stay.
+func (c *Cache) InvalidateArena(ctx context.Context, arena string) error {
+	if err := c.Invalidate(ctx, CryptoPricesKey, ArenaPositionsKey(arena), TradesKey(arena), AccountTotalsKey(arena),
+		SinceInceptionKey(arena), LeaderboardCacheKey(arena), ConversationsKey(arena), ArenaAnalyticsKey(arena)); err != nil {
+		return err
+	}
+	return c.InvalidateMatch(ctx, AnalyticsKey(arena, "*"))
+}
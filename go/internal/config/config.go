@@ -10,12 +10,121 @@ type PostgresConf struct {
 	DSN     string `json:",optional"`
 	MaxOpen int    `json:",default=10"`
 	MaxIdle int    `json:",default=5"`
+	// AutoMigrate applies pending embedded migrations on start; otherwise run
+	// `nof0 migrate up` (see internal/migrate).
+	AutoMigrate bool `json:",optional"`
 }
 
 type CacheTTL struct {
 	Short  int `json:",default=10"` // seconds
 	Medium int `json:",default=60"`
 	Long   int `json:",default=300"`
+	// Stale is how long an expired entry is still served while one caller
+	// reloads it (stale-while-revalidate).
+	Stale int `json:",default=60"`
+}
+
+type RegistryConf struct {
+	// File holds the model registry as YAML or JSON. It is the registry when
+	// Postgres is not configured and the read fallback when it is.
+	File string `json:",default=etc/models.yaml"`
+}
+
+// JobConf schedules one built-in job (see internal/jobs). Schedule is a
+// five-field cron expression in UTC ("*/5 * * * *"), @hourly/@daily/@weekly
+// or "@every 30s".
+type JobConf struct {
+	Name     string
+	Schedule string
+	Timeout  int  `json:",default=60"` // seconds; also bounds the lock TTL
+	Disabled bool `json:",optional"`
+}
+
+// IngestConf tunes POST /api/ingest/* (ingester keys, see AuthConf).
+type IngestConf struct {
+	DedupeTTL int `json:",default=86400"` // seconds a trade id is remembered
+}
+
+// ApiKeyConf is a key defined in the config, e.g. in file mode or to
+// bootstrap; keys created at /api/admin/keys live in Postgres.
+type ApiKeyConf struct {
+	Name string
+	Role string `json:",options=reader|ingester|admin"`
+	Key  string
+}
+
+// AuthConf holds the config API keys. Ingest, admin and model write routes
+// require a key ("Authorization: Bearer <key>" or "X-Api-Key: <key>");
+// public GET routes do not.
+type AuthConf struct {
+	Keys []ApiKeyConf `json:",optional"`
+}
+
+// RateLimitRule limits requests whose path starts with Path (the longest
+// matching rule wins) to Rate per second per client, in bursts of up to
+// Burst. Rate 0 leaves the path unlimited.
+type RateLimitRule struct {
+	Path  string
+	Rate  float64
+	Burst int `json:",optional"` // default: Rate rounded up
+}
+
+// RateLimitConf is a token bucket per client and rule. Clients are told
+// apart by API key when they send one, by IP otherwise. Paths without a rule
+// use Rate and Burst.
+type RateLimitConf struct {
+	Disabled bool            `json:",optional"`
+	Rate     float64         `json:",default=20"`
+	Burst    int             `json:",default=40"`
+	Routes   []RateLimitRule `json:",optional"`
+	// TrustedProxies lists the addresses or CIDRs of proxies whose
+	// X-Forwarded-For is believed; other peers are limited by their own address.
+	TrustedProxies []string `json:",optional"`
+}
+
+// ResponseCacheConf caches 200 responses of the public GET routes in memory
+// and answers If-None-Match with 304.
+type ResponseCacheConf struct {
+	TTL        int `json:",default=5"` // seconds; 0 keeps ETags but caches nothing
+	MaxEntries int `json:",default=1000"`
+}
+
+// UpstreamConf mirrors an API with nof1.ai's endpoints (see internal/upstream)
+// into the DataPath/Arena directory, which is then served like any arena.
+type UpstreamConf struct {
+	// BaseURL of the API, e.g. https://nof1.ai/api; empty disables the mirror.
+	BaseURL  string `json:",optional"`
+	Arena    string `json:",default=nof1"`
+	Interval int    `json:",default=15,range=[1:]"` // seconds between syncs, at least 1
+	Timeout  int    `json:",default=5000"`          // milliseconds per attempt
+	Retries  int    `json:",default=2"`
+	Backoff  int    `json:",default=250"` // milliseconds before the first retry, doubling
+	// The breaker opens after BreakerFailures failed requests in a row and
+	// lets a probe through after BreakerCooldown seconds.
+	BreakerFailures int `json:",default=5"`
+	BreakerCooldown int `json:",default=30"`
+}
+
+// SnapshotConf records every arena's data into Path/<arena>/<id>/ every
+// Interval seconds (see internal/snapshot); unchanged data records nothing.
+type SnapshotConf struct {
+	Path     string `json:",optional"`               // empty disables recording and ?snapshot=
+	Interval int    `json:",default=300,range=[1:]"` // at least 1
+	Keep     int    `json:",default=288"`            // newest snapshots kept per arena; 0 keeps all
+	MaxAge   int    `json:",default=7"`              // days a snapshot is kept; 0 keeps all
+}
+
+// FreshnessConf sets, per resource, how many seconds after its dataAsOf
+// (newest record or file time) a response is flagged stale; 0 never flags it.
+type FreshnessConf struct {
+	Prices         int `json:",default=60"`
+	AccountTotals  int `json:",default=300"`
+	Positions      int `json:",default=300"`
+	Trades         int `json:",default=3600"`
+	SinceInception int `json:",default=3600"`
+	Leaderboard    int `json:",default=3600"`
+	Analytics      int `json:",default=3600"`
+	Conversations  int `json:",default=3600"`
 }
 
 type Config struct {
@@ -24,4 +133,20 @@ type Config struct {
 	Postgres PostgresConf    `json:",optional"`
 	Redis    redis.RedisConf `json:",optional"`
 	TTL      CacheTTL        `json:",optional"`
+	Registry RegistryConf    `json:",optional"`
+	// Jobs run in-process when Postgres is configured; with Redis each run
+	// is single-flight across instances.
+	Jobs   []JobConf  `json:",optional"`
+	Ingest IngestConf `json:",optional"`
+	Auth   AuthConf   `json:",optional"`
+	// RateLimit applies to every route; ResponseCache to the public GETs.
+	RateLimit     RateLimitConf     `json:",optional"`
+	ResponseCache ResponseCacheConf `json:",optional"`
+	Upstream      UpstreamConf      `json:",optional"`
+	Snapshots     SnapshotConf      `json:",optional"`
+	Freshness     FreshnessConf     `json:",optional"`
+	// DefaultArena names the arena (a DataPath subdirectory) served when a
+	// request has no ?arena=; empty prefers DataPath itself, then the last season.
+	// Startup fails when the named arena has no data.
+	DefaultArena string `json:",optional"`
 }
@@ -0,0 +1,200 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"nof0-api/internal/types"
+)
+
+// DefaultArena is the id of the arena served straight from the DataPath root.
+const DefaultArena = "default"
+
+// Arena lifecycle states reported by /api/arenas.
+const (
+	ArenaStatusLive     = "live"
+	ArenaStatusFinished = "finished"
+)
+
+// arenaMetaFile optionally describes an arena directory (name, dates).
+const arenaMetaFile = "arena.json"
+
+type arenaMeta struct {
+	Name      string `json:"name"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+}
+
+// ArenaSet holds one DataLoader per arena (competition/season) found under a
+// data root. JSON files directly in the root form the "default" arena; every
+// subdirectory that contains JSON files is an arena named after the directory.
+type ArenaSet struct {
+	defaultId  string
+	loaders    map[string]*DataLoader
+	orderedIds []string
+}
+
+// NewArenaSet scans root for arenas. defaultId picks the arena served when a
+// request does not name one; when empty, the root arena is preferred, then
+// the last subdirectory in lexical order (e.g. season-2 over season-1). A
+// defaultId naming no arena under root is an error.
+func NewArenaSet(root, defaultId string) (*ArenaSet, error) {
+	s := &ArenaSet{loaders: map[string]*DataLoader{}}
+	if hasJSONFiles(root) {
+		s.add(DefaultArena, root)
+	}
+	entries, _ := os.ReadDir(root)
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		if hasJSONFiles(dir) {
+			s.add(e.Name(), dir)
+		}
+	}
+
+	switch {
+	case defaultId != "" && defaultId != DefaultArena && s.loaders[defaultId] == nil:
+		return nil, fmt.Errorf("default arena %q: no data under %s", defaultId, filepath.Join(root, defaultId))
+	case defaultId != "":
+		s.defaultId = defaultId
+	case s.loaders[DefaultArena] != nil:
+		s.defaultId = DefaultArena
+	case len(s.orderedIds) > 0:
+		s.defaultId = s.orderedIds[len(s.orderedIds)-1]
+	default:
+		s.defaultId = DefaultArena
+	}
+	// Keep serving the root even when it has no data yet, so a missing
+	// directory surfaces as a load error rather than an unknown arena.
+	if s.loaders[s.defaultId] == nil {
+		s.add(s.defaultId, root)
+	}
+	return s, nil
+}
+
+func (s *ArenaSet) add(id, dir string) {
+	s.loaders[id] = NewDataLoader(dir)
+	s.orderedIds = append(s.orderedIds, id)
+	sort.Strings(s.orderedIds)
+}
+
+// DefaultId returns the arena served when none is requested.
+func (s *ArenaSet) DefaultId() string {
+	return s.defaultId
+}
+
+// Loader returns the loader for arena id; an empty id means the default arena.
+func (s *ArenaSet) Loader(id string) (*DataLoader, bool) {
+	if id == "" {
+		id = s.defaultId
+	}
+	dl, ok := s.loaders[id]
+	return dl, ok
+}
+
+// Ids lists the arena ids in lexical order.
+func (s *ArenaSet) Ids() []string {
+	return append([]string(nil), s.orderedIds...)
+}
+
+// List describes every arena with its dates and participants.
+func (s *ArenaSet) List() []types.ArenaInfo {
+	out := make([]types.ArenaInfo, 0, len(s.orderedIds))
+	for _, id := range s.orderedIds {
+		out = append(out, s.describe(id))
+	}
+	return out
+}
+
+func (s *ArenaSet) describe(id string) types.ArenaInfo {
+	dl := s.loaders[id]
+	info := types.ArenaInfo{
+		Id:           id,
+		Name:         id,
+		Participants: []string{},
+		Default:      id == s.defaultId,
+	}
+
+	var meta arenaMeta
+	if err := dl.loadJSONFile(arenaMetaFile, &meta); err == nil {
+		if meta.Name != "" {
+			info.Name = meta.Name
+		}
+		info.StartTime = meta.StartTime
+		info.EndTime = meta.EndTime
+	}
+
+	participants := map[string]struct{}{}
+	if resp, err := dl.LoadSinceInception(); err == nil {
+		for _, v := range resp.SinceInceptionValues {
+			participants[v.ModelId] = struct{}{}
+			if ms := ToMillis(v.InceptionDate); meta.StartTime == 0 && ms > 0 && (info.StartTime == 0 || ms < info.StartTime) {
+				info.StartTime = ms
+			}
+		}
+	}
+	if resp, err := dl.LoadLeaderboard(); err == nil {
+		for _, e := range resp.Leaderboard {
+			participants[e.Id] = struct{}{}
+		}
+	}
+	for modelId := range participants {
+		if modelId != "" {
+			info.Participants = append(info.Participants, modelId)
+		}
+	}
+	sort.Strings(info.Participants)
+
+	info.Status = ArenaStatusLive
+	if info.EndTime > 0 {
+		info.Status = ArenaStatusFinished
+	}
+	return info
+}
+
+// FileTimes returns the modification time of every data file by arena.
+func (s *ArenaSet) FileTimes() map[string]map[string]time.Time {
+	out := make(map[string]map[string]time.Time, len(s.orderedIds))
+	for _, id := range s.orderedIds {
+		out[id] = s.loaders[id].FileTimes()
+	}
+	return out
+}
+
+func hasJSONFiles(dir string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	return len(matches) > 0
+}
+
+// secondsCutoff separates epoch seconds from milliseconds: 1e12 ms is 2001,
+// 1e12 s is far in the future.
+const secondsCutoff = 1e12
+
+// ToMillis normalizes a timestamp that may be in seconds or milliseconds;
+// values below 1e12 are treated as seconds.
+func ToMillis(ts float64) int64 {
+	if ts < secondsCutoff {
+		return int64(ts * 1000)
+	}
+	return int64(ts)
+}
+
+type arenaCtxKey struct{}
+
+// WithArena stores the requested arena id in ctx.
+func WithArena(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, arenaCtxKey{}, id)
+}
+
+// ArenaFromContext returns the arena id stored by WithArena, or "".
+func ArenaFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(arenaCtxKey{}).(string)
+	return id
+}
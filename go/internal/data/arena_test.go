@@ -0,0 +1,83 @@
+package data
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeArenaFile(t *testing.T, dir, name, body string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644))
+}
+
+func TestArenaSetRootIsDefault(t *testing.T) {
+	arenas, err := NewArenaSet(testDataPath, "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultArena, arenas.DefaultId())
+
+	dl, ok := arenas.Loader("")
+	require.True(t, ok)
+	resp, err := dl.LoadLeaderboard()
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Leaderboard)
+
+	_, ok = arenas.Loader("no-such-arena")
+	assert.False(t, ok)
+}
+
+func TestArenaSetSeasons(t *testing.T) {
+	root := t.TempDir()
+	writeArenaFile(t, filepath.Join(root, "season-1"), "leaderboard.json",
+		`{"leaderboard":[{"id":"gpt-5","equity":9000},{"id":"grok-4","equity":12000}]}`)
+	writeArenaFile(t, filepath.Join(root, "season-1"), "arena.json",
+		`{"name":"Season 1","start_time":1760000000000,"end_time":1761000000000}`)
+	writeArenaFile(t, filepath.Join(root, "season-2"), "since-inception-values.json",
+		`{"sinceInceptionValues":[{"id":"a","model_id":"qwen3-max","nav_since_inception":10000,"inception_date":1762000000}]}`)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "empty"), 0o755))
+
+	arenas, err := NewArenaSet(root, "")
+	require.NoError(t, err)
+	assert.Equal(t, "season-2", arenas.DefaultId(), "Latest season should be the default")
+
+	list := arenas.List()
+	require.Len(t, list, 2, "Directories without data are not arenas")
+
+	s1, s2 := list[0], list[1]
+	assert.Equal(t, "season-1", s1.Id)
+	assert.Equal(t, "Season 1", s1.Name)
+	assert.Equal(t, ArenaStatusFinished, s1.Status)
+	assert.Equal(t, []string{"gpt-5", "grok-4"}, s1.Participants)
+	assert.False(t, s1.Default)
+
+	assert.Equal(t, "season-2", s2.Id)
+	assert.Equal(t, ArenaStatusLive, s2.Status)
+	assert.Equal(t, int64(1762000000000), s2.StartTime, "Start should fall back to earliest inception")
+	assert.Equal(t, []string{"qwen3-max"}, s2.Participants)
+	assert.True(t, s2.Default)
+
+	dl, ok := arenas.Loader("season-1")
+	require.True(t, ok)
+	resp, err := dl.LoadLeaderboard()
+	require.NoError(t, err)
+	assert.Len(t, resp.Leaderboard, 2)
+
+	pinned, err := NewArenaSet(root, "season-1")
+	require.NoError(t, err)
+	assert.Equal(t, "season-1", pinned.DefaultId())
+
+	_, err = NewArenaSet(root, "season-3")
+	assert.Error(t, err, "A configured default arena must exist")
+	_, err = NewArenaSet(root, "empty")
+	assert.Error(t, err, "A configured default arena must have data")
+}
+
+func TestArenaContext(t *testing.T) {
+	assert.Equal(t, "", ArenaFromContext(context.Background()))
+	assert.Equal(t, "season-1", ArenaFromContext(WithArena(context.Background(), "season-1")))
+}
@@ -0,0 +1,287 @@
+package data
+
+import (
+	"encoding/json"
+	"html"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"nof0-api/internal/types"
+)
+
+const (
+	conversationsFile = "conversations.json"
+
+	// snippetRadius is how many runes of context surround the first match.
+	snippetRadius = 80
+	markOpen      = "<mark>"
+	markClose     = "</mark>"
+
+	// HeadlineStart and HeadlineStop delimit matches in Postgres ts_headline
+	// output; MarkHeadline turns them into <mark></mark>.
+	HeadlineStart = "\x01"
+	HeadlineStop  = "\x02"
+)
+
+var headlineMarks = strings.NewReplacer(HeadlineStart, markOpen, HeadlineStop, markClose)
+
+// ConversationIndex is an in-memory inverted index over conversation
+// messages, used for search when no database is configured.
+type ConversationIndex struct {
+	docs     []types.ConversationHit
+	postings map[string][]posting
+}
+
+type posting struct {
+	doc int
+	tf  int
+}
+
+// NewConversationIndex indexes every message. Conversation and message ids
+// are 1-based positions in the source file.
+func NewConversationIndex(conversations []types.Conversation) *ConversationIndex {
+	ix := &ConversationIndex{postings: map[string][]posting{}}
+	for ci, c := range conversations {
+		for mi, m := range c.Messages {
+			doc := len(ix.docs)
+			ix.docs = append(ix.docs, types.ConversationHit{
+				ModelId:        c.ModelId,
+				ConversationId: int64(ci + 1),
+				MessageId:      int64(mi + 1),
+				Role:           m.Role,
+				Timestamp:      messageMillis(m.Timestamp),
+				Content:        m.Content,
+			})
+			tf := map[string]int{}
+			for _, tok := range tokenize(m.Content) {
+				tf[tok.term]++
+			}
+			for term, n := range tf {
+				ix.postings[term] = append(ix.postings[term], posting{doc: doc, tf: n})
+			}
+		}
+	}
+	return ix
+}
+
+// Search returns the requested page of messages matching every filter and,
+// when req.Q is set, containing every query term. Matches are ranked by
+// tf-idf, otherwise newest first. From/To are epoch milliseconds.
+func (ix *ConversationIndex) Search(req *types.ConversationSearchRequest) (hits []types.ConversationHit, total int) {
+	terms := queryTerms(req.Q)
+	scores := map[int]float64{}
+	var candidates []int
+	if len(terms) > 0 {
+		for i, term := range terms {
+			list := ix.postings[term]
+			idf := math.Log(1 + float64(len(ix.docs))/float64(len(list)+1))
+			next := map[int]float64{}
+			for _, p := range list {
+				if _, ok := scores[p.doc]; ok || i == 0 {
+					next[p.doc] = scores[p.doc] + float64(p.tf)*idf
+				}
+			}
+			scores = next
+		}
+		for doc := range scores {
+			candidates = append(candidates, doc)
+		}
+	} else {
+		candidates = make([]int, len(ix.docs))
+		for i := range candidates {
+			candidates[i] = i
+		}
+	}
+
+	matched := candidates[:0]
+	for _, doc := range candidates {
+		if ix.matches(doc, req) {
+			matched = append(matched, doc)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if scores[a] != scores[b] {
+			return scores[a] > scores[b]
+		}
+		if ix.docs[a].Timestamp != ix.docs[b].Timestamp {
+			return ix.docs[a].Timestamp > ix.docs[b].Timestamp
+		}
+		return a < b
+	})
+
+	total = len(matched)
+	hits = []types.ConversationHit{}
+	for _, doc := range paginate(matched, req.Offset, req.Limit) {
+		hit := ix.docs[doc]
+		hit.Score = scores[doc]
+		hit.Snippet = Snippet(hit.Content, terms)
+		hits = append(hits, hit)
+	}
+	return hits, total
+}
+
+func (ix *ConversationIndex) matches(doc int, req *types.ConversationSearchRequest) bool {
+	d := ix.docs[doc]
+	switch {
+	case req.ModelId != "" && d.ModelId != req.ModelId:
+		return false
+	case req.Role != "" && d.Role != req.Role:
+		return false
+	case req.From > 0 && d.Timestamp < req.From:
+		return false
+	case req.To > 0 && d.Timestamp > req.To:
+		return false
+	}
+	return true
+}
+
+func paginate(docs []int, offset, limit int) []int {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(docs) {
+		return nil
+	}
+	docs = docs[offset:]
+	if limit > 0 && limit < len(docs) {
+		docs = docs[:limit]
+	}
+	return docs
+}
+
+// ConversationIndex returns the search index for this data directory,
+// rebuilding it when conversations.json changes.
+func (dl *DataLoader) ConversationIndex() (*ConversationIndex, error) {
+	v, err := dl.memo("conversationIndex", []string{conversationsFile}, func() (interface{}, error) {
+		resp, err := dl.LoadConversations()
+		if err != nil {
+			return nil, err
+		}
+		return NewConversationIndex(resp.Conversations), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*ConversationIndex), nil
+}
+
+type token struct {
+	term       string
+	start, end int // rune offsets
+}
+
+// tokenize splits text into lowercase letter/digit runs.
+func tokenize(text string) []token {
+	var out []token
+	runes := []rune(text)
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		word := i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]))
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			out = append(out, token{term: strings.ToLower(string(runes[start:i])), start: start, end: i})
+			start = -1
+		}
+	}
+	return out
+}
+
+func queryTerms(q string) []string {
+	seen := map[string]struct{}{}
+	var terms []string
+	for _, tok := range tokenize(q) {
+		if _, ok := seen[tok.term]; !ok {
+			seen[tok.term] = struct{}{}
+			terms = append(terms, tok.term)
+		}
+	}
+	return terms
+}
+
+// Snippet returns an excerpt of content around the first occurrence of any
+// term with every occurrence wrapped in <mark></mark>. Without terms it is
+// the beginning of content. The text is HTML-escaped, so only the marks are
+// markup.
+func Snippet(content string, terms []string) string {
+	runes := []rune(content)
+	want := map[string]struct{}{}
+	for _, t := range terms {
+		want[t] = struct{}{}
+	}
+	var spans []token
+	for _, tok := range tokenize(content) {
+		if _, ok := want[tok.term]; ok {
+			spans = append(spans, tok)
+		}
+	}
+
+	from, to := 0, len(runes)
+	if len(spans) > 0 {
+		from = spans[0].start - snippetRadius
+		to = spans[0].end + snippetRadius
+	} else {
+		to = 2 * snippetRadius
+	}
+	if from < 0 {
+		from = 0
+	}
+	if to > len(runes) {
+		to = len(runes)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, s := range spans {
+		if s.start < from || s.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:s.start])))
+		b.WriteString(markOpen)
+		b.WriteString(html.EscapeString(string(runes[s.start:s.end])))
+		b.WriteString(markClose)
+		pos = s.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// MarkHeadline HTML-escapes a ts_headline excerpt delimited with
+// HeadlineStart/HeadlineStop and wraps the matches in <mark></mark>, giving
+// the same markup as Snippet.
+func MarkHeadline(s string) string {
+	return headlineMarks.Replace(html.EscapeString(s))
+}
+
+// messageMillis converts a message timestamp (seconds or ms, number or
+// string, or RFC3339) to epoch milliseconds; unknown shapes yield 0.
+func messageMillis(v interface{}) int64 {
+	switch t := v.(type) {
+	case float64:
+		return ToMillis(t)
+	case json.Number:
+		if f, err := t.Float64(); err == nil {
+			return ToMillis(f)
+		}
+	case string:
+		if f, err := strconv.ParseFloat(t, 64); err == nil {
+			return ToMillis(f)
+		}
+		if ts, err := time.Parse(time.RFC3339, t); err == nil {
+			return ts.UnixMilli()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,112 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/types"
+)
+
+func TestConversationIndexSearch(t *testing.T) {
+	index, err := NewDataLoader(testDataPath).ConversationIndex()
+	require.NoError(t, err)
+
+	hits, total := index.Search(&types.ConversationSearchRequest{Q: "MACD"})
+	assert.Equal(t, 5, total, "Every model but one reasons about MACD")
+	require.Len(t, hits, 5)
+	for i, hit := range hits {
+		assert.Equal(t, "assistant", hit.Role)
+		assert.Contains(t, strings.ToLower(hit.Content), "macd")
+		assert.Contains(t, hit.Snippet, "<mark>MACD</mark>")
+		assert.Greater(t, hit.Score, 0.0)
+		assert.Greater(t, hit.Timestamp, int64(1e12), "Timestamps should be in ms")
+		if i > 0 {
+			assert.GreaterOrEqual(t, hits[i-1].Score, hit.Score, "Hits should be ranked")
+		}
+	}
+
+	hits, total = index.Search(&types.ConversationSearchRequest{Q: "macd", ModelId: "gpt-5"})
+	require.Equal(t, 1, total)
+	assert.Equal(t, "gpt-5", hits[0].ModelId)
+	assert.Equal(t, int64(3), hits[0].MessageId)
+
+	_, total = index.Search(&types.ConversationSearchRequest{Q: "macd zzzunknown"})
+	assert.Zero(t, total, "All terms must match")
+}
+
+func TestConversationIndexFiltersAndPaging(t *testing.T) {
+	index := NewConversationIndex([]types.Conversation{
+		{ModelId: "a", Messages: []types.ConversationMessage{
+			{Role: "system", Content: "rules", Timestamp: 1000.0},
+			{Role: "assistant", Content: "buy BTC", Timestamp: 2000.0},
+		}},
+		{ModelId: "b", Messages: []types.ConversationMessage{
+			{Role: "assistant", Content: "sell BTC", Timestamp: "3000"},
+		}},
+	})
+
+	hits, total := index.Search(&types.ConversationSearchRequest{Role: "assistant", Limit: 1})
+	assert.Equal(t, 2, total)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "b", hits[0].ModelId, "Newest first without a query")
+
+	hits, _ = index.Search(&types.ConversationSearchRequest{Role: "assistant", Limit: 1, Offset: 1})
+	require.Len(t, hits, 1)
+	assert.Equal(t, "a", hits[0].ModelId)
+
+	hits, total = index.Search(&types.ConversationSearchRequest{From: 1500000, To: 2500000})
+	require.Equal(t, 1, total)
+	assert.Equal(t, "buy BTC", hits[0].Content)
+
+	hits, total = index.Search(&types.ConversationSearchRequest{Offset: 10})
+	assert.Equal(t, 3, total)
+	assert.Empty(t, hits)
+}
+
+func TestSnippet(t *testing.T) {
+	assert.Equal(t, "MACD <mark>crossed</mark> up, price <mark>crossed</mark> EMA",
+		Snippet("MACD crossed up, price crossed EMA", []string{"crossed"}))
+
+	long := strings.Repeat("a ", 100) + "target hit" + strings.Repeat(" b", 100)
+	s := Snippet(long, []string{"target"})
+	assert.True(t, strings.HasPrefix(s, "…"))
+	assert.True(t, strings.HasSuffix(s, "…"))
+	assert.Contains(t, s, "<mark>target</mark> hit")
+
+	assert.Equal(t, 2*snippetRadius+1, len([]rune(Snippet(long, nil))), "Leading excerpt plus an ellipsis")
+
+	assert.Equal(t, "&lt;b&gt;<mark>buy</mark>&lt;/b&gt; &amp; hold",
+		Snippet("<b>buy</b> & hold", []string{"buy"}), "Content is escaped, marks are not")
+	assert.Equal(t, "&lt;i&gt;<mark>buy</mark> &amp; hold",
+		MarkHeadline("<i>"+HeadlineStart+"buy"+HeadlineStop+" & hold"))
+}
+
+func TestConversationIndexReloadsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, conversationsFile)
+	writeArenaFile(t, dir, conversationsFile, `{"conversations":[{"model_id":"a","messages":[{"role":"user","content":"alpha"}]}]}`)
+	dl := NewDataLoader(dir)
+
+	index, err := dl.ConversationIndex()
+	require.NoError(t, err)
+	_, total := index.Search(&types.ConversationSearchRequest{Q: "alpha"})
+	assert.Equal(t, 1, total)
+
+	again, err := dl.ConversationIndex()
+	require.NoError(t, err)
+	assert.Same(t, index, again, "Index should be cached while the file is unchanged")
+
+	writeArenaFile(t, dir, conversationsFile, `{"conversations":[{"model_id":"a","messages":[{"role":"user","content":"beta"}]}]}`)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	index, err = dl.ConversationIndex()
+	require.NoError(t, err)
+	_, total = index.Search(&types.ConversationSearchRequest{Q: "beta"})
+	assert.Equal(t, 1, total)
+}
@@ -0,0 +1,101 @@
+package data
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"nof0-api/internal/types"
+)
+
+// Resources whose freshness is reported (dataAsOf and stale on responses,
+// /api/status); see config.FreshnessConf for their staleness thresholds.
+const (
+	ResourcePrices         = "prices"
+	ResourceAccountTotals  = "account_totals"
+	ResourcePositions      = "positions"
+	ResourceTrades         = "trades"
+	ResourceSinceInception = "since_inception"
+	ResourceLeaderboard    = "leaderboard"
+	ResourceAnalytics      = "analytics"
+	ResourceConversations  = "conversations"
+)
+
+// dataAsOf is the epoch ms time of the newest record, or when the records
+// carry no time, the modification time of file (0 if it has none).
+func (dl *DataLoader) dataAsOf(newest int64, file string) int64 {
+	if newest > 0 {
+		return newest
+	}
+	path := filepath.Join(dl.dataPath, file)
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		info, err = os.Stat(path + ".gz")
+	}
+	if err != nil {
+		return 0
+	}
+	return info.ModTime().UnixMilli()
+}
+
+// latest returns the newest of ts (epoch seconds or ms) as epoch ms.
+func latest(ts ...float64) int64 {
+	var newest int64
+	for _, t := range ts {
+		newest = max(newest, ToMillis(t))
+	}
+	return newest
+}
+
+func pricesAsOf(r *types.CryptoPricesResponse) int64 {
+	var newest int64
+	for _, p := range r.Prices {
+		newest = max(newest, latest(float64(p.Timestamp)))
+	}
+	return newest
+}
+
+func accountTotalsAsOf(r *types.AccountTotalsResponse) int64 {
+	var newest int64
+	for _, a := range r.AccountTotals {
+		newest = max(newest, latest(a.Timestamp))
+	}
+	return newest
+}
+
+func tradesAsOf(trades []types.Trade) int64 {
+	var newest int64
+	for _, t := range trades {
+		newest = max(newest, latest(t.EntryTime, t.ExitTime))
+	}
+	return newest
+}
+
+func analyticsAsOf(analytics ...types.ModelAnalytics) int64 {
+	var newest int64
+	for _, a := range analytics {
+		newest = max(newest, latest(a.UpdatedAt))
+	}
+	return newest
+}
+
+// conversationsAsOf reads message timestamps, which may be numbers or
+// numeric strings.
+func conversationsAsOf(conversations []types.Conversation) int64 {
+	var newest int64
+	for _, c := range conversations {
+		for _, m := range c.Messages {
+			var ts float64
+			switch v := m.Timestamp.(type) {
+			case float64:
+				ts = v
+			case string:
+				ts, _ = strconv.ParseFloat(v, 64)
+			}
+			newest = max(newest, latest(ts))
+		}
+	}
+	return newest
+}
@@ -0,0 +1,42 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataAsOf(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644))
+	}
+	write("trades.json", `{"trades":[{"id":"a","entry_time":1760700000,"exit_time":1760710000},{"id":"b","entry_time":1760705000}]}`)
+	write("crypto-prices.json", `{"prices":{"BTC":{"symbol":"BTC","price":1,"timestamp":1760720000123}}}`)
+	write("conversations.json", `{"conversations":[{"model_id":"m","messages":[{"role":"user","content":"x","timestamp":"1760730000"}]}]}`)
+	write("positions.json", `{"accountTotals":[]}`)
+	mod := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "positions.json"), mod, mod))
+
+	dl := NewDataLoader(dir)
+	trades, err := dl.LoadTrades()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1760710000000), trades.DataAsOf)
+
+	prices, err := dl.LoadCryptoPrices()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1760720000123), prices.DataAsOf)
+
+	convs, err := dl.LoadConversations()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1760730000000), convs.DataAsOf)
+
+	// Positions carry no time of their own.
+	positions, err := dl.LoadPositions()
+	require.NoError(t, err)
+	assert.Equal(t, mod.UnixMilli(), positions.DataAsOf)
+}
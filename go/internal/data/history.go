@@ -0,0 +1,358 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+
+	"nof0-api/internal/registry"
+	"nof0-api/internal/types"
+)
+
+// History reconstructs point-in-time state (positions, account totals,
+// leaderboard, prices) from a source's trades, open positions, equity
+// snapshots (account totals) and price ticks. Trade fills double as price
+// ticks so prices can be rebuilt even without a tick history. Sources that
+// implement Recorder supply the recorded prices and positions at asOf, which
+// take precedence over the approximation.
+type History struct {
+	// StartingCapital returns a model's initial NAV; defaults to
+	// registry.DefaultStartingCapital.
+	StartingCapital func(modelId string) float64
+
+	trades    []types.Trade
+	open      []types.PositionsByModel
+	snapshots map[string][]types.AccountTotal // by model, ascending timestamp
+	ticks     map[string][]types.CryptoPrice  // by symbol, ascending timestamp
+	recorded  *Recorded
+}
+
+// Recorder is implemented by sources that keep a price tick and position
+// status history (Postgres).
+type Recorder interface {
+	// LoadRecordedAt returns the state recorded at asOf (ms), or nil when the
+	// source has no history for its arena.
+	LoadRecordedAt(asOf int64) (*Recorded, error)
+}
+
+// Recorded is the state a Recorder held at AsOf (ms): the last tick of every
+// symbol and the positions open or reduced at that moment.
+type Recorded struct {
+	AsOf      int64
+	Prices    map[string]types.CryptoPrice
+	Positions []types.PositionsByModel
+}
+
+// NewHistory loads everything needed for reconstruction at asOf (ms). Trades
+// are required; positions, account totals and prices are used when available.
+// When src is a Recorder its state at asOf is loaded as well; other points in
+// time are approximated.
+func NewHistory(src DataSource, asOf int64) (*History, error) {
+	trades, err := src.LoadTrades()
+	if err != nil {
+		return nil, err
+	}
+	h := &History{
+		trades:    trades.Trades,
+		snapshots: map[string][]types.AccountTotal{},
+		ticks:     map[string][]types.CryptoPrice{},
+	}
+	if resp, err := src.LoadPositions(); err == nil {
+		h.open = resp.AccountTotals
+	}
+	if resp, err := src.LoadAccountTotals(); err == nil {
+		for _, at := range resp.AccountTotals {
+			h.snapshots[at.ModelId] = append(h.snapshots[at.ModelId], at)
+		}
+	}
+	if resp, err := src.LoadCryptoPrices(); err == nil {
+		for sym, p := range resp.Prices {
+			if p.Symbol == "" {
+				p.Symbol = sym
+			}
+			h.addTick(p.Symbol, p.Price, p.Timestamp)
+		}
+	}
+	for _, t := range h.trades {
+		h.addTick(t.Symbol, t.EntryPrice, ToMillis(t.EntryTime))
+		if t.ExitTime > 0 {
+			h.addTick(t.Symbol, t.ExitPrice, ToMillis(t.ExitTime))
+		}
+	}
+
+	if rec, ok := src.(Recorder); ok {
+		if h.recorded, err = rec.LoadRecordedAt(asOf); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range h.snapshots {
+		sort.SliceStable(s, func(i, j int) bool { return s[i].Timestamp < s[j].Timestamp })
+	}
+	for _, ticks := range h.ticks {
+		sort.SliceStable(ticks, func(i, j int) bool { return ticks[i].Timestamp < ticks[j].Timestamp })
+	}
+	return h, nil
+}
+
+func (h *History) addTick(symbol string, price float64, ts int64) {
+	if symbol == "" || price <= 0 || ts <= 0 {
+		return
+	}
+	h.ticks[symbol] = append(h.ticks[symbol], types.CryptoPrice{Symbol: symbol, Price: price, Timestamp: ts})
+}
+
+func (h *History) capital(modelId string) float64 {
+	if h.StartingCapital != nil {
+		if c := h.StartingCapital(modelId); c > 0 {
+			return c
+		}
+	}
+	return registry.DefaultStartingCapital
+}
+
+// recordedAt returns the recorded state if it was loaded for asOf.
+func (h *History) recordedAt(asOf int64) (*Recorded, bool) {
+	if h.recorded == nil || h.recorded.AsOf != asOf {
+		return nil, false
+	}
+	return h.recorded, true
+}
+
+// Prices returns the last known price of every symbol at or before asOf (ms).
+// Recorded ticks win over trade fills and current prices unless those are
+// more recent.
+func (h *History) Prices(asOf int64) map[string]types.CryptoPrice {
+	out := map[string]types.CryptoPrice{}
+	for sym, ticks := range h.ticks {
+		i := sort.Search(len(ticks), func(i int) bool { return ticks[i].Timestamp > asOf })
+		if i > 0 {
+			out[sym] = ticks[i-1]
+		}
+	}
+	if rec, ok := h.recordedAt(asOf); ok {
+		for sym, p := range rec.Prices {
+			if cur, ok := out[sym]; !ok || p.Timestamp >= cur.Timestamp {
+				out[sym] = p
+			}
+		}
+	}
+	return out
+}
+
+// Positions returns the positions every model held at asOf (ms): recorded
+// positions first, then trades that were entered but not yet exited, plus
+// currently open positions entered by then. Current price and unrealized PnL
+// are marked at the asOf price.
+func (h *History) Positions(asOf int64) []types.PositionsByModel {
+	prices := h.Prices(asOf)
+	byModel := map[string]map[string]types.Position{}
+	put := func(modelId string, p types.Position) {
+		if byModel[modelId] == nil {
+			byModel[modelId] = map[string]types.Position{}
+		}
+		if px, ok := prices[p.Symbol]; ok {
+			p.CurrentPrice = px.Price
+			p.UnrealizedPnl = (px.Price - p.EntryPrice) * p.Quantity
+		}
+		byModel[modelId][p.Symbol] = p
+	}
+
+	held := map[string]struct{}{} // model/symbol
+	if rec, ok := h.recordedAt(asOf); ok {
+		for _, pm := range rec.Positions {
+			for sym, p := range pm.Positions {
+				if p.Symbol == "" {
+					p.Symbol = sym
+				}
+				held[pm.ModelId+"/"+p.Symbol] = struct{}{}
+				put(pm.ModelId, p)
+			}
+		}
+	}
+
+	traded := map[int64]struct{}{}
+	for _, t := range h.trades {
+		if t.EntryOid != 0 {
+			traded[t.EntryOid] = struct{}{}
+		}
+		if !openAt(t, asOf) {
+			continue
+		}
+		if _, ok := held[t.ModelId+"/"+t.Symbol]; ok {
+			continue // recorded state is authoritative
+		}
+		qty := t.Quantity
+		if t.Side == "short" && qty > 0 {
+			qty = -qty
+		}
+		put(t.ModelId, types.Position{
+			EntryOid:   t.EntryOid,
+			Confidence: t.Confidence,
+			ExitPlan:   t.ExitPlan,
+			EntryTime:  t.EntryTime,
+			Symbol:     t.Symbol,
+			EntryPrice: t.EntryPrice,
+			Commission: t.EntryCommissionDollars,
+			Leverage:   t.Leverage,
+			Quantity:   qty,
+		})
+	}
+	for _, pm := range h.open {
+		for sym, p := range pm.Positions {
+			if _, ok := traded[p.EntryOid]; ok && p.EntryOid != 0 {
+				continue // lifecycle already known from the trade
+			}
+			if ToMillis(p.EntryTime) > asOf {
+				continue
+			}
+			if _, ok := byModel[pm.ModelId][sym]; ok {
+				continue
+			}
+			if p.Symbol == "" {
+				p.Symbol = sym
+			}
+			put(pm.ModelId, p)
+		}
+	}
+
+	out := make([]types.PositionsByModel, 0, len(byModel))
+	for _, modelId := range h.modelIds() {
+		if positions, ok := byModel[modelId]; ok {
+			out = append(out, types.PositionsByModel{ModelId: modelId, Positions: positions})
+		}
+	}
+	return out
+}
+
+// AccountTotals returns each model's account at asOf (ms). The latest equity
+// snapshot at or before asOf anchors the result; realized PnL of trades closed
+// since then and the change in unrealized PnL are applied on top. Without a
+// snapshot the anchor is the model's starting capital.
+func (h *History) AccountTotals(asOf int64) []types.AccountTotal {
+	positions := map[string]map[string]types.Position{}
+	for _, pm := range h.Positions(asOf) {
+		positions[pm.ModelId] = pm.Positions
+	}
+
+	out := make([]types.AccountTotal, 0)
+	for _, modelId := range h.modelIds() {
+		capital := h.capital(modelId)
+		at := types.AccountTotal{
+			Id:           fmt.Sprintf("%s_%d", modelId, asOf),
+			ModelId:      modelId,
+			Timestamp:    float64(asOf) / 1000,
+			DollarEquity: capital,
+			Positions:    positions[modelId],
+		}
+		var anchorMs int64
+		if snap, ok := h.snapshotAt(modelId, asOf); ok {
+			at.DollarEquity = snap.DollarEquity - snap.TotalUnrealizedPnl
+			at.RealizedPnl = snap.RealizedPnl
+			at.SharpeRatio = snap.SharpeRatio
+			at.SinceInceptionHourlyMarker = snap.SinceInceptionHourlyMarker
+			at.SinceInceptionMinuteMarker = snap.SinceInceptionMinuteMarker
+			anchorMs = ToMillis(snap.Timestamp)
+		} else if len(h.tradesOf(modelId, 0, asOf)) == 0 && len(positions[modelId]) == 0 {
+			continue // model had not started trading yet
+		}
+		for _, t := range h.tradesOf(modelId, anchorMs, asOf) {
+			at.RealizedPnl += t.RealizedNetPnl
+			at.DollarEquity += t.RealizedNetPnl
+		}
+		for _, p := range at.Positions {
+			at.TotalUnrealizedPnl += p.UnrealizedPnl
+		}
+		if at.Positions == nil {
+			at.Positions = map[string]types.Position{}
+		}
+		at.DollarEquity += at.TotalUnrealizedPnl
+		at.CumPnlPct = (at.DollarEquity - capital) / capital * 100
+		out = append(out, at)
+	}
+	return out
+}
+
+// Leaderboard ranks models by equity at asOf (ms), counting trades closed by then.
+func (h *History) Leaderboard(asOf int64) []types.LeaderboardEntry {
+	totals := h.AccountTotals(asOf)
+	out := make([]types.LeaderboardEntry, 0, len(totals))
+	for _, at := range totals {
+		e := types.LeaderboardEntry{
+			Id:        at.ModelId,
+			Sharpe:    at.SharpeRatio,
+			ReturnPct: at.CumPnlPct,
+			Equity:    at.DollarEquity,
+		}
+		for _, t := range h.tradesOf(at.ModelId, 0, asOf) {
+			e.NumTrades++
+			switch {
+			case t.RealizedNetPnl > 0:
+				e.NumWins++
+				e.WinDollars += t.RealizedNetPnl
+			case t.RealizedNetPnl < 0:
+				e.NumLosses++
+				e.LoseDollars += t.RealizedNetPnl
+			}
+		}
+		out = append(out, e)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Equity > out[j].Equity })
+	return out
+}
+
+// tradesOf returns modelId's trades that closed in (from, to] (ms).
+func (h *History) tradesOf(modelId string, from, to int64) []types.Trade {
+	var out []types.Trade
+	for _, t := range h.trades {
+		if t.ModelId != modelId || t.ExitTime <= 0 {
+			continue
+		}
+		if exit := ToMillis(t.ExitTime); exit > from && exit <= to {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func (h *History) snapshotAt(modelId string, asOf int64) (types.AccountTotal, bool) {
+	snaps := h.snapshots[modelId]
+	i := sort.Search(len(snaps), func(i int) bool { return ToMillis(snaps[i].Timestamp) > asOf })
+	if i == 0 {
+		return types.AccountTotal{}, false
+	}
+	return snaps[i-1], true
+}
+
+func (h *History) modelIds() []string {
+	seen := map[string]struct{}{}
+	for _, t := range h.trades {
+		seen[t.ModelId] = struct{}{}
+	}
+	for _, pm := range h.open {
+		seen[pm.ModelId] = struct{}{}
+	}
+	for modelId := range h.snapshots {
+		seen[modelId] = struct{}{}
+	}
+	if h.recorded != nil {
+		for _, pm := range h.recorded.Positions {
+			seen[pm.ModelId] = struct{}{}
+		}
+	}
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// openAt reports whether a trade's position was held at asOf (ms).
+func openAt(t types.Trade, asOf int64) bool {
+	if t.EntryTime <= 0 || ToMillis(t.EntryTime) > asOf {
+		return false
+	}
+	return t.ExitTime <= 0 || ToMillis(t.ExitTime) > asOf
+}
@@ -0,0 +1,159 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/types"
+)
+
+// Timeline (seconds): a holds BTC long 1000-2000 (+100); b holds ETH short
+// 1200-1400 (-5) and reopens at 1500, still open. Prices at 3000 are current.
+func writeHistoryFixture(t *testing.T) string {
+	dir := t.TempDir()
+	writeArenaFile(t, dir, "trades.json", `{"trades":[
+		{"id":"t1","model_id":"a","symbol":"BTC","side":"long","quantity":1,"entry_price":100,"entry_time":1000,
+		 "exit_price":200,"exit_time":2000,"realized_net_pnl":100,"entry_oid":11},
+		{"id":"t2","model_id":"b","symbol":"ETH","side":"short","quantity":-2,"entry_price":50,"entry_time":1200,
+		 "exit_price":40,"exit_time":1400,"realized_net_pnl":-5,"entry_oid":21}
+	]}`)
+	writeArenaFile(t, dir, "positions.json", `{"accountTotals":[
+		{"model_id":"b","positions":{"ETH":{"symbol":"ETH","entry_oid":22,"entry_time":1500,"entry_price":45,"quantity":-2}}}
+	]}`)
+	writeArenaFile(t, dir, "crypto-prices.json", `{"prices":{
+		"BTC":{"symbol":"BTC","price":250,"timestamp":3000000},
+		"ETH":{"symbol":"ETH","price":30,"timestamp":3000000}
+	}}`)
+	return dir
+}
+
+func TestHistoryPositions(t *testing.T) {
+	h, err := NewHistory(NewDataLoader(writeHistoryFixture(t)), 3_000_000)
+	require.NoError(t, err)
+
+	assert.Empty(t, h.Positions(999_000), "Nothing is open before the first entry")
+
+	at := h.Positions(1_300_000)
+	require.Len(t, at, 2)
+	assert.Equal(t, "a", at[0].ModelId)
+	btc := at[0].Positions["BTC"]
+	assert.Equal(t, 100.0, btc.EntryPrice)
+	assert.Equal(t, 100.0, btc.CurrentPrice, "Marked at the last fill before as_of")
+	eth := at[1].Positions["ETH"]
+	assert.Equal(t, int64(21), eth.EntryOid)
+	assert.Equal(t, -2.0, eth.Quantity)
+
+	later := h.Positions(2_500_000)
+	require.Len(t, later, 1, "a closed BTC at 2000")
+	eth = later[0].Positions["ETH"]
+	assert.Equal(t, int64(22), eth.EntryOid, "Currently open position applies once entered")
+	assert.Equal(t, 40.0, eth.CurrentPrice)
+	assert.InDelta(t, 10.0, eth.UnrealizedPnl, 1e-9, "Short gains as price falls")
+}
+
+func TestHistoryAccountTotalsAndLeaderboard(t *testing.T) {
+	h, err := NewHistory(NewDataLoader(writeHistoryFixture(t)), 3_000_000)
+	require.NoError(t, err)
+	h.StartingCapital = func(modelId string) float64 {
+		if modelId == "b" {
+			return 1000
+		}
+		return 0 // falls back to the default
+	}
+
+	totals := h.AccountTotals(3_000_000)
+	require.Len(t, totals, 2)
+	a, b := totals[0], totals[1]
+	assert.Equal(t, 10100.0, a.DollarEquity)
+	assert.Equal(t, 100.0, a.RealizedPnl)
+	assert.InDelta(t, 1.0, a.CumPnlPct, 1e-9)
+	assert.Equal(t, 3000.0, a.Timestamp)
+	assert.InDelta(t, -5.0, b.RealizedPnl, 1e-9)
+	assert.InDelta(t, 30.0, b.TotalUnrealizedPnl, 1e-9)
+	assert.InDelta(t, 1025.0, b.DollarEquity, 1e-9)
+
+	board := h.Leaderboard(3_000_000)
+	require.Len(t, board, 2)
+	assert.Equal(t, "a", board[0].Id, "Ranked by equity")
+	assert.Equal(t, 1, board[0].NumWins)
+	assert.Equal(t, 100.0, board[0].WinDollars)
+	assert.Equal(t, 1, board[1].NumLosses)
+	assert.Equal(t, -5.0, board[1].LoseDollars)
+
+	assert.Empty(t, h.AccountTotals(500_000), "No model had started trading")
+}
+
+func TestHistoryAnchorsOnEquitySnapshot(t *testing.T) {
+	dir := writeHistoryFixture(t)
+	writeArenaFile(t, dir, "account-totals.json", `{"accountTotals":[
+		{"model_id":"a","timestamp":1500,"dollar_equity":12000,"realized_pnl":0,"total_unrealized_pnl":50,"sharpe_ratio":1.5}
+	]}`)
+	h, err := NewHistory(NewDataLoader(dir), 2_500_000)
+	require.NoError(t, err)
+
+	totals := h.AccountTotals(2_500_000)
+	require.Len(t, totals, 2)
+	a := totals[0]
+	assert.Equal(t, 12050.0, a.DollarEquity, "Snapshot minus its unrealized PnL plus the trade closed since")
+	assert.Equal(t, 1.5, a.SharpeRatio)
+}
+
+func TestHistoryPrices(t *testing.T) {
+	h, err := NewHistory(NewDataLoader(testDataPath), 0)
+	require.NoError(t, err)
+
+	latest, err := NewDataLoader(testDataPath).LoadCryptoPrices()
+	require.NoError(t, err)
+	now := h.Prices(latest.ServerTime)
+	for sym, p := range latest.Prices {
+		assert.Equal(t, p.Price, now[sym].Price, "%s should match the latest tick", sym)
+	}
+
+	assert.Empty(t, h.Prices(1), "No ticks that early")
+}
+
+// recordingSource is a DataLoader that also recorded prices and positions.
+type recordingSource struct {
+	*DataLoader
+	rec *Recorded
+}
+
+func (s recordingSource) LoadRecordedAt(asOf int64) (*Recorded, error) {
+	if s.rec != nil {
+		s.rec.AsOf = asOf
+	}
+	return s.rec, nil
+}
+
+func TestHistoryPrefersRecordedState(t *testing.T) {
+	src := recordingSource{DataLoader: NewDataLoader(writeHistoryFixture(t)), rec: &Recorded{
+		Prices: map[string]types.CryptoPrice{
+			"BTC": {Symbol: "BTC", Price: 120, Timestamp: 1_250_000},
+			"ETH": {Symbol: "ETH", Price: 60, Timestamp: 1_100_000},
+		},
+		Positions: []types.PositionsByModel{
+			{ModelId: "a", Positions: map[string]types.Position{
+				"BTC": {Symbol: "BTC", EntryOid: 10, EntryTime: 900, EntryPrice: 90, Quantity: 2},
+			}},
+			{ModelId: "c", Positions: map[string]types.Position{}},
+		},
+	}}
+	h, err := NewHistory(src, 1_300_000)
+	require.NoError(t, err)
+
+	prices := h.Prices(1_300_000)
+	assert.Equal(t, 120.0, prices["BTC"].Price, "Recorded tick is newer than the BTC fill")
+	assert.Equal(t, 50.0, prices["ETH"].Price, "ETH fill at 1200 is newer than the recorded tick")
+
+	at := h.Positions(1_300_000)
+	require.Len(t, at, 2)
+	btc := at[0].Positions["BTC"]
+	assert.Equal(t, int64(10), btc.EntryOid, "Recorded position wins over the trade")
+	assert.Equal(t, 2.0, btc.Quantity)
+	assert.InDelta(t, 60.0, btc.UnrealizedPnl, 1e-9)
+	assert.Equal(t, int64(21), at[1].Positions["ETH"].EntryOid, "Trades fill what was not recorded")
+
+	assert.Equal(t, int64(11), h.Positions(1_500_000)[0].Positions["BTC"].EntryOid, "Other points in time are approximated")
+}
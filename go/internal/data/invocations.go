@@ -0,0 +1,178 @@
+package data
+
+import (
+	"errors"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"nof0-api/internal/metrics"
+	"nof0-api/internal/types"
+)
+
+const invocationsFile = "invocations.json"
+
+// Invocation outcomes.
+const (
+	InvocationStatusOk    = "ok"
+	InvocationStatusError = "error"
+)
+
+// LoadInvocations loads recorded model invocations from invocations.json.
+// When the file does not exist they are derived from conversations: each
+// assistant reply is one invocation whose prompt is the messages before it,
+// with token counts estimated and marked as such. Timestamps are epoch ms.
+func (dl *DataLoader) LoadInvocations() ([]types.Invocation, error) {
+	defer metrics.ObserveLoad(metrics.SourceFile, "invocations", time.Now())
+	var data struct {
+		Invocations []types.Invocation `json:"invocations"`
+	}
+	err := dl.loadJSONFile(invocationsFile, &data)
+	if err == nil {
+		for i := range data.Invocations {
+			data.Invocations[i].Timestamp = ToMillis(float64(data.Invocations[i].Timestamp))
+			if data.Invocations[i].Status == "" {
+				data.Invocations[i].Status = InvocationStatusOk
+			}
+		}
+		return data.Invocations, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	convs, err := dl.LoadConversations()
+	if err != nil {
+		return nil, err
+	}
+	return InvocationsFromConversations(convs.Conversations), nil
+}
+
+// InvocationsFromConversations derives one estimated invocation per
+// assistant message. Latency is the gap from the last prompt message.
+func InvocationsFromConversations(conversations []types.Conversation) []types.Invocation {
+	var out []types.Invocation
+	for ci, c := range conversations {
+		var promptTokens int
+		var promptMs int64
+		for mi, m := range c.Messages {
+			ts := messageMillis(m.Timestamp)
+			if m.Role != "assistant" {
+				promptTokens += EstimateTokens(m.Content)
+				promptMs = ts
+				continue
+			}
+			inv := types.Invocation{
+				Id:             c.ModelId + ":" + strconv.Itoa(ci+1) + ":" + strconv.Itoa(mi+1),
+				ModelId:        c.ModelId,
+				ConversationId: int64(ci + 1),
+				Timestamp:      ts,
+				InputTokens:    promptTokens,
+				OutputTokens:   EstimateTokens(m.Content),
+				Status:         InvocationStatusOk,
+				Estimated:      true,
+			}
+			if promptMs > 0 && ts >= promptMs {
+				inv.Timestamp = promptMs
+				inv.LatencyMs = ts - promptMs
+			}
+			out = append(out, inv)
+			// The reply becomes context for any later turn.
+			promptTokens += inv.OutputTokens
+		}
+	}
+	return out
+}
+
+// EstimateTokens approximates a tokenizer at four characters per token.
+func EstimateTokens(text string) int {
+	return (len([]rune(text)) + 3) / 4
+}
+
+// AggregateInvocations summarizes invocations per model per UTC day, ordered
+// by day then model. from/to (epoch ms, inclusive) and modelId filter when set.
+func AggregateInvocations(invocations []types.Invocation, modelId string, from, to int64) []types.InvocationDailyStats {
+	type key struct{ day, modelId string }
+	groups := map[key][]types.Invocation{}
+	for _, inv := range invocations {
+		switch {
+		case modelId != "" && inv.ModelId != modelId:
+		case from > 0 && inv.Timestamp < from:
+		case to > 0 && inv.Timestamp > to:
+		default:
+			k := key{time.UnixMilli(inv.Timestamp).UTC().Format(time.DateOnly), inv.ModelId}
+			groups[k] = append(groups[k], inv)
+		}
+	}
+
+	out := make([]types.InvocationDailyStats, 0, len(groups))
+	for k, invs := range groups {
+		sort.Slice(invs, func(i, j int) bool { return invs[i].Timestamp < invs[j].Timestamp })
+		s := types.InvocationDailyStats{Day: k.day, ModelId: k.modelId}
+		latencies := make([]float64, 0, len(invs))
+		var breaks float64
+		for i, inv := range invs {
+			s.Invocations++
+			if inv.Status != InvocationStatusOk {
+				s.Errors++
+			}
+			if inv.Estimated {
+				s.Estimated = true
+			}
+			if s.Provider == "" {
+				s.Provider = inv.Provider
+			}
+			s.InputTokens += inv.InputTokens
+			s.OutputTokens += inv.OutputTokens
+			s.CostUsd += inv.CostUsd
+			latencies = append(latencies, float64(inv.LatencyMs))
+			if inv.LatencyMs > s.MaxLatencyMs {
+				s.MaxLatencyMs = inv.LatencyMs
+			}
+			if i > 0 {
+				breaks += float64(inv.Timestamp-invs[i-1].Timestamp) / float64(time.Minute/time.Millisecond)
+			}
+		}
+		s.CostUsd = math.Round(s.CostUsd*1e6) / 1e6
+		s.AvgLatencyMs = mean(latencies)
+		s.P95LatencyMs = percentile(latencies, 0.95)
+		if len(invs) > 1 {
+			s.AvgInvocationBreakMins = breaks / float64(len(invs)-1)
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Day != out[j].Day {
+			return out[i].Day < out[j].Day
+		}
+		return out[i].ModelId < out[j].ModelId
+	})
+	return out
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// percentile uses linear interpolation between closest ranks, like
+// Postgres percentile_cont.
+func percentile(xs []float64, p float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	rank := p * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
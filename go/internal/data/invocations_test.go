@@ -0,0 +1,90 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/types"
+)
+
+func TestLoadInvocationsFromConversations(t *testing.T) {
+	invs, err := NewDataLoader(testDataPath).LoadInvocations()
+	require.NoError(t, err)
+	require.NotEmpty(t, invs)
+
+	for _, inv := range invs {
+		assert.True(t, inv.Estimated, "Derived invocations carry estimated tokens")
+		assert.Equal(t, InvocationStatusOk, inv.Status)
+		assert.NotZero(t, inv.ConversationId)
+		assert.Greater(t, inv.InputTokens, 0)
+		assert.Greater(t, inv.OutputTokens, 0)
+		assert.Greater(t, inv.Timestamp, int64(1e12), "Timestamps should be in ms")
+	}
+}
+
+func TestLoadInvocationsFile(t *testing.T) {
+	dir := t.TempDir()
+	writeArenaFile(t, dir, invocationsFile, `{"invocations":[
+		{"id":"1","model_id":"a","provider":"openai","timestamp":1760738000,"latency_ms":1200,"input_tokens":900,"output_tokens":300,"cost_usd":0.01},
+		{"id":"2","model_id":"a","timestamp":1760738060000,"status":"error","error":"timeout"}]}`)
+
+	invs, err := NewDataLoader(dir).LoadInvocations()
+	require.NoError(t, err)
+	require.Len(t, invs, 2)
+	assert.Equal(t, int64(1760738000000), invs[0].Timestamp, "Seconds should be normalized to ms")
+	assert.Equal(t, InvocationStatusOk, invs[0].Status, "Status defaults to ok")
+	assert.False(t, invs[0].Estimated)
+	assert.Equal(t, InvocationStatusError, invs[1].Status)
+}
+
+func TestInvocationsFromConversations(t *testing.T) {
+	invs := InvocationsFromConversations([]types.Conversation{{
+		ModelId: "a",
+		Messages: []types.ConversationMessage{
+			{Role: "system", Content: "12345678", Timestamp: 1000.0},
+			{Role: "user", Content: "abcd", Timestamp: 1010.0},
+			{Role: "assistant", Content: "reply", Timestamp: 1040.0},
+		},
+	}})
+	require.Len(t, invs, 1)
+	assert.Equal(t, int64(1010000), invs[0].Timestamp, "An invocation starts at the last prompt message")
+	assert.Equal(t, int64(30000), invs[0].LatencyMs)
+	assert.Equal(t, 3, invs[0].InputTokens)
+	assert.Equal(t, 2, invs[0].OutputTokens)
+}
+
+func TestAggregateInvocations(t *testing.T) {
+	const day = 86400000
+	invs := []types.Invocation{
+		{ModelId: "a", Provider: "openai", Timestamp: day, LatencyMs: 100, InputTokens: 10, OutputTokens: 5, CostUsd: 0.1, Status: "ok"},
+		{ModelId: "a", Timestamp: day + 60000, LatencyMs: 300, InputTokens: 20, OutputTokens: 5, CostUsd: 0.2, Status: "error"},
+		{ModelId: "a", Timestamp: day + 3*60000, LatencyMs: 200, Status: "ok", Estimated: true},
+		{ModelId: "b", Timestamp: day + 1000, LatencyMs: 50, Status: "ok"},
+		{ModelId: "a", Timestamp: 2 * day, LatencyMs: 10, Status: "ok"},
+	}
+
+	stats := AggregateInvocations(invs, "", 0, 0)
+	require.Len(t, stats, 3)
+	assert.Equal(t, "1970-01-02", stats[0].Day)
+	assert.Equal(t, "a", stats[0].ModelId)
+	assert.Equal(t, "b", stats[1].ModelId, "Ordered by day then model")
+	assert.Equal(t, "1970-01-03", stats[2].Day)
+
+	a := stats[0]
+	assert.Equal(t, "openai", a.Provider)
+	assert.Equal(t, 3, a.Invocations)
+	assert.Equal(t, 1, a.Errors)
+	assert.Equal(t, 30, a.InputTokens)
+	assert.Equal(t, 10, a.OutputTokens)
+	assert.InDelta(t, 0.3, a.CostUsd, 1e-9)
+	assert.InDelta(t, 200, a.AvgLatencyMs, 1e-9)
+	assert.InDelta(t, 290, a.P95LatencyMs, 1e-9, "Interpolated like percentile_cont")
+	assert.Equal(t, int64(300), a.MaxLatencyMs)
+	assert.InDelta(t, 1.5, a.AvgInvocationBreakMins, 1e-9)
+	assert.True(t, a.Estimated)
+
+	assert.Len(t, AggregateInvocations(invs, "a", 0, 0), 2)
+	assert.Len(t, AggregateInvocations(invs, "", day+2000, 2*day-1), 1)
+}
@@ -0,0 +1,353 @@
+package data
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"nof0-api/internal/types"
+)
+
+// Link defaults: a decision must be acted on within DefaultLinkWindow, at an
+// entry price within DefaultLinkPriceTolerance (fraction) of the one stated.
+// Messages are stamped once the invocation completes, so fills up to
+// linkClockSkew before the message still count.
+const (
+	DefaultLinkWindow         = 4 * time.Hour
+	DefaultLinkPriceTolerance = 0.02
+
+	linkClockSkew = time.Minute
+)
+
+// LinkOptions tunes how decisions are matched to trades and positions.
+type LinkOptions struct {
+	Window         time.Duration
+	PriceTolerance float64
+}
+
+func (o LinkOptions) withDefaults() LinkOptions {
+	if o.Window <= 0 {
+		o.Window = DefaultLinkWindow
+	}
+	if o.PriceTolerance <= 0 {
+		o.PriceTolerance = DefaultLinkPriceTolerance
+	}
+	return o
+}
+
+// Decision is a trade call parsed from an assistant message.
+type Decision struct {
+	ConversationId int64
+	MessageId      int64
+	ModelId        string
+	Timestamp      int64 // ms
+	Symbol         string
+	Side           string // long or short
+	Entry          float64
+	Target         float64
+	Stop           float64
+}
+
+var numberRe = regexp.MustCompile(`\d[\d,]*(?:\.\d+)?`)
+
+// ParseDecisions extracts per-symbol trade calls from message content. A
+// section starts at a line naming one of symbols and runs until the next such
+// line; its direction is the first long/short (or buy/sell) word in it.
+func ParseDecisions(content string, symbols []string) []Decision {
+	known := map[string]string{}
+	for _, s := range symbols {
+		known[strings.ToLower(s)] = s
+	}
+
+	var out []Decision
+	var cur *Decision
+	var fallbackSide string
+	flush := func() {
+		if cur != nil {
+			if cur.Side == "" {
+				cur.Side = fallbackSide
+			}
+			if cur.Side != "" {
+				out = append(out, *cur)
+			}
+		}
+		cur, fallbackSide = nil, ""
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		tokens := tokenize(line)
+		for _, tok := range tokens {
+			if sym, ok := known[tok.term]; ok && (cur == nil || cur.Symbol != sym) && startsSection(line, tok) {
+				flush()
+				cur = &Decision{Symbol: sym}
+				break
+			}
+		}
+		if cur == nil {
+			continue
+		}
+		for _, tok := range tokens {
+			switch tok.term {
+			case "long", "short":
+				if cur.Side == "" {
+					cur.Side = tok.term
+				}
+			case "buy":
+				if fallbackSide == "" {
+					fallbackSide = "long"
+				}
+			case "sell":
+				if fallbackSide == "" {
+					fallbackSide = "short"
+				}
+			}
+		}
+
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			continue
+		}
+		label := strings.ToLower(line[:colon])
+		m := numberRe.FindString(line[colon+1:])
+		value, _ := strconv.ParseFloat(strings.ReplaceAll(m, ",", ""), 64)
+		switch {
+		case strings.Contains(label, "stop"):
+			if cur.Stop == 0 {
+				cur.Stop = value
+			}
+		case strings.Contains(label, "target") || strings.Contains(label, "take profit"):
+			if cur.Target == 0 {
+				cur.Target = value
+			}
+		case strings.Contains(label, "entry"):
+			if cur.Entry == 0 {
+				cur.Entry = value
+			}
+		}
+	}
+	flush()
+	return out
+}
+
+// startsSection reports whether a symbol mention opens a new decision: the
+// symbol leads the line (e.g. "BTC: Short", "SOL Trade Setup:").
+func startsSection(line string, tok token) bool {
+	lead := strings.TrimLeft(string([]rune(line)[:tok.start]), " \t-*#>•")
+	return lead == ""
+}
+
+// LinkConversations matches assistant decisions to the trades and open
+// positions that followed them: same model, symbol and direction, entered
+// within the window after the message, at a compatible entry price. Each
+// trade or position links to at most one decision, the best-scoring one.
+func LinkConversations(conversations []types.Conversation, trades []types.Trade, positions []types.PositionsByModel, opts LinkOptions) []types.ConversationLink {
+	opts = opts.withDefaults()
+
+	symbolSet := map[string]struct{}{}
+	for _, t := range trades {
+		symbolSet[t.Symbol] = struct{}{}
+	}
+	for _, pm := range positions {
+		for sym := range pm.Positions {
+			symbolSet[sym] = struct{}{}
+		}
+	}
+	symbols := make([]string, 0, len(symbolSet))
+	for s := range symbolSet {
+		if s != "" {
+			symbols = append(symbols, s)
+		}
+	}
+
+	var decisions []Decision
+	for ci, c := range conversations {
+		for mi, m := range c.Messages {
+			if m.Role != "assistant" {
+				continue
+			}
+			for _, d := range ParseDecisions(m.Content, symbols) {
+				d.ConversationId, d.MessageId = int64(ci+1), int64(mi+1)
+				d.ModelId, d.Timestamp = c.ModelId, messageMillis(m.Timestamp)
+				decisions = append(decisions, d)
+			}
+		}
+	}
+
+	var candidates []types.ConversationLink
+	for _, d := range decisions {
+		for _, t := range trades {
+			side := t.Side
+			if side == "" {
+				side = t.TradeType
+			}
+			if link, ok := matchDecision(d, t.ModelId, t.Symbol, side, t.EntryPrice, ToMillis(t.EntryTime), t.ExitPlan, opts); ok {
+				link.TradeId = t.Id
+				link.EntryOid = t.EntryOid
+				candidates = append(candidates, link)
+			}
+		}
+		for _, pm := range positions {
+			for sym, p := range pm.Positions {
+				if link, ok := matchDecision(d, pm.ModelId, sym, PositionSide(p.Quantity), p.EntryPrice, ToMillis(p.EntryTime), p.ExitPlan, opts); ok {
+					link.EntryOid = p.EntryOid
+					link.Position = true
+					candidates = append(candidates, link)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	taken := map[string]struct{}{}
+	links := []types.ConversationLink{}
+	for _, c := range candidates {
+		key := "t:" + c.TradeId
+		if c.Position {
+			key = "p:" + c.ModelId + ":" + c.Symbol + ":" + strconv.FormatInt(c.EntryTime, 10)
+		}
+		if _, ok := taken[key]; ok {
+			continue
+		}
+		taken[key] = struct{}{}
+		links = append(links, c)
+	}
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].ConversationId != links[j].ConversationId {
+			return links[i].ConversationId < links[j].ConversationId
+		}
+		return links[i].EntryTime < links[j].EntryTime
+	})
+	return links
+}
+
+func matchDecision(d Decision, modelId, symbol, side string, entryPrice float64, entryMs int64, exitPlan interface{}, opts LinkOptions) (types.ConversationLink, bool) {
+	if d.ModelId != modelId || d.Symbol != symbol || d.Side != side {
+		return types.ConversationLink{}, false
+	}
+	delay := entryMs - d.Timestamp
+	if d.Timestamp <= 0 || delay < -linkClockSkew.Milliseconds() || delay > opts.Window.Milliseconds() {
+		return types.ConversationLink{}, false
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	matched := []string{"symbol", "side", "time"}
+	// Closer in time scores higher; every matching price adds a point.
+	score := 1 - float64(delay)/float64(opts.Window.Milliseconds())
+	if d.Entry > 0 && entryPrice > 0 {
+		if !near(d.Entry, entryPrice, opts.PriceTolerance) {
+			return types.ConversationLink{}, false
+		}
+		matched = append(matched, "entry")
+		score++
+	}
+	target, stop := exitPlanLevels(exitPlan)
+	if d.Target > 0 && target > 0 && near(d.Target, target, opts.PriceTolerance/4) {
+		matched = append(matched, "target")
+		score++
+	}
+	if d.Stop > 0 && stop > 0 && near(d.Stop, stop, opts.PriceTolerance/4) {
+		matched = append(matched, "stop")
+		score++
+	}
+	return types.ConversationLink{
+		ConversationId: d.ConversationId,
+		MessageId:      d.MessageId,
+		ModelId:        modelId,
+		Symbol:         symbol,
+		Side:           side,
+		DecisionTime:   d.Timestamp,
+		EntryTime:      entryMs,
+		MatchedOn:      matched,
+		Score:          math.Round(score*1000) / 1000,
+	}, true
+}
+
+func near(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance*math.Abs(b)
+}
+
+func exitPlanLevels(plan interface{}) (target, stop float64) {
+	m, ok := plan.(map[string]interface{})
+	if !ok {
+		return 0, 0
+	}
+	target, _ = m["profit_target"].(float64)
+	stop, _ = m["stop_loss"].(float64)
+	return target, stop
+}
+
+// ApplyTradeLinks sets ConversationId on every linked trade.
+func ApplyTradeLinks(trades []types.Trade, links []types.ConversationLink) {
+	byTrade := map[string]int64{}
+	for _, l := range links {
+		if l.TradeId != "" {
+			byTrade[l.TradeId] = l.ConversationId
+		}
+	}
+	for i := range trades {
+		if id, ok := byTrade[trades[i].Id]; ok {
+			trades[i].ConversationId = id
+		}
+	}
+}
+
+// ApplyPositionLinks sets ConversationId on every linked open position.
+func ApplyPositionLinks(positions []types.PositionsByModel, links []types.ConversationLink) {
+	byPosition := map[string]int64{}
+	for _, l := range links {
+		if l.Position {
+			byPosition[l.ModelId+":"+l.Symbol] = l.ConversationId
+		}
+	}
+	for _, pm := range positions {
+		for sym, p := range pm.Positions {
+			if id, ok := byPosition[pm.ModelId+":"+sym]; ok {
+				p.ConversationId = id
+				pm.Positions[sym] = p
+			}
+		}
+	}
+}
+
+// ApplyConversationLinks numbers conversations (1-based, as used by links and
+// search) and lists the trades each one produced.
+func ApplyConversationLinks(conversations []types.Conversation, links []types.ConversationLink) {
+	for i := range conversations {
+		conversations[i].Id = int64(i + 1)
+	}
+	for _, l := range links {
+		if l.TradeId == "" || l.ConversationId < 1 || int(l.ConversationId) > len(conversations) {
+			continue
+		}
+		c := &conversations[l.ConversationId-1]
+		c.TradeIds = append(c.TradeIds, l.TradeId)
+	}
+}
+
+// ConversationLinks returns the links for this data directory, recomputed
+// when conversations, trades or positions change.
+func (dl *DataLoader) ConversationLinks() ([]types.ConversationLink, error) {
+	v, err := dl.memo("links", []string{conversationsFile, "trades.json", "positions.json"}, func() (interface{}, error) {
+		convs, err := dl.LoadConversations()
+		if err != nil {
+			return nil, err
+		}
+		trades, err := dl.LoadTrades()
+		if err != nil {
+			return nil, err
+		}
+		var positions []types.PositionsByModel
+		if resp, err := dl.LoadPositions(); err == nil {
+			positions = resp.AccountTotals
+		}
+		return LinkConversations(convs.Conversations, trades.Trades, positions, LinkOptions{}), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]types.ConversationLink), nil
+}
@@ -0,0 +1,98 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/types"
+)
+
+func TestParseDecisions(t *testing.T) {
+	content := `Based on current technical analysis:
+
+BTC: Short position recommended
+- Entry: ~107,067
+- Target: 102,321.7
+- Stop Loss: 109,362.4
+- Reasoning: bearish MACD divergence; ETH looks similar
+
+SOL Trade Setup:
+- Direction: Long
+- Entry Price: 185.5
+
+DOGE Market Analysis:
+- Trade Recommendation: BUY
+- Take Profit: 0.22
+
+XRP looks choppy, no trade.`
+
+	decisions := ParseDecisions(content, []string{"BTC", "ETH", "SOL", "DOGE", "XRP"})
+	require.Len(t, decisions, 3, "Sections without a direction are not decisions")
+
+	assert.Equal(t, Decision{Symbol: "BTC", Side: "short", Entry: 107067, Target: 102321.7, Stop: 109362.4}, decisions[0])
+	assert.Equal(t, Decision{Symbol: "SOL", Side: "long", Entry: 185.5}, decisions[1])
+	assert.Equal(t, Decision{Symbol: "DOGE", Side: "long", Target: 0.22}, decisions[2])
+}
+
+func TestLinkConversations(t *testing.T) {
+	conversations := []types.Conversation{{
+		ModelId: "a",
+		Messages: []types.ConversationMessage{
+			{Role: "user", Content: "BTC: go long?", Timestamp: 1000.0},
+			{Role: "assistant", Content: "BTC: Long\nEntry: 100\nStop: 90", Timestamp: 1000.0},
+		},
+	}}
+	trades := []types.Trade{
+		{Id: "hit", ModelId: "a", Symbol: "BTC", Side: "long", EntryPrice: 101, EntryTime: 1600},
+		{Id: "later", ModelId: "a", Symbol: "BTC", Side: "long", EntryPrice: 100, EntryTime: 1000 + 5*3600},
+		{Id: "wrong-side", ModelId: "a", Symbol: "BTC", Side: "short", EntryPrice: 100, EntryTime: 1100},
+		{Id: "far-price", ModelId: "a", Symbol: "BTC", Side: "long", EntryPrice: 120, EntryTime: 1100},
+		{Id: "other-model", ModelId: "b", Symbol: "BTC", Side: "long", EntryPrice: 100, EntryTime: 1100},
+	}
+	positions := []types.PositionsByModel{{ModelId: "a", Positions: map[string]types.Position{
+		"BTC": {Symbol: "BTC", EntryPrice: 100, Quantity: 1, EntryTime: 1200, EntryOid: 7,
+			ExitPlan: map[string]interface{}{"stop_loss": 90.0}},
+	}}}
+
+	links := LinkConversations(conversations, trades, positions, LinkOptions{})
+	require.Len(t, links, 2)
+
+	assert.True(t, links[0].Position)
+	assert.Equal(t, int64(7), links[0].EntryOid)
+	assert.Equal(t, []string{"symbol", "side", "time", "entry", "stop"}, links[0].MatchedOn)
+
+	assert.Equal(t, "hit", links[1].TradeId)
+	assert.Equal(t, int64(1), links[1].ConversationId)
+	assert.Equal(t, int64(2), links[1].MessageId, "Only assistant messages carry decisions")
+	assert.Greater(t, links[0].Score, links[1].Score, "Matching exit plan levels should score higher")
+
+	wide := LinkConversations(conversations, trades, positions, LinkOptions{Window: 6 * time.Hour})
+	assert.Len(t, wide, 3, "A wider window also links the later trade")
+
+	ApplyTradeLinks(trades, links)
+	assert.Equal(t, int64(1), trades[0].ConversationId)
+	assert.Zero(t, trades[1].ConversationId)
+
+	ApplyPositionLinks(positions, links)
+	assert.Equal(t, int64(1), positions[0].Positions["BTC"].ConversationId)
+
+	ApplyConversationLinks(conversations, links)
+	assert.Equal(t, int64(1), conversations[0].Id)
+	assert.Equal(t, []string{"hit"}, conversations[0].TradeIds)
+}
+
+func TestConversationLinksFromData(t *testing.T) {
+	links, err := NewDataLoader(testDataPath).ConversationLinks()
+	require.NoError(t, err)
+	require.NotEmpty(t, links)
+
+	for _, l := range links {
+		assert.NotZero(t, l.ConversationId)
+		assert.Contains(t, []string{"long", "short"}, l.Side)
+		assert.Contains(t, l.MatchedOn, "entry", "Every stated entry should agree with the fill")
+		assert.True(t, l.TradeId != "" || l.Position)
+	}
+}
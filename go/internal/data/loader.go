@@ -1,45 +1,74 @@
 package data
 
 import (
+	"compress/gzip"
 	"encoding/json"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/zeromicro/go-zero/core/syncx"
+
+	"nof0-api/internal/errs"
+	"nof0-api/internal/metrics"
 	"nof0-api/internal/types"
 )
 
 // DataLoader handles loading JSON data from MCP data files
 type DataLoader struct {
 	dataPath string
+
+	mu      sync.Mutex // guards memos only; values are built outside it
+	memos   map[string]memoEntry
+	flights syncx.SingleFlight
+}
+
+// memoEntry is a value derived from data files, valid while their
+// modification times are unchanged.
+type memoEntry struct {
+	mods  []time.Time
+	value interface{}
 }
 
 func NewDataLoader(dataPath string) *DataLoader {
 	return &DataLoader{
 		dataPath: dataPath,
+		flights:  syncx.NewSingleFlight(),
 	}
 }
 
 // LoadCryptoPrices loads crypto prices from JSON file
 func (dl *DataLoader) LoadCryptoPrices() (*types.CryptoPricesResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceFile, "crypto_prices", time.Now())
 	var response types.CryptoPricesResponse
 	err := dl.loadJSONFile("crypto-prices.json", &response)
+	if err == nil {
+		response.DataAsOf = dl.dataAsOf(pricesAsOf(&response), "crypto-prices.json")
+	}
 	return &response, err
 }
 
 // LoadAccountTotals loads account totals from JSON file
 func (dl *DataLoader) LoadAccountTotals() (*types.AccountTotalsResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceFile, "account_totals", time.Now())
 	var response types.AccountTotalsResponse
 	err := dl.loadJSONFile("account-totals.json", &response)
 	if err != nil {
 		return nil, err
 	}
 	response.ServerTime = getCurrentTimestamp()
+	response.DataAsOf = dl.dataAsOf(accountTotalsAsOf(&response), "account-totals.json")
 	return &response, nil
 }
 
 // LoadTrades loads trades from JSON file
 func (dl *DataLoader) LoadTrades() (*types.TradesResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceFile, "trades", time.Now())
 	var data struct {
 		Trades []types.Trade `json:"trades"`
 	}
@@ -51,40 +80,52 @@ func (dl *DataLoader) LoadTrades() (*types.TradesResponse, error) {
 	return &types.TradesResponse{
 		Trades:     data.Trades,
 		ServerTime: getCurrentTimestamp(),
+		DataAsOf:   dl.dataAsOf(tradesAsOf(data.Trades), "trades.json"),
 	}, nil
 }
 
 // LoadSinceInception loads since inception values from JSON file
 func (dl *DataLoader) LoadSinceInception() (*types.SinceInceptionResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceFile, "since_inception", time.Now())
 	var response types.SinceInceptionResponse
 	err := dl.loadJSONFile("since-inception-values.json", &response)
 	if err != nil {
 		return nil, err
 	}
 	response.ServerTime = getCurrentTimestamp()
+	// Values are dated by inception, so the file time says how current they are.
+	response.DataAsOf = dl.dataAsOf(0, "since-inception-values.json")
 	return &response, nil
 }
 
 // LoadLeaderboard loads leaderboard from JSON file
 func (dl *DataLoader) LoadLeaderboard() (*types.LeaderboardResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceFile, "leaderboard", time.Now())
 	var response types.LeaderboardResponse
 	err := dl.loadJSONFile("leaderboard.json", &response)
+	if err == nil {
+		response.DataAsOf = dl.dataAsOf(0, "leaderboard.json")
+	}
 	return &response, err
 }
 
 // LoadAnalytics loads all analytics from JSON file
 func (dl *DataLoader) LoadAnalytics() (*types.AnalyticsResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceFile, "analytics", time.Now())
 	var response types.AnalyticsResponse
 	err := dl.loadJSONFile("analytics.json", &response)
 	if err != nil {
 		return nil, err
 	}
 	response.ServerTime = getCurrentTimestamp()
+	response.DataAsOf = dl.dataAsOf(analyticsAsOf(response.Analytics...), "analytics.json")
 	return &response, nil
 }
 
-// LoadModelAnalytics loads analytics for a specific model
+// LoadModelAnalytics loads analytics for a specific model; unknown models
+// are an errs.KindNotFound error.
 func (dl *DataLoader) LoadModelAnalytics(modelId string) (*types.ModelAnalyticsResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceFile, "model_analytics", time.Now())
 	// Try to load model-specific file first
 	var analytics types.ModelAnalytics
 	filename := "analytics-" + modelId + ".json"
@@ -98,6 +139,7 @@ func (dl *DataLoader) LoadModelAnalytics(modelId string) (*types.ModelAnalyticsR
 		return &types.ModelAnalyticsResponse{
 			Analytics:  data.Analytics,
 			ServerTime: getCurrentTimestamp(),
+			DataAsOf:   dl.dataAsOf(analyticsAsOf(data.Analytics), filename),
 		}, nil
 	}
 
@@ -114,23 +156,32 @@ func (dl *DataLoader) LoadModelAnalytics(modelId string) (*types.ModelAnalyticsR
 			return &types.ModelAnalyticsResponse{
 				Analytics:  analytics,
 				ServerTime: getCurrentTimestamp(),
+				DataAsOf:   dl.dataAsOf(analyticsAsOf(analytics), "analytics.json"),
 			}, nil
 		}
 	}
 
-	// Return empty analytics if not found
-	return &types.ModelAnalyticsResponse{
-		Analytics: types.ModelAnalytics{
-			ModelId: modelId,
-		},
-		ServerTime: getCurrentTimestamp(),
-	}, nil
+	return nil, errs.NotFound("no analytics for model %s", modelId)
 }
 
-// Helper function to load JSON file
+// Helper function to load JSON file; a gzipped copy (name.json.gz, as in
+// recorded snapshots) is read when the plain file does not exist.
 func (dl *DataLoader) loadJSONFile(filename string, v interface{}) error {
+	defer func(start time.Time) {
+		metrics.ParseDuration.ObserveFloat(float64(time.Since(start))/float64(time.Millisecond), filename)
+	}(time.Now())
 	filePath := filepath.Join(dl.dataPath, filename)
 	data, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		if f, gzErr := os.Open(filePath + ".gz"); gzErr == nil {
+			defer f.Close()
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				return err
+			}
+			return json.NewDecoder(zr).Decode(v)
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -139,6 +190,7 @@ func (dl *DataLoader) loadJSONFile(filename string, v interface{}) error {
 
 // LoadPositions loads positions from JSON file
 func (dl *DataLoader) LoadPositions() (*types.PositionsResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceFile, "positions", time.Now())
 	var data struct {
 		AccountTotals []types.PositionsByModel `json:"accountTotals"`
 	}
@@ -147,14 +199,26 @@ func (dl *DataLoader) LoadPositions() (*types.PositionsResponse, error) {
 		return nil, err
 	}
 
+	// Positions carry no update time, so the file time says how current they are.
 	return &types.PositionsResponse{
 		AccountTotals: data.AccountTotals,
 		ServerTime:    getCurrentTimestamp(),
+		DataAsOf:      dl.dataAsOf(0, "positions.json"),
 	}, nil
 }
 
+// PositionSide is the direction of a position, whose quantity is signed:
+// negative for shorts.
+func PositionSide(quantity float64) string {
+	if quantity < 0 {
+		return "short"
+	}
+	return "long"
+}
+
 // LoadConversations loads conversations from JSON file
 func (dl *DataLoader) LoadConversations() (*types.ConversationsResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceFile, "conversations", time.Now())
 	var data struct {
 		Conversations []types.Conversation `json:"conversations"`
 	}
@@ -166,9 +230,76 @@ func (dl *DataLoader) LoadConversations() (*types.ConversationsResponse, error)
 	return &types.ConversationsResponse{
 		Conversations: data.Conversations,
 		ServerTime:    getCurrentTimestamp(),
+		DataAsOf:      dl.dataAsOf(conversationsAsOf(data.Conversations), "conversations.json"),
 	}, nil
 }
 
+// FileTimes returns the modification time of each data file (*.json, or
+// *.json.gz in snapshots), keyed by file name.
+func (dl *DataLoader) FileTimes() map[string]time.Time {
+	out := map[string]time.Time{}
+	entries, _ := os.ReadDir(dl.dataPath)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !(strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".json.gz")) {
+			continue
+		}
+		if info, err := e.Info(); err == nil {
+			out[name] = info.ModTime()
+		}
+	}
+	return out
+}
+
+// memo returns build's cached result for key, rebuilding it when any of
+// files (relative to the data path) has changed since it was built.
+func (dl *DataLoader) memo(key string, files []string, build func() (interface{}, error)) (interface{}, error) {
+	mods := make([]time.Time, len(files))
+	for i, f := range files {
+		if info, err := os.Stat(filepath.Join(dl.dataPath, f)); err == nil {
+			mods[i] = info.ModTime()
+		}
+	}
+
+	dl.mu.Lock()
+	e, ok := dl.memos[key]
+	dl.mu.Unlock()
+	if ok && equalTimes(e.mods, mods) {
+		return e.value, nil
+	}
+
+	// Concurrent callers that saw the same file versions share one build.
+	flight := key
+	for _, m := range mods {
+		flight += "@" + strconv.FormatInt(m.UnixNano(), 10)
+	}
+	return dl.flights.Do(flight, func() (interface{}, error) {
+		v, err := build()
+		if err != nil {
+			return nil, err
+		}
+		dl.mu.Lock()
+		if dl.memos == nil {
+			dl.memos = map[string]memoEntry{}
+		}
+		dl.memos[key] = memoEntry{mods: mods, value: v}
+		dl.mu.Unlock()
+		return v, nil
+	})
+}
+
+func equalTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // getCurrentTimestamp returns current timestamp in milliseconds
 func getCurrentTimestamp() int64 {
 	return time.Now().UnixMilli()
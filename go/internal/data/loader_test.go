@@ -1,10 +1,15 @@
 package data
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/errs"
 )
 
 const testDataPath = "../../../mcp/data"
@@ -188,10 +193,8 @@ func TestLoadModelAnalytics(t *testing.T) {
 				assert.NotZero(t, resp.ServerTime)
 				assert.Equal(t, tc.modelId, resp.Analytics.ModelId)
 			} else {
-				// For non-existent models, should still return empty analytics
-				// (fallback behavior)
-				require.NotNil(t, resp)
-				assert.Equal(t, tc.modelId, resp.Analytics.ModelId)
+				assert.True(t, errs.Is(err, errs.KindNotFound), "Unknown models should be not found")
+				assert.Nil(t, resp)
 			}
 		})
 	}
@@ -355,3 +358,49 @@ func BenchmarkLoadConversations(b *testing.B) {
 		_, _ = loader.LoadConversations()
 	}
 }
+
+func TestMemoBuildsOutsideTheLock(t *testing.T) {
+	dir := t.TempDir()
+	writeArenaFile(t, dir, "trades.json", `{"trades":[]}`)
+	dl := NewDataLoader(dir)
+
+	var builds atomic.Int32
+	release := make(chan struct{})
+	slow := func() (interface{}, error) {
+		builds.Add(1)
+		<-release
+		return "slow", nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := dl.memo("slow", []string{"trades.json"}, slow)
+			assert.NoError(t, err)
+			assert.Equal(t, "slow", v)
+		}()
+	}
+
+	// Another key is served while "slow" is still building.
+	done := make(chan struct{})
+	go func() {
+		v, err := dl.memo("fast", []string{"trades.json"}, func() (interface{}, error) { return "fast", nil })
+		assert.NoError(t, err)
+		assert.Equal(t, "fast", v)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("memo blocked on another key's build")
+	}
+
+	close(release)
+	wg.Wait()
+	n := builds.Load()
+	v, err := dl.memo("slow", []string{"trades.json"}, slow)
+	require.NoError(t, err)
+	assert.Equal(t, "slow", v)
+	assert.Equal(t, n, builds.Load(), "Memoized value is reused")
+}
@@ -0,0 +1,399 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"nof0-api/internal/types"
+)
+
+// Issue severities. Errors make the data unfit for import; warnings are
+// reported but do not block it.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Timestamps outside [minPlausibleMs, now+maxClockAhead] are rejected.
+var minPlausibleMs = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+const maxClockAhead = 24 * time.Hour
+
+// ValidationIssue is one problem found in a data file.
+type ValidationIssue struct {
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Path     string `json:"path,omitempty"` // e.g. trades[3].exit_time
+	Check    string `json:"check"`
+	Message  string `json:"message"`
+}
+
+// ValidationFile summarizes one data file.
+type ValidationFile struct {
+	File     string `json:"file"`
+	Rows     int    `json:"rows"`
+	Missing  bool   `json:"missing,omitempty"`
+	Errors   int    `json:"errors"`
+	Warnings int    `json:"warnings"`
+}
+
+// ValidationReport is the result of DataLoader.Validate.
+type ValidationReport struct {
+	DataPath string            `json:"dataPath"`
+	Files    []ValidationFile  `json:"files"`
+	Issues   []ValidationIssue `json:"issues"`
+	Errors   int               `json:"errors"`
+	Warnings int               `json:"warnings"`
+}
+
+// OK reports whether no errors were found.
+func (r *ValidationReport) OK() bool {
+	return r.Errors == 0
+}
+
+type validator struct {
+	report  *ValidationReport
+	now     int64
+	file    *ValidationFile
+	units   map[string]struct{} // timestamp units seen in the current file
+	symbols map[string]struct{} // known symbols; nil when crypto-prices.json is unavailable
+	models  map[string]struct{} // leaderboard models; nil when leaderboard.json is unavailable
+}
+
+// Validate parses every data file and checks value sanity and referential
+// integrity without side effects: symbols must be priced in
+// crypto-prices.json, models should be on the leaderboard, timestamps must
+// be plausible and use one unit per file, trades must exit after entering
+// with a quantity whose sign matches the side, and trade ids must be unique.
+// Values the import fills in (a message's role, an invocation's status) are
+// warned about when missing.
+func (dl *DataLoader) Validate() *ValidationReport {
+	v := &validator{
+		report: &ValidationReport{DataPath: dl.dataPath, Issues: []ValidationIssue{}},
+		now:    time.Now().UnixMilli(),
+	}
+
+	// Reference data first: the universe of symbols and models.
+	prices, err := dl.LoadCryptoPrices()
+	if v.begin("crypto-prices.json", err) {
+		v.symbols = map[string]struct{}{}
+		syms := make([]string, 0, len(prices.Prices))
+		for sym := range prices.Prices {
+			syms = append(syms, sym)
+		}
+		sort.Strings(syms)
+		for _, sym := range syms {
+			p := prices.Prices[sym]
+			v.file.Rows++
+			v.symbols[sym] = struct{}{}
+			path := fmt.Sprintf("prices.%s", sym)
+			if p.Symbol != "" && p.Symbol != sym {
+				v.errorf(path+".symbol", "reference", "symbol %q does not match key %q", p.Symbol, sym)
+			}
+			v.positive(path+".price", p.Price)
+			v.timestamp(path+".timestamp", float64(p.Timestamp), true)
+		}
+		v.end()
+	}
+	lb, err := dl.LoadLeaderboard()
+	if v.begin("leaderboard.json", err) {
+		v.models = map[string]struct{}{}
+		for i, e := range lb.Leaderboard {
+			v.file.Rows++
+			if e.Id == "" {
+				v.errorf(fmt.Sprintf("leaderboard[%d].id", i), "required", "missing model id")
+				continue
+			}
+			v.models[e.Id] = struct{}{}
+		}
+		v.end()
+	}
+
+	trades, err := dl.LoadTrades()
+	if v.begin("trades.json", err) {
+		v.trades(trades.Trades)
+		v.end()
+	}
+	positions, err := dl.LoadPositions()
+	if v.begin("positions.json", err) {
+		for i, pm := range positions.AccountTotals {
+			v.positions(fmt.Sprintf("accountTotals[%d]", i), pm.ModelId, pm.Positions)
+		}
+		v.end()
+	}
+	totals, err := dl.LoadAccountTotals()
+	if v.begin("account-totals.json", err) {
+		for i, at := range totals.AccountTotals {
+			path := fmt.Sprintf("accountTotals[%d]", i)
+			v.timestamp(path+".timestamp", at.Timestamp, true)
+			v.positions(path, at.ModelId, at.Positions)
+		}
+		v.end()
+	}
+	si, err := dl.LoadSinceInception()
+	if v.begin("since-inception-values.json", err) {
+		for i, s := range si.SinceInceptionValues {
+			v.file.Rows++
+			path := fmt.Sprintf("sinceInceptionValues[%d]", i)
+			v.model(path+".model_id", s.ModelId)
+			v.timestamp(path+".inception_date", s.InceptionDate, false)
+		}
+		v.end()
+	}
+	analytics, err := dl.LoadAnalytics()
+	if v.begin("analytics.json", err) {
+		for i, a := range analytics.Analytics {
+			v.file.Rows++
+			path := fmt.Sprintf("analytics[%d]", i)
+			v.model(path+".model_id", a.ModelId)
+			v.timestamp(path+".updated_at", a.UpdatedAt, false)
+		}
+		v.end()
+	}
+
+	conversations := 0
+	convs, err := dl.LoadConversations()
+	if v.begin(conversationsFile, err) {
+		conversations = len(convs.Conversations)
+		v.conversations(convs.Conversations)
+		v.end()
+	}
+	var invs struct {
+		Invocations []types.Invocation `json:"invocations"`
+	}
+	if v.begin(invocationsFile, dl.loadJSONFile(invocationsFile, &invs)) {
+		v.invocations(invs.Invocations, conversations)
+		v.end()
+	}
+	return v.report
+}
+
+// begin starts a file; it reports whether the file parsed and should be
+// checked further.
+func (v *validator) begin(file string, err error) bool {
+	v.report.Files = append(v.report.Files, ValidationFile{File: file})
+	v.file = &v.report.Files[len(v.report.Files)-1]
+	v.units = map[string]struct{}{}
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		v.file.Missing = true
+		return false
+	case err != nil:
+		v.errorf("", "parse", "%v", err)
+		return false
+	}
+	return true
+}
+
+func (v *validator) end() {
+	if len(v.units) > 1 {
+		v.warnf("", "timestamp_unit", "timestamps mix seconds and milliseconds")
+	}
+}
+
+func (v *validator) add(severity, path, check, format string, args ...interface{}) {
+	v.report.Issues = append(v.report.Issues, ValidationIssue{
+		Severity: severity,
+		File:     v.file.File,
+		Path:     path,
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+	})
+	if severity == SeverityError {
+		v.report.Errors++
+		v.file.Errors++
+	} else {
+		v.report.Warnings++
+		v.file.Warnings++
+	}
+}
+
+func (v *validator) errorf(path, check, format string, args ...interface{}) {
+	v.add(SeverityError, path, check, format, args...)
+}
+
+func (v *validator) warnf(path, check, format string, args ...interface{}) {
+	v.add(SeverityWarning, path, check, format, args...)
+}
+
+// timestamp checks a seconds-or-ms timestamp; zero is allowed unless required.
+func (v *validator) timestamp(path string, ts float64, required bool) int64 {
+	if ts == 0 {
+		if required {
+			v.errorf(path, "required", "missing timestamp")
+		}
+		return 0
+	}
+	if ts < secondsCutoff {
+		v.units["s"] = struct{}{}
+	} else {
+		v.units["ms"] = struct{}{}
+	}
+	ms := ToMillis(ts)
+	if ms < minPlausibleMs || ms > v.now+maxClockAhead.Milliseconds() {
+		v.errorf(path, "timestamp_range", "timestamp %v is out of range", ts)
+	}
+	return ms
+}
+
+func (v *validator) positive(path string, x float64) {
+	if x <= 0 {
+		v.errorf(path, "range", "must be positive, got %v", x)
+	}
+}
+
+func (v *validator) symbol(path, sym string) {
+	if sym == "" {
+		v.errorf(path, "required", "missing symbol")
+		return
+	}
+	if _, ok := v.symbols[sym]; v.symbols != nil && !ok {
+		v.errorf(path, "symbol_known", "unknown symbol %q", sym)
+	}
+}
+
+func (v *validator) model(path, modelId string) {
+	if modelId == "" {
+		v.errorf(path, "required", "missing model id")
+		return
+	}
+	if _, ok := v.models[modelId]; v.models != nil && !ok {
+		v.warnf(path, "model_known", "model %q is not on the leaderboard", modelId)
+	}
+}
+
+func (v *validator) confidence(path string, c float64) {
+	if c < 0 || c > 1 {
+		v.errorf(path, "range", "confidence must be within [0,1], got %v", c)
+	}
+}
+
+func (v *validator) trades(trades []types.Trade) {
+	seen := map[string]int{}
+	for i, t := range trades {
+		v.file.Rows++
+		path := fmt.Sprintf("trades[%d]", i)
+		if t.Id == "" {
+			v.errorf(path+".id", "required", "missing trade id")
+		} else if first, ok := seen[t.Id]; ok {
+			v.errorf(path+".id", "duplicate_id", "trade id %q duplicates trades[%d]", t.Id, first)
+		} else {
+			seen[t.Id] = i
+		}
+		v.model(path+".model_id", t.ModelId)
+		v.symbol(path+".symbol", t.Symbol)
+
+		switch t.Side {
+		case "long", "short":
+			if t.TradeType != "" && t.TradeType != t.Side {
+				v.errorf(path+".trade_type", "side", "trade_type %q contradicts side %q", t.TradeType, t.Side)
+			}
+		default:
+			v.errorf(path+".side", "side", "side must be long or short, got %q", t.Side)
+		}
+		switch {
+		case t.Quantity == 0:
+			v.errorf(path+".quantity", "range", "quantity is zero")
+		case t.Side == "long" && t.Quantity < 0, t.Side == "short" && t.Quantity > 0:
+			v.errorf(path+".quantity", "quantity_sign", "quantity %v is inconsistent with side %q", t.Quantity, t.Side)
+		}
+
+		v.positive(path+".entry_price", t.EntryPrice)
+		if t.ExitTime != 0 {
+			v.positive(path+".exit_price", t.ExitPrice)
+		}
+		if t.Leverage < 0 {
+			v.errorf(path+".leverage", "range", "leverage must not be negative, got %v", t.Leverage)
+		}
+		v.confidence(path+".confidence", t.Confidence)
+
+		entry := v.timestamp(path+".entry_time", t.EntryTime, true)
+		exit := v.timestamp(path+".exit_time", t.ExitTime, false)
+		if entry > 0 && exit > 0 && entry >= exit {
+			v.errorf(path+".exit_time", "entry_before_exit", "exit_time %v is not after entry_time %v", t.ExitTime, t.EntryTime)
+		}
+	}
+}
+
+func (v *validator) positions(path, modelId string, positions map[string]types.Position) {
+	v.model(path+".model_id", modelId)
+	syms := make([]string, 0, len(positions))
+	for sym := range positions {
+		syms = append(syms, sym)
+	}
+	sort.Strings(syms)
+	for _, sym := range syms {
+		p := positions[sym]
+		v.file.Rows++
+		ppath := fmt.Sprintf("%s.positions.%s", path, sym)
+		v.symbol(ppath, sym)
+		if p.Symbol != "" && p.Symbol != sym {
+			v.errorf(ppath+".symbol", "reference", "symbol %q does not match key %q", p.Symbol, sym)
+		}
+		if p.Quantity == 0 {
+			v.errorf(ppath+".quantity", "range", "quantity is zero")
+		}
+		v.positive(ppath+".entry_price", p.EntryPrice)
+		if p.Leverage < 0 {
+			v.errorf(ppath+".leverage", "range", "leverage must not be negative, got %v", p.Leverage)
+		}
+		v.confidence(ppath+".confidence", p.Confidence)
+		v.timestamp(ppath+".entry_time", p.EntryTime, true)
+	}
+}
+
+func (v *validator) conversations(conversations []types.Conversation) {
+	for i, c := range conversations {
+		v.file.Rows++
+		path := fmt.Sprintf("conversations[%d]", i)
+		v.model(path+".model_id", c.ModelId)
+		for j, m := range c.Messages {
+			mpath := fmt.Sprintf("%s.messages[%d]", path, j)
+			switch m.Role {
+			case "":
+				v.warnf(mpath+".role", "default", "missing role; imported as assistant")
+			case "system", "user", "assistant":
+			default:
+				v.errorf(mpath+".role", "range", "unknown role %q", m.Role)
+			}
+			if f, ok := m.Timestamp.(float64); ok {
+				v.timestamp(mpath+".timestamp", f, false)
+			} else if m.Timestamp != nil && messageMillis(m.Timestamp) == 0 {
+				v.errorf(mpath+".timestamp", "timestamp_range", "unparseable timestamp %v", m.Timestamp)
+			}
+		}
+	}
+}
+
+func (v *validator) invocations(invocations []types.Invocation, conversations int) {
+	seen := map[string]int{}
+	for i, inv := range invocations {
+		v.file.Rows++
+		path := fmt.Sprintf("invocations[%d]", i)
+		if first, ok := seen[inv.Id]; ok && inv.Id != "" {
+			v.errorf(path+".id", "duplicate_id", "invocation id %q duplicates invocations[%d]", inv.Id, first)
+		} else if inv.Id == "" {
+			v.errorf(path+".id", "required", "missing invocation id")
+		} else {
+			seen[inv.Id] = i
+		}
+		v.model(path+".model_id", inv.ModelId)
+		v.timestamp(path+".timestamp", float64(inv.Timestamp), true)
+		if inv.ConversationId < 0 || int(inv.ConversationId) > conversations {
+			v.errorf(path+".conversation_id", "reference", "conversation %d does not exist", inv.ConversationId)
+		}
+		switch inv.Status {
+		case "":
+			v.warnf(path+".status", "default", "missing status; imported as %s", InvocationStatusOk)
+		case InvocationStatusOk, InvocationStatusError:
+		default:
+			v.errorf(path+".status", "range", "status must be ok or error, got %q", inv.Status)
+		}
+		if inv.LatencyMs < 0 || inv.InputTokens < 0 || inv.OutputTokens < 0 || inv.CostUsd < 0 {
+			v.errorf(path, "range", "latency, tokens and cost must not be negative")
+		}
+	}
+}
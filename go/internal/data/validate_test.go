@@ -0,0 +1,63 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFixture(t *testing.T) {
+	report := NewDataLoader(testDataPath).Validate()
+	assert.True(t, report.OK(), "Fixture data should import cleanly: %+v", report.Issues)
+
+	files := map[string]ValidationFile{}
+	for _, f := range report.Files {
+		files[f.File] = f
+	}
+	assert.Equal(t, 230, files["trades.json"].Rows)
+	assert.True(t, files["account-totals.json"].Missing)
+}
+
+func TestValidateFindsProblems(t *testing.T) {
+	dir := t.TempDir()
+	writeArenaFile(t, dir, "crypto-prices.json", `{"prices":{"BTC":{"symbol":"BTC","price":100,"timestamp":1760738000000}}}`)
+	writeArenaFile(t, dir, "leaderboard.json", `{"leaderboard":[{"id":"a"}]}`)
+	writeArenaFile(t, dir, "trades.json", `{"trades":[
+		{"id":"t1","model_id":"a","symbol":"BTC","side":"long","quantity":1,"entry_price":100,"exit_price":101,"entry_time":1760738000,"exit_time":1760739000},
+		{"id":"t1","model_id":"a","symbol":"DOGE","side":"short","quantity":2,"entry_price":100,"exit_price":101,"entry_time":1760739000,"exit_time":1760738000000},
+		{"id":"t3","model_id":"b","symbol":"BTC","side":"long","quantity":1,"entry_price":100,"entry_time":17607380}]}`)
+	writeArenaFile(t, dir, "positions.json", `{"accountTotals":[{"model_id":"a","positions":{"BTC":{"symbol":"BTC","entry_price":100,"quantity":0,"entry_time":1760738000,"confidence":2}}}]}`)
+	writeArenaFile(t, dir, conversationsFile, `{"conversations":[{"model_id":"a","messages":[{"role":"robot","content":"hi"},{"content":"ok"}]}]}`)
+	writeArenaFile(t, dir, invocationsFile, `{"invocations":[{"id":"i1","model_id":"a","timestamp":1760738000000,"conversation_id":1}]}`)
+	writeArenaFile(t, dir, "analytics.json", `{"analytics":[{"model_id": 5}]}`)
+
+	report := NewDataLoader(dir).Validate()
+	assert.False(t, report.OK())
+
+	checks := map[string][]string{}
+	for _, issue := range report.Issues {
+		checks[issue.File] = append(checks[issue.File], issue.Path+" "+issue.Check)
+	}
+	assert.ElementsMatch(t, []string{
+		"trades[1].id duplicate_id",
+		"trades[1].symbol symbol_known",
+		"trades[1].quantity quantity_sign",
+		"trades[1].exit_time entry_before_exit",
+		"trades[2].model_id model_known",
+		"trades[2].entry_time timestamp_range",
+		" timestamp_unit",
+	}, checks["trades.json"])
+	assert.ElementsMatch(t, []string{
+		"accountTotals[0].positions.BTC.quantity range",
+		"accountTotals[0].positions.BTC.confidence range",
+	}, checks["positions.json"])
+	assert.Equal(t, []string{
+		"conversations[0].messages[0].role range",
+		"conversations[0].messages[1].role default",
+	}, checks[conversationsFile])
+	assert.Equal(t, []string{"invocations[0].status default"}, checks[invocationsFile], "Values the import fills in only warn")
+	assert.Equal(t, []string{" parse"}, checks["analytics.json"], "Malformed files are reported, not fatal")
+
+	assert.Equal(t, 9, report.Errors)
+	assert.Equal(t, 4, report.Warnings, "Unknown models, mixed units and defaulted values only warn")
+}
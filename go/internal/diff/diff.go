@@ -0,0 +1,379 @@
+// Package diff compares two versions of an arena's data set, e.g. two
+// recorded snapshots or a snapshot and the live files, and reports what
+// changed between them.
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/types"
+)
+
+// positionFields are the position values compared between versions.
+var positionFields = []string{"quantity", "entry_price", "leverage"}
+
+// Compare reports what changed from old to new. Analytics metrics are listed
+// when they moved by more than threshold relative to their old value (0.01
+// is 1%); any change from zero counts. A file missing on either side is
+// treated as empty, so a partial data set still compares.
+func Compare(old, new data.DataSource, threshold float64) (*types.DiffResponse, error) {
+	d := &types.DiffResponse{Threshold: threshold, ServerTime: time.Now().UnixMilli()}
+	if err := comparePositions(d, old, new); err != nil {
+		return nil, err
+	}
+	if err := compareTrades(d, old, new); err != nil {
+		return nil, err
+	}
+	if err := compareLeaderboard(d, old, new); err != nil {
+		return nil, err
+	}
+	if err := compareAnalytics(d, old, new, threshold); err != nil {
+		return nil, err
+	}
+	if err := compareConversations(d, old, new); err != nil {
+		return nil, err
+	}
+	d.Summary = types.DiffSummary{
+		NewPositions:      len(d.NewPositions),
+		ClosedPositions:   len(d.ClosedPositions),
+		ModifiedPositions: len(d.ModifiedPositions),
+		NewTrades:         len(d.NewTrades),
+		RankChanges:       len(d.RankChanges),
+		AnalyticsDeltas:   len(d.AnalyticsDeltas),
+		NewMessages:       len(d.NewMessages),
+	}
+	return d, nil
+}
+
+// load calls fn on both sides, turning a missing file into a nil result.
+func load[T any](old, new data.DataSource, fn func(data.DataSource) (*T, error)) (*T, *T, error) {
+	get := func(ds data.DataSource) (*T, error) {
+		v, err := fn(ds)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return v, err
+	}
+	a, err := get(old)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := get(new)
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, b, nil
+}
+
+type positionKey struct{ model, symbol string }
+
+func positionIndex(r *types.PositionsResponse) map[positionKey]types.Position {
+	idx := map[positionKey]types.Position{}
+	if r == nil {
+		return idx
+	}
+	for _, m := range r.AccountTotals {
+		for sym, p := range m.Positions {
+			if p.Symbol == "" {
+				p.Symbol = sym
+			}
+			idx[positionKey{m.ModelId, p.Symbol}] = p
+		}
+	}
+	return idx
+}
+
+func sortedKeys(m map[positionKey]types.Position) []positionKey {
+	keys := make([]positionKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].model != keys[j].model {
+			return keys[i].model < keys[j].model
+		}
+		return keys[i].symbol < keys[j].symbol
+	})
+	return keys
+}
+
+func toDiffPosition(k positionKey, p types.Position) types.DiffPosition {
+	return types.DiffPosition{
+		ModelId:    k.model,
+		Symbol:     k.symbol,
+		Side:       data.PositionSide(p.Quantity),
+		Quantity:   p.Quantity,
+		EntryPrice: p.EntryPrice,
+		Leverage:   p.Leverage,
+	}
+}
+
+func positionValue(p types.Position, field string) float64 {
+	switch field {
+	case "quantity":
+		return p.Quantity
+	case "entry_price":
+		return p.EntryPrice
+	default:
+		return p.Leverage
+	}
+}
+
+func comparePositions(d *types.DiffResponse, old, new data.DataSource) error {
+	a, b, err := load(old, new, data.DataSource.LoadPositions)
+	if err != nil {
+		return err
+	}
+	before, after := positionIndex(a), positionIndex(b)
+	d.NewPositions, d.ClosedPositions, d.ModifiedPositions = []types.DiffPosition{}, []types.DiffPosition{}, []types.PositionChange{}
+	for _, k := range sortedKeys(after) {
+		p := after[k]
+		q, ok := before[k]
+		if !ok {
+			d.NewPositions = append(d.NewPositions, toDiffPosition(k, p))
+			continue
+		}
+		var changes []types.FieldDelta
+		for _, f := range positionFields {
+			if o, n := positionValue(q, f), positionValue(p, f); o != n {
+				changes = append(changes, types.FieldDelta{Field: f, Old: o, New: n, Delta: n - o})
+			}
+		}
+		if len(changes) > 0 {
+			d.ModifiedPositions = append(d.ModifiedPositions, types.PositionChange{ModelId: k.model, Symbol: k.symbol, Changes: changes})
+		}
+	}
+	for _, k := range sortedKeys(before) {
+		if _, ok := after[k]; !ok {
+			d.ClosedPositions = append(d.ClosedPositions, toDiffPosition(k, before[k]))
+		}
+	}
+	return nil
+}
+
+func compareTrades(d *types.DiffResponse, old, new data.DataSource) error {
+	a, b, err := load(old, new, data.DataSource.LoadTrades)
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	if a != nil {
+		for _, t := range a.Trades {
+			seen[t.Id] = true
+		}
+	}
+	d.NewTrades = []types.Trade{}
+	if b != nil {
+		for _, t := range b.Trades {
+			if !seen[t.Id] {
+				d.NewTrades = append(d.NewTrades, t)
+			}
+		}
+	}
+	sort.SliceStable(d.NewTrades, func(i, j int) bool { return d.NewTrades[i].EntryTime < d.NewTrades[j].EntryTime })
+	return nil
+}
+
+type rank struct {
+	pos       int // 1-based
+	returnPct float64
+}
+
+// ranking orders the leaderboard by return, best first.
+func ranking(r *types.LeaderboardResponse) map[string]rank {
+	out := map[string]rank{}
+	if r == nil {
+		return out
+	}
+	entries := append([]types.LeaderboardEntry(nil), r.Leaderboard...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].ReturnPct != entries[j].ReturnPct {
+			return entries[i].ReturnPct > entries[j].ReturnPct
+		}
+		return entries[i].Id < entries[j].Id
+	})
+	for i, e := range entries {
+		out[e.Id] = rank{pos: i + 1, returnPct: e.ReturnPct}
+	}
+	return out
+}
+
+func compareLeaderboard(d *types.DiffResponse, old, new data.DataSource) error {
+	a, b, err := load(old, new, data.DataSource.LoadLeaderboard)
+	if err != nil {
+		return err
+	}
+	before, after := ranking(a), ranking(b)
+	ids := map[string]bool{}
+	for id := range before {
+		ids[id] = true
+	}
+	for id := range after {
+		ids[id] = true
+	}
+	d.RankChanges = []types.RankChange{}
+	for id := range ids {
+		o, n := before[id], after[id]
+		if o.pos == n.pos {
+			continue
+		}
+		d.RankChanges = append(d.RankChanges, types.RankChange{
+			ModelId:      id,
+			OldRank:      o.pos,
+			NewRank:      n.pos,
+			OldReturnPct: o.returnPct,
+			NewReturnPct: n.returnPct,
+		})
+	}
+	sort.Slice(d.RankChanges, func(i, j int) bool {
+		ri, rj := d.RankChanges[i].NewRank, d.RankChanges[j].NewRank
+		if (ri == 0) != (rj == 0) {
+			return rj == 0
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return d.RankChanges[i].ModelId < d.RankChanges[j].ModelId
+	})
+	return nil
+}
+
+// metrics flattens a model's analytics into dotted metric paths, leaving out
+// ids and timestamps, which change on every update.
+func metrics(a types.ModelAnalytics) (map[string]float64, error) {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]any
+	if err := json.Unmarshal(b, &tree); err != nil {
+		return nil, err
+	}
+	out := map[string]float64{}
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, child := range v {
+				if k == "updated_at" || strings.HasSuffix(k, "_time") || strings.HasSuffix(k, "_timestamp") {
+					continue
+				}
+				if prefix != "" {
+					k = prefix + "." + k
+				}
+				walk(k, child)
+			}
+		case float64:
+			out[prefix] = v
+		}
+	}
+	walk("", tree)
+	return out, nil
+}
+
+func analyticsIndex(r *types.AnalyticsResponse) (map[string]map[string]float64, error) {
+	idx := map[string]map[string]float64{}
+	if r == nil {
+		return idx, nil
+	}
+	for _, a := range r.Analytics {
+		m, err := metrics(a)
+		if err != nil {
+			return nil, err
+		}
+		id := a.ModelId
+		if id == "" {
+			id = a.Id
+		}
+		idx[id] = m
+	}
+	return idx, nil
+}
+
+func compareAnalytics(d *types.DiffResponse, old, new data.DataSource, threshold float64) error {
+	a, b, err := load(old, new, data.DataSource.LoadAnalytics)
+	if err != nil {
+		return err
+	}
+	before, err := analyticsIndex(a)
+	if err != nil {
+		return err
+	}
+	after, err := analyticsIndex(b)
+	if err != nil {
+		return err
+	}
+	d.AnalyticsDeltas = []types.AnalyticsDelta{}
+	// Only models present on both sides are compared; a model appearing or
+	// disappearing shows up in the rank changes.
+	for model, n := range after {
+		o, ok := before[model]
+		if !ok {
+			continue
+		}
+		names := map[string]bool{}
+		for k := range o {
+			names[k] = true
+		}
+		for k := range n {
+			names[k] = true
+		}
+		// Zero values are omitted from the tables, so a missing metric is 0.
+		for metric := range names {
+			ov, nv := o[metric], n[metric]
+			delta := nv - ov
+			if delta == 0 || (ov != 0 && math.Abs(delta) <= threshold*math.Abs(ov)) {
+				continue
+			}
+			d.AnalyticsDeltas = append(d.AnalyticsDeltas, types.AnalyticsDelta{ModelId: model, Metric: metric, Old: ov, New: nv, Delta: delta})
+		}
+	}
+	sort.Slice(d.AnalyticsDeltas, func(i, j int) bool {
+		if d.AnalyticsDeltas[i].ModelId != d.AnalyticsDeltas[j].ModelId {
+			return d.AnalyticsDeltas[i].ModelId < d.AnalyticsDeltas[j].ModelId
+		}
+		return d.AnalyticsDeltas[i].Metric < d.AnalyticsDeltas[j].Metric
+	})
+	return nil
+}
+
+func messageKey(model string, m types.ConversationMessage) string {
+	return fmt.Sprintf("%s\x00%s\x00%v\x00%s", model, m.Role, m.Timestamp, m.Content)
+}
+
+func compareConversations(d *types.DiffResponse, old, new data.DataSource) error {
+	a, b, err := load(old, new, data.DataSource.LoadConversations)
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	if a != nil {
+		for _, c := range a.Conversations {
+			for _, m := range c.Messages {
+				seen[messageKey(c.ModelId, m)] = true
+			}
+		}
+	}
+	d.NewMessages = []types.DiffMessage{}
+	if b != nil {
+		for _, c := range b.Conversations {
+			for _, m := range c.Messages {
+				key := messageKey(c.ModelId, m)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				d.NewMessages = append(d.NewMessages, types.DiffMessage{ModelId: c.ModelId, Role: m.Role, Content: m.Content, Timestamp: m.Timestamp})
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/types"
+)
+
+const testDataPath = "../../../mcp/data"
+
+var fixtures = []string{"positions.json", "trades.json", "leaderboard.json", "analytics.json", "conversations.json"}
+
+// copyFixtures copies the compared fixture files into a fresh directory.
+func copyFixtures(t *testing.T) string {
+	dir := t.TempDir()
+	for _, f := range fixtures {
+		b, err := os.ReadFile(filepath.Join(testDataPath, f))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, f), b, 0o644))
+	}
+	return dir
+}
+
+// edit rewrites a JSON file through fn.
+func edit(t *testing.T, dir, file string, fn func(doc map[string]any)) {
+	path := filepath.Join(dir, file)
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(b, &doc))
+	fn(doc)
+	b, err = json.Marshal(doc)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0o644))
+}
+
+func find(list []any, key, value string) map[string]any {
+	for _, v := range list {
+		if m := v.(map[string]any); m[key] == value {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestCompareIdentical(t *testing.T) {
+	dl := data.NewDataLoader(testDataPath)
+	d, err := Compare(dl, data.NewDataLoader(copyFixtures(t)), 0.01)
+	require.NoError(t, err)
+	assert.Equal(t, types.DiffSummary{}, d.Summary)
+	assert.NotNil(t, d.NewTrades)
+	assert.NotNil(t, d.AnalyticsDeltas)
+}
+
+func TestCompare(t *testing.T) {
+	oldDir, newDir := copyFixtures(t), copyFixtures(t)
+
+	var tradeId string
+	edit(t, oldDir, "trades.json", func(doc map[string]any) {
+		trades := doc["trades"].([]any)
+		tradeId = trades[0].(map[string]any)["id"].(string)
+		doc["trades"] = trades[1:]
+	})
+	edit(t, newDir, "positions.json", func(doc map[string]any) {
+		totals := doc["accountTotals"].([]any)
+		gpt := find(totals, "model_id", "gpt-5")["positions"].(map[string]any)
+		delete(gpt, "ETH")
+		gpt["BTC"].(map[string]any)["quantity"] = 0.5
+		find(totals, "model_id", "deepseek-chat-v3.1")["positions"].(map[string]any)["ETH"] = map[string]any{
+			"symbol": "ETH", "quantity": -2.0, "entry_price": 4000.0, "leverage": 10.0,
+		}
+	})
+	edit(t, newDir, "leaderboard.json", func(doc map[string]any) {
+		find(doc["leaderboard"].([]any), "id", "gpt-5")["return_pct"] = 50.0
+	})
+	edit(t, newDir, "analytics.json", func(doc map[string]any) {
+		a := find(doc["analytics"].([]any), "model_id", "claude-sonnet-4-5")
+		a["updated_at"] = 1.0
+		wl := a["winners_losers_breakdown_table"].(map[string]any)
+		wl["win_rate"] = 40.0
+		wl["avg_winners_net_pnl"] = wl["avg_winners_net_pnl"].(float64) * 1.001
+	})
+	edit(t, newDir, "conversations.json", func(doc map[string]any) {
+		c := find(doc["conversations"].([]any), "model_id", "gpt-5")
+		c["messages"] = append(c["messages"].([]any), map[string]any{
+			"role": "assistant", "content": "Closing ETH.", "timestamp": 1760800000,
+		})
+	})
+
+	d, err := Compare(data.NewDataLoader(oldDir), data.NewDataLoader(newDir), 0.01)
+	require.NoError(t, err)
+
+	require.Len(t, d.NewPositions, 1)
+	assert.Equal(t, types.DiffPosition{ModelId: "deepseek-chat-v3.1", Symbol: "ETH", Side: "short", Quantity: -2, EntryPrice: 4000, Leverage: 10}, d.NewPositions[0])
+	require.Len(t, d.ClosedPositions, 1)
+	assert.Equal(t, "gpt-5", d.ClosedPositions[0].ModelId)
+	assert.Equal(t, "ETH", d.ClosedPositions[0].Symbol)
+	require.Len(t, d.ModifiedPositions, 1)
+	assert.Equal(t, "BTC", d.ModifiedPositions[0].Symbol)
+	require.Len(t, d.ModifiedPositions[0].Changes, 1)
+	assert.Equal(t, "quantity", d.ModifiedPositions[0].Changes[0].Field)
+	assert.Equal(t, 0.5, d.ModifiedPositions[0].Changes[0].New)
+
+	require.Len(t, d.NewTrades, 1)
+	assert.Equal(t, tradeId, d.NewTrades[0].Id)
+
+	// gpt-5 jumps from last to first; everyone above it moves down one.
+	require.Len(t, d.RankChanges, 6)
+	assert.Equal(t, types.RankChange{ModelId: "gpt-5", OldRank: 6, NewRank: 1, OldReturnPct: -69.36, NewReturnPct: 50}, d.RankChanges[0])
+
+	// The 0.1% move is under the threshold and updated_at is ignored.
+	require.Len(t, d.AnalyticsDeltas, 1)
+	assert.Equal(t, "claude-sonnet-4-5", d.AnalyticsDeltas[0].ModelId)
+	assert.Equal(t, "winners_losers_breakdown_table.win_rate", d.AnalyticsDeltas[0].Metric)
+	assert.Equal(t, 40.0, d.AnalyticsDeltas[0].New)
+
+	require.Len(t, d.NewMessages, 1)
+	assert.Equal(t, "Closing ETH.", d.NewMessages[0].Content)
+
+	assert.Equal(t, types.DiffSummary{NewPositions: 1, ClosedPositions: 1, ModifiedPositions: 1, NewTrades: 1,
+		RankChanges: 6, AnalyticsDeltas: 1, NewMessages: 1}, d.Summary)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteText(&buf, d))
+	assert.Contains(t, buf.String(), "1 new, 1 closed, 1 modified positions; 1 new trades")
+	assert.Contains(t, buf.String(), "#6 -> #1")
+	assert.Contains(t, buf.String(), "winners_losers_breakdown_table.win_rate")
+}
+
+func TestCompareMissingFiles(t *testing.T) {
+	d, err := Compare(data.NewDataLoader(t.TempDir()), data.NewDataLoader(testDataPath), 0.01)
+	require.NoError(t, err)
+	assert.Equal(t, 6, d.Summary.RankChanges)
+	assert.NotZero(t, d.Summary.NewTrades)
+	for _, r := range d.RankChanges {
+		assert.Zero(t, r.OldRank)
+	}
+}
@@ -0,0 +1,75 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"nof0-api/internal/types"
+)
+
+// maxContent is how much of a new message's content the text report shows.
+const maxContent = 120
+
+// WriteText renders a diff for people: a summary line followed by one
+// section per non-empty category.
+func WriteText(w io.Writer, d *types.DiffResponse) error {
+	bw := bufio.NewWriter(w)
+	s := d.Summary
+	if d.From != "" || d.To != "" {
+		fmt.Fprintf(bw, "diff %s -> %s\n", d.From, d.To)
+	}
+	fmt.Fprintf(bw, "%d new, %d closed, %d modified positions; %d new trades; %d rank changes; %d analytics deltas (threshold %g%%); %d new messages\n",
+		s.NewPositions, s.ClosedPositions, s.ModifiedPositions, s.NewTrades, s.RankChanges, s.AnalyticsDeltas, d.Threshold*100, s.NewMessages)
+
+	section := func(title string, n int) {
+		if n > 0 {
+			fmt.Fprintf(bw, "\n%s (%d)\n", title, n)
+		}
+	}
+	section("New positions", len(d.NewPositions))
+	for _, p := range d.NewPositions {
+		fmt.Fprintf(bw, "  + %-24s %-6s %-5s qty %g @ %g x%g\n", p.ModelId, p.Symbol, p.Side, p.Quantity, p.EntryPrice, p.Leverage)
+	}
+	section("Closed positions", len(d.ClosedPositions))
+	for _, p := range d.ClosedPositions {
+		fmt.Fprintf(bw, "  - %-24s %-6s %-5s qty %g @ %g x%g\n", p.ModelId, p.Symbol, p.Side, p.Quantity, p.EntryPrice, p.Leverage)
+	}
+	section("Modified positions", len(d.ModifiedPositions))
+	for _, p := range d.ModifiedPositions {
+		changes := make([]string, 0, len(p.Changes))
+		for _, c := range p.Changes {
+			changes = append(changes, fmt.Sprintf("%s %g -> %g", c.Field, c.Old, c.New))
+		}
+		fmt.Fprintf(bw, "  ~ %-24s %-6s %s\n", p.ModelId, p.Symbol, strings.Join(changes, ", "))
+	}
+	section("New trades", len(d.NewTrades))
+	for _, t := range d.NewTrades {
+		fmt.Fprintf(bw, "  + %-24s %-6s %-5s qty %g @ %g, net pnl %g [%s]\n", t.ModelId, t.Symbol, t.Side, t.Quantity, t.EntryPrice, t.RealizedNetPnl, t.Id)
+	}
+	section("Leaderboard", len(d.RankChanges))
+	for _, r := range d.RankChanges {
+		fmt.Fprintf(bw, "  %-24s %s -> %s (return %g%% -> %g%%)\n", r.ModelId, rankLabel(r.OldRank), rankLabel(r.NewRank), r.OldReturnPct, r.NewReturnPct)
+	}
+	section("Analytics", len(d.AnalyticsDeltas))
+	for _, a := range d.AnalyticsDeltas {
+		fmt.Fprintf(bw, "  %-24s %s: %g -> %g (%+g)\n", a.ModelId, a.Metric, a.Old, a.New, a.Delta)
+	}
+	section("New messages", len(d.NewMessages))
+	for _, m := range d.NewMessages {
+		content := strings.Join(strings.Fields(m.Content), " ")
+		if r := []rune(content); len(r) > maxContent {
+			content = string(r[:maxContent]) + "…"
+		}
+		fmt.Fprintf(bw, "  %-24s %-9s %s\n", m.ModelId, m.Role, content)
+	}
+	return bw.Flush()
+}
+
+func rankLabel(rank int) string {
+	if rank == 0 {
+		return "unranked"
+	}
+	return fmt.Sprintf("#%d", rank)
+}
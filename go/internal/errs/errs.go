@@ -0,0 +1,140 @@
+// Package errs holds the typed errors returned to API clients. Handler, set
+// with httpx.SetErrorHandlerCtx, maps them to HTTP status codes and writes
+// them as a JSON types.ErrorResponse; any other error is a 500 whose details
+// are logged, not returned.
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/internal/types"
+)
+
+// Kind classifies an error for the client.
+type Kind int
+
+const (
+	KindInternal          Kind = iota
+	KindNotFound               // 404: the requested model, snapshot or data does not exist
+	KindInvalidArgument        // 400: the request is malformed
+	KindUnavailable            // 503: a backing service or feature is not available
+	KindUpstream               // 502: the upstream API failed
+	KindUnauthenticated        // 401: the API key is missing or invalid
+	KindPermissionDenied       // 403: the API key lacks the required role
+	KindResourceExhausted      // 429: the client is over its rate limit
+)
+
+var kinds = map[Kind]struct {
+	code   string
+	status int
+}{
+	KindInternal:          {"internal", http.StatusInternalServerError},
+	KindNotFound:          {"not_found", http.StatusNotFound},
+	KindInvalidArgument:   {"invalid_argument", http.StatusBadRequest},
+	KindUnavailable:       {"unavailable", http.StatusServiceUnavailable},
+	KindUpstream:          {"upstream", http.StatusBadGateway},
+	KindUnauthenticated:   {"unauthenticated", http.StatusUnauthorized},
+	KindPermissionDenied:  {"permission_denied", http.StatusForbidden},
+	KindResourceExhausted: {"resource_exhausted", http.StatusTooManyRequests},
+}
+
+// Code is the envelope code of k, such as "not_found".
+func (k Kind) Code() string { return kinds[k].code }
+
+// Status is the HTTP status code of k.
+func (k Kind) Status() int { return kinds[k].status }
+
+// Error is an error with a kind and a message safe to show to clients. The
+// wrapped cause, if any, is only logged.
+type Error struct {
+	Kind    Kind
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// New returns an error of kind with a formatted message.
+func New(kind Kind, format string, args ...any) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap returns an error of kind with a formatted message and cause err.
+func Wrap(kind Kind, err error, format string, args ...any) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+func NotFound(format string, args ...any) error { return New(KindNotFound, format, args...) }
+
+func InvalidArgument(format string, args ...any) error {
+	return New(KindInvalidArgument, format, args...)
+}
+
+func Unavailable(format string, args ...any) error { return New(KindUnavailable, format, args...) }
+
+func Upstream(format string, args ...any) error { return New(KindUpstream, format, args...) }
+
+func Unauthenticated(format string, args ...any) error {
+	return New(KindUnauthenticated, format, args...)
+}
+
+func PermissionDenied(format string, args ...any) error {
+	return New(KindPermissionDenied, format, args...)
+}
+
+func ResourceExhausted(format string, args ...any) error {
+	return New(KindResourceExhausted, format, args...)
+}
+
+// Is reports whether err is, or wraps, an *Error of kind.
+func Is(err error, kind Kind) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Kind == kind
+}
+
+// classify returns the kind of err and the message shown for it. Missing
+// files are reported by name only, never by path.
+func classify(err error) (Kind, string) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind, e.Message
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		var pe *fs.PathError
+		if errors.As(err, &pe) {
+			return KindNotFound, filepath.Base(pe.Path) + " not found"
+		}
+		return KindNotFound, "not found"
+	}
+	return KindInternal, "internal error"
+}
+
+// Message is the client-safe message of err.
+func Message(err error) string {
+	_, msg := classify(err)
+	return msg
+}
+
+// Handler is the httpx error handler: it writes err as a types.ErrorResponse
+// with the status code of its kind and logs server-side failures.
+func Handler(ctx context.Context, err error) (int, any) {
+	kind, msg := classify(err)
+	if kind.Status() >= http.StatusInternalServerError {
+		logx.WithContext(ctx).Errorf("%s: %v", kind.Code(), err)
+	}
+	return kind.Status(), &types.ErrorResponse{Error: types.ErrorBody{Code: kind.Code(), Message: msg}}
+}
@@ -0,0 +1,69 @@
+package errs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/rest/httpx"
+
+	"nof0-api/internal/types"
+)
+
+func TestHandler(t *testing.T) {
+	_, missing := os.ReadFile(filepath.Join(t.TempDir(), "trades.json"))
+	sentinel := NotFound("snapshot not found")
+
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{"not found", NotFound("model %s not found", "x"), http.StatusNotFound, "not_found", "model x not found"},
+		{"wrapped sentinel", fmt.Errorf("arena a: %w", sentinel), http.StatusNotFound, "not_found", "snapshot not found"},
+		{"invalid", InvalidArgument("limit must be positive"), http.StatusBadRequest, "invalid_argument", "limit must be positive"},
+		{"unavailable", Unavailable("ingest requires Postgres"), http.StatusServiceUnavailable, "unavailable", "ingest requires Postgres"},
+		{"upstream", Wrap(KindUpstream, errors.New("dial tcp: refused"), "upstream unavailable"), http.StatusBadGateway, "upstream", "upstream unavailable"},
+		{"missing file", missing, http.StatusNotFound, "not_found", "trades.json not found"},
+		{"unauthenticated", Unauthenticated("missing or invalid API key"), http.StatusUnauthorized, "unauthenticated", "missing or invalid API key"},
+		{"permission denied", PermissionDenied("API key lacks the required role"), http.StatusForbidden, "permission_denied", "API key lacks the required role"},
+		{"resource exhausted", ResourceExhausted("rate limit exceeded"), http.StatusTooManyRequests, "resource_exhausted", "rate limit exceeded"},
+		{"internal", errors.New("pq: password authentication failed"), http.StatusInternalServerError, "internal", "internal error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := Handler(context.Background(), tt.err)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, &types.ErrorResponse{Error: types.ErrorBody{Code: tt.code, Message: tt.message}}, body)
+		})
+	}
+	assert.True(t, errors.Is(fmt.Errorf("x: %w", sentinel), sentinel))
+	assert.True(t, Is(fmt.Errorf("x: %w", sentinel), KindNotFound))
+	assert.False(t, Is(missing, KindNotFound), "Is only matches typed errors")
+}
+
+func TestHandlerResponse(t *testing.T) {
+	httpx.SetErrorHandlerCtx(Handler)
+	_, missing := os.ReadFile(filepath.Join(t.TempDir(), "positions.json"))
+
+	w := httptest.NewRecorder()
+	httpx.ErrorCtx(context.Background(), w, missing)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.NotContains(t, w.Body.String(), os.TempDir(), "Paths must not leak")
+
+	var resp types.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "not_found", resp.Error.Code)
+	assert.Equal(t, "positions.json not found", resp.Error.Message)
+}
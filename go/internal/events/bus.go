@@ -0,0 +1,81 @@
+// Package events fans out change events written by ingest: to subscribers in
+// this process (the /api/events stream) and, when Redis is configured, to the
+// nof0:events pub/sub channel for other instances and external consumers.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+
+	"nof0-api/internal/types"
+)
+
+// Channel is the Redis pub/sub channel events are published on as JSON.
+const Channel = "nof0:events"
+
+// Event types, one per ingest endpoint.
+const (
+	TypePrices           = "prices"
+	TypeTrades           = "trades"
+	TypePositions        = "positions"
+	TypeAccountSnapshots = "account-snapshots"
+	TypeConversations    = "conversations"
+)
+
+// Bus delivers events to local subscribers without blocking the publisher:
+// a subscriber that falls behind by more than its buffer misses events.
+type Bus struct {
+	rds *redis.Redis
+
+	mu   sync.Mutex
+	subs map[chan types.ChangeEvent]struct{}
+}
+
+// NewBus returns a bus; rds may be nil to publish in-process only.
+func NewBus(rds *redis.Redis) *Bus {
+	return &Bus{rds: rds, subs: map[chan types.ChangeEvent]struct{}{}}
+}
+
+// Publish sends e to every subscriber and to Channel. A Redis failure is
+// logged; the data is already written, so it does not fail the caller.
+func (b *Bus) Publish(ctx context.Context, e types.ChangeEvent) {
+	b.mu.Lock()
+	for ch := range b.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+	b.mu.Unlock()
+
+	if b.rds == nil {
+		return
+	}
+	msg, _ := json.Marshal(e)
+	if _, err := b.rds.PublishCtx(ctx, Channel, string(msg)); err != nil {
+		logx.WithContext(ctx).Errorf("publish %s event: %v", e.Type, err)
+	}
+}
+
+// Subscribe returns a channel receiving every event published from now on
+// and a function that unsubscribes and closes it.
+func (b *Bus) Subscribe(buffer int) (<-chan types.ChangeEvent, func()) {
+	ch := make(chan types.ChangeEvent, buffer)
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
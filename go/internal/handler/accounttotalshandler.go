@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/zeromicro/go-zero/rest/httpx"
+	"nof0-api/internal/errs"
 	"nof0-api/internal/logic"
 	"nof0-api/internal/svc"
 	"nof0-api/internal/types"
@@ -16,7 +17,7 @@ func AccountTotalsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req types.AccountTotalsRequest
 		if err := httpx.Parse(r, &req); err != nil {
-			httpx.ErrorCtx(r.Context(), w, err)
+			httpx.ErrorCtx(r.Context(), w, errs.InvalidArgument("%v", err))
 			return
 		}
 
@@ -0,0 +1,32 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/logic"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+)
+
+func ConversationSearchHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ConversationSearchRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, errs.InvalidArgument("%v", err))
+			return
+		}
+
+		l := logic.NewConversationSearchLogic(r.Context(), svcCtx)
+		resp, err := l.ConversationSearch(&req)
+		if err != nil {
+			httpx.ErrorCtx(r.Context(), w, err)
+		} else {
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"github.com/zeromicro/go-zero/rest/router"
+
+	"nof0-api/internal/errs"
+)
+
+func TestCreateApiKeyRejectsUnknownRole(t *testing.T) {
+	httpx.SetErrorHandlerCtx(errs.Handler)
+	rt := router.NewRouter()
+	for _, r := range testRoutes(t) {
+		require.NoError(t, rt.Handle(r.Method, r.Path, r.Handler))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/keys", strings.NewReader(`{"name":"ops","role":"root"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", testKey)
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "root")
+}
@@ -7,14 +7,22 @@ import (
 	"net/http"
 
 	"github.com/zeromicro/go-zero/rest/httpx"
+	"nof0-api/internal/errs"
 	"nof0-api/internal/logic"
 	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
 )
 
 func CryptoPricesHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.CryptoPricesRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, errs.InvalidArgument("%v", err))
+			return
+		}
+
 		l := logic.NewCryptoPricesLogic(r.Context(), svcCtx)
-		resp, err := l.CryptoPrices()
+		resp, err := l.CryptoPrices(&req)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 		} else {
@@ -0,0 +1,39 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"nof0-api/internal/diff"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/logic"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+)
+
+func DiffHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.DiffRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, errs.InvalidArgument("%v", err))
+			return
+		}
+
+		l := logic.NewDiffLogic(r.Context(), svcCtx)
+		resp, err := l.Diff(&req)
+		switch {
+		case err != nil:
+			httpx.ErrorCtx(r.Context(), w, err)
+		case req.Format == "text":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			if err := diff.WriteText(w, resp); err != nil {
+				l.Errorf("write diff: %v", err)
+			}
+		default:
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+	"nof0-api/internal/logic"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+)
+
+func EventsHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client := make(chan *types.ChangeEvent, 16)
+
+		l := logic.NewEventsLogic(r.Context(), svcCtx)
+		threading.GoSafeCtx(r.Context(), func() {
+			defer close(client)
+			if err := l.Events(client); err != nil {
+				logx.WithContext(r.Context()).Errorf("events: %v", err)
+			}
+		})
+
+		// Open the stream now rather than at the first event.
+		fmt.Fprint(w, ": connected\n\n")
+		flush(w)
+		for e := range client {
+			b, err := json.Marshal(e)
+			if err != nil {
+				logx.WithContext(r.Context()).Errorf("events: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, b); err != nil {
+				return
+			}
+			flush(w)
+		}
+	}
+}
+
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
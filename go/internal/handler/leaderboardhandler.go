@@ -7,14 +7,22 @@ import (
 	"net/http"
 
 	"github.com/zeromicro/go-zero/rest/httpx"
+	"nof0-api/internal/errs"
 	"nof0-api/internal/logic"
 	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
 )
 
 func LeaderboardHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.LeaderboardRequest
+		if err := httpx.Parse(r, &req); err != nil {
+			httpx.ErrorCtx(r.Context(), w, errs.InvalidArgument("%v", err))
+			return
+		}
+
 		l := logic.NewLeaderboardLogic(r.Context(), svcCtx)
-		resp, err := l.Leaderboard()
+		resp, err := l.Leaderboard(&req)
 		if err != nil {
 			httpx.ErrorCtx(r.Context(), w, err)
 		} else {
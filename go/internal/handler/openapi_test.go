@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"github.com/zeromicro/go-zero/rest/router"
+
+	"nof0-api/internal/auth"
+	"nof0-api/internal/config"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/middleware"
+	"nof0-api/internal/openapi"
+	"nof0-api/internal/svc"
+)
+
+const (
+	testKey       = "nof0_test_admin"
+	testReaderKey = "nof0_test_reader"
+)
+
+// testRoutes registers every route against the fixture data.
+func testRoutes(t *testing.T) []rest.Route {
+	cfg := config.Config{}
+	cfg.DataPath = "../../../mcp/data"
+	cfg.Auth.Keys = []config.ApiKeyConf{
+		{Name: "test", Role: "admin", Key: testKey},
+		{Name: "reader", Role: "reader", Key: testReaderKey},
+	}
+	server := rest.MustNewServer(rest.RestConf{Host: "localhost", Port: 0})
+	RegisterHandlers(server, svc.NewServiceContext(cfg))
+	return server.Routes()
+}
+
+func TestRoutesDocumented(t *testing.T) {
+	spec := openapi.Generate()
+	registered := []string{}
+	for _, r := range testRoutes(t) {
+		registered = append(registered, r.Method+" "+openapi.Path(r.Path))
+	}
+	documented := []string{}
+	for path, item := range spec.Paths {
+		for method := range item {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(registered)
+	sort.Strings(documented)
+	assert.Equal(t, registered, documented)
+}
+
+// TestResponsesMatchSpec calls every GET route, and a few failing ones
+// including rejected keys and rate limiting, and validates the JSON bodies
+// against the generated document.
+func TestResponsesMatchSpec(t *testing.T) {
+	httpx.SetErrorHandlerCtx(errs.Handler)
+	spec := openapi.Generate()
+	rt := router.NewRouter()
+	var gets []string
+	for _, r := range testRoutes(t) {
+		require.NoError(t, rt.Handle(r.Method, r.Path, r.Handler))
+		if r.Method == http.MethodGet && r.Path != "/api/events" {
+			gets = append(gets, r.Path)
+		}
+	}
+
+	// The server installs the rate limiter around every route; one request
+	// per second per IP for /api/leaderboard is enough to be refused.
+	a, err := auth.NewAuthenticator(nil, nil)
+	require.NoError(t, err)
+	limiter, err := middleware.NewRateLimitMiddleware(config.RateLimitConf{
+		Routes: []config.RateLimitRule{{Path: "/api/leaderboard", Rate: 1}},
+	}, a)
+	require.NoError(t, err)
+	handler := limiter.Handle(rt.ServeHTTP)
+
+	type call struct {
+		route, url, key string
+		status          int // 0 for any
+	}
+	calls := []call{
+		{"/api/analytics/:modelId", "/api/analytics/no-such-model", testKey, http.StatusNotFound},
+		{"/api/models/:modelId", "/api/models/no-such-model", testKey, http.StatusNotFound},
+		{"/api/trades", "/api/trades?arena=no-such-arena", testKey, http.StatusNotFound},
+		{"/api/admin/diff", "/api/admin/diff?from=live&threshold=-1", testKey, http.StatusBadRequest},
+		{"/api/admin/diff", "/api/admin/diff?from=live&to=live", testKey, http.StatusOK},
+		{"/api/conversations/search", "/api/conversations/search?q=BTC&limit=5", testKey, http.StatusOK},
+		{"/api/admin/jobs", "/api/admin/jobs", "", http.StatusUnauthorized},
+		{"/api/admin/keys", "/api/admin/keys", "nof0_made_up", http.StatusUnauthorized},
+		{"/api/admin/diff", "/api/admin/diff?from=live&to=live", testReaderKey, http.StatusForbidden},
+		{"/api/leaderboard", "/api/leaderboard", "", http.StatusOK},
+		{"/api/leaderboard", "/api/leaderboard", "", http.StatusTooManyRequests},
+	}
+	for _, route := range gets {
+		url := strings.NewReplacer(":modelId", "gpt-5", ":id", "none").Replace(route)
+		if route != "/api/leaderboard" {
+			calls = append(calls, call{route, url, testKey, 0})
+		}
+	}
+
+	for _, c := range calls {
+		t.Run(c.url, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, c.url, nil)
+			if c.key != "" {
+				req.Header.Set("X-Api-Key", c.key)
+			}
+			w := httptest.NewRecorder()
+			handler(w, req)
+			if c.status != 0 {
+				assert.Equal(t, c.status, w.Code)
+			}
+			require.Contains(t, w.Header().Get("Content-Type"), "application/json", w.Body.String())
+			assert.NoError(t, spec.ValidateResponse(http.MethodGet, c.route, w.Code, w.Body.Bytes()),
+				"status %d: %.300s", w.Code, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+	"nof0-api/internal/health"
+	"nof0-api/internal/logic"
+	"nof0-api/internal/svc"
+)
+
+func ReadyzHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logic.NewReadyzLogic(r.Context(), svcCtx)
+		resp, err := l.Readyz()
+		switch {
+		case err != nil:
+			httpx.ErrorCtx(r.Context(), w, err)
+		case resp.Status != health.StatusOK:
+			httpx.WriteJsonCtx(r.Context(), w, http.StatusServiceUnavailable, resp)
+		default:
+			httpx.OkJsonCtx(r.Context(), w, resp)
+		}
+	}
+}
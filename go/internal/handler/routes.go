@@ -16,50 +16,238 @@ func RegisterHandlers(server *rest.Server, serverCtx *svc.ServiceContext) {
 		[]rest.Route{
 			{
 				Method:  http.MethodGet,
-				Path:    "/account-totals",
-				Handler: AccountTotalsHandler(serverCtx),
+				Path:    "/healthz",
+				Handler: HealthzHandler(serverCtx),
 			},
 			{
 				Method:  http.MethodGet,
-				Path:    "/analytics",
-				Handler: AnalyticsHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/analytics/:modelId",
-				Handler: ModelAnalyticsHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/crypto-prices",
-				Handler: CryptoPricesHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/leaderboard",
-				Handler: LeaderboardHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/since-inception-values",
-				Handler: SinceInceptionHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/trades",
-				Handler: TradesHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/positions",
-				Handler: PositionsHandler(serverCtx),
-			},
-			{
-				Method:  http.MethodGet,
-				Path:    "/conversations",
-				Handler: ConversationsHandler(serverCtx),
+				Path:    "/readyz",
+				Handler: ReadyzHandler(serverCtx),
 			},
 		},
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Arena, serverCtx.ResponseCache},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/arenas",
+					Handler: ArenasHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/snapshots",
+					Handler: SnapshotsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/status",
+					Handler: StatusHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/crypto-prices",
+					Handler: CryptoPricesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/account-totals",
+					Handler: AccountTotalsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/trades",
+					Handler: TradesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/positions",
+					Handler: PositionsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/conversations",
+					Handler: ConversationsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/since-inception-values",
+					Handler: SinceInceptionHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/leaderboard",
+					Handler: LeaderboardHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/analytics",
+					Handler: AnalyticsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/conversations/search",
+					Handler: ConversationSearchHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/conversations/links",
+					Handler: ConversationLinksHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/invocations",
+					Handler: InvocationsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/analytics/correlation",
+					Handler: CorrelationHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/analytics/:modelId",
+					Handler: ModelAnalyticsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/models",
+					Handler: ListModelsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/models/:modelId",
+					Handler: ModelDetailHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/openapi.json",
+					Handler: OpenApiHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.AdminAuth, serverCtx.Arena},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/models",
+					Handler: CreateModelHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPatch,
+					Path:    "/models/:modelId",
+					Handler: UpdateModelHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.AdminAuth},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/jobs",
+					Handler: JobsHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/admin"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.AdminAuth, serverCtx.Arena},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/diff",
+					Handler: DiffHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/admin"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.AdminAuth},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/keys",
+					Handler: ListApiKeysHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/keys",
+					Handler: CreateApiKeyHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/keys/:id",
+					Handler: RevokeApiKeyHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/admin"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.IngesterAuth, serverCtx.Arena},
+			[]rest.Route{
+				{
+					Method:  http.MethodPost,
+					Path:    "/prices",
+					Handler: IngestPricesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/trades",
+					Handler: IngestTradesHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/positions",
+					Handler: IngestPositionsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/account-snapshots",
+					Handler: IngestAccountSnapshotsHandler(serverCtx),
+				},
+				{
+					Method:  http.MethodPost,
+					Path:    "/conversations",
+					Handler: IngestConversationsHandler(serverCtx),
+				},
+			}...,
+		),
+		rest.WithPrefix("/api/ingest"),
+	)
+
+	server.AddRoutes(
+		rest.WithMiddlewares(
+			[]rest.Middleware{serverCtx.Arena},
+			[]rest.Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/events",
+					Handler: EventsHandler(serverCtx),
+				},
+			}...,
+		),
 		rest.WithPrefix("/api"),
+		rest.WithSSE(),
 	)
 }
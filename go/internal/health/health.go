@@ -0,0 +1,132 @@
+// Package health checks the service's data dependencies for /healthz and
+// /readyz: the files under DataPath, and Postgres and Redis when configured.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/types"
+)
+
+// Check and overall statuses.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// checkTimeout bounds each dependency check.
+const checkTimeout = 2 * time.Second
+
+var errNoData = errors.New("no data files")
+
+// Checker runs the dependency checks.
+type Checker struct {
+	dataPath string
+	conn     sqlx.SqlConn // nil without Postgres
+	rds      *redis.Redis // nil without Redis
+}
+
+func NewChecker(dataPath string, conn sqlx.SqlConn, rds *redis.Redis) *Checker {
+	return &Checker{dataPath: dataPath, conn: conn, rds: rds}
+}
+
+// Check runs every configured check; the status is StatusFail when any of
+// them failed. The probes are public, so a failed check reports a short
+// message and the error itself is only logged.
+func (c *Checker) Check(ctx context.Context) *types.HealthResponse {
+	resp := &types.HealthResponse{Status: StatusOK, Checks: []types.HealthCheck{}}
+	add := func(name string, check func(context.Context) error) {
+		ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+		defer cancel()
+		start := time.Now()
+		err := check(ctx)
+		hc := types.HealthCheck{Name: name, Status: StatusOK, DurationMs: time.Since(start).Milliseconds()}
+		if err != nil {
+			logx.WithContext(ctx).Errorf("health check %s: %v", name, err)
+			hc.Status, hc.Error = StatusFail, message(err)
+			resp.Status = StatusFail
+		}
+		resp.Checks = append(resp.Checks, hc)
+	}
+
+	add("data", c.checkData)
+	if c.conn != nil {
+		add("postgres", c.checkPostgres)
+	}
+	if c.rds != nil {
+		add("redis", c.checkRedis)
+	}
+	resp.ServerTime = time.Now().UnixMilli()
+	return resp
+}
+
+func message(err error) string {
+	switch {
+	case errors.Is(err, errNoData):
+		return errNoData.Error()
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timed out"
+	}
+	return "unavailable"
+}
+
+// checkData opens every data file of every arena (the DataPath root and its
+// subdirectories); at least one must exist.
+func (c *Checker) checkData(ctx context.Context) error {
+	dirs := []string{c.dataPath}
+	entries, err := os.ReadDir(c.dataPath)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			dirs = append(dirs, filepath.Join(c.dataPath, e.Name()))
+		}
+	}
+	files := 0
+	for _, dir := range dirs {
+		for _, pattern := range []string{"*.json", "*.json.gz"} {
+			matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+			for _, path := range matches {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				f, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				f.Close()
+				files++
+			}
+		}
+	}
+	if files == 0 {
+		return fmt.Errorf("%w under %s", errNoData, c.dataPath)
+	}
+	return nil
+}
+
+func (c *Checker) checkPostgres(ctx context.Context) error {
+	db, err := c.conn.RawDB()
+	if err != nil {
+		return err
+	}
+	return db.PingContext(ctx)
+}
+
+func (c *Checker) checkRedis(ctx context.Context) error {
+	if !c.rds.PingCtx(ctx) {
+		return errors.New("redis ping failed")
+	}
+	return nil
+}
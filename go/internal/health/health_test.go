@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+func TestCheckData(t *testing.T) {
+	root := t.TempDir()
+	resp := NewChecker(root, nil, nil).Check(context.Background())
+	assert.Equal(t, StatusFail, resp.Status)
+	require.Len(t, resp.Checks, 1)
+	assert.Equal(t, "no data files", resp.Checks[0].Error, "Paths must not leak")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "season-1"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "season-1", "trades.json"), []byte(`{"trades":[]}`), 0o644))
+	resp = NewChecker(root, nil, nil).Check(context.Background())
+	assert.Equal(t, StatusOK, resp.Status)
+
+	resp = NewChecker(filepath.Join(root, "missing"), nil, nil).Check(context.Background())
+	assert.Equal(t, StatusFail, resp.Status)
+	assert.Equal(t, "unavailable", resp.Checks[0].Error)
+}
+
+func TestCheckDependencies(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+	mr := miniredis.RunT(t)
+	c := NewChecker("../../../mcp/data", sqlx.NewSqlConnFromDB(db), redis.New(mr.Addr()))
+
+	mock.ExpectPing()
+	resp := c.Check(context.Background())
+	assert.Equal(t, StatusOK, resp.Status)
+	names := []string{}
+	for _, check := range resp.Checks {
+		names = append(names, check.Name)
+	}
+	assert.Equal(t, []string{"data", "postgres", "redis"}, names)
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	mr.Close()
+	resp = c.Check(context.Background())
+	assert.Equal(t, StatusFail, resp.Status)
+	assert.Equal(t, StatusOK, resp.Checks[0].Status)
+	assert.Equal(t, "unavailable", resp.Checks[1].Error, "Driver errors are only logged")
+	assert.Equal(t, StatusFail, resp.Checks[2].Status)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
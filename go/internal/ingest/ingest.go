@@ -0,0 +1,375 @@
+// Package ingest writes pushed arena data (POST /api/ingest/*) to Postgres,
+// drops the cached payloads it changes and publishes a change event per
+// batch. It also holds the SQL writers cmd/importer uses.
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/cache"
+	"nof0-api/internal/config"
+	"nof0-api/internal/data"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/events"
+	"nof0-api/internal/types"
+)
+
+var ErrEmpty = errs.InvalidArgument("no items in request body")
+
+// Decode reads a JSON body holding either one T or an array of them.
+func Decode[T any](r io.Reader) ([]T, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, errs.InvalidArgument("invalid request body: %v", err)
+	}
+	var items []T
+	if raw[0] == '[' {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, errs.InvalidArgument("invalid request body: %v", err)
+		}
+	} else {
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, errs.InvalidArgument("invalid request body: %v", err)
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return nil, ErrEmpty
+	}
+	return items, nil
+}
+
+// Result reports what one batch did. Ids are the keys written, in order.
+type Result struct {
+	Written    int
+	Duplicates int
+	Ids        []string
+}
+
+// Ingester writes each batch in one transaction: a batch with an invalid item
+// or a failing write changes nothing.
+type Ingester struct {
+	conn  sqlx.SqlConn
+	cache *cache.Cache
+	bus   *events.Bus
+	conf  config.IngestConf
+	now   func() time.Time
+}
+
+func New(conn sqlx.SqlConn, c *cache.Cache, bus *events.Bus, conf config.IngestConf) *Ingester {
+	return &Ingester{conn: conn, cache: c, bus: bus, conf: conf, now: time.Now}
+}
+
+// Prices appends each price to price_ticks and advances price_latest.
+// Prices are shared by all arenas; a missing timestamp means now.
+func (in *Ingester) Prices(ctx context.Context, prices []types.CryptoPrice) (Result, error) {
+	var res Result
+	for i := range prices {
+		p := &prices[i]
+		p.Symbol = strings.TrimSpace(p.Symbol)
+		if p.Symbol == "" || p.Price <= 0 {
+			return res, errs.InvalidArgument("prices[%d]: symbol and a positive price are required", i)
+		}
+		if p.Timestamp == 0 {
+			p.Timestamp = in.now().UnixMilli()
+		}
+		p.Timestamp = data.ToMillis(float64(p.Timestamp))
+	}
+
+	err := in.conn.TransactCtx(ctx, func(ctx context.Context, s sqlx.Session) error {
+		for _, p := range prices {
+			if err := UpsertSymbol(ctx, s, p.Symbol); err != nil {
+				return err
+			}
+			if err := InsertPriceTick(ctx, s, p.Symbol, p.Price, p.Timestamp); err != nil {
+				return err
+			}
+			if err := UpsertPriceLatest(ctx, s, p.Symbol, p.Price, p.Timestamp, ContentHash(p)); err != nil {
+				return err
+			}
+			res.Ids = append(res.Ids, p.Symbol)
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	res.Written = len(prices)
+
+	in.invalidate(ctx, "prices", cache.CryptoPricesKey)
+	in.publish(ctx, events.TypePrices, "", nil, res.Ids)
+	return res, nil
+}
+
+// Trades upserts trades by id. A trade id seen within DedupeTTL (the
+// nof0:ingest:trade:{id} mark, Redis only) is counted as a duplicate and
+// skipped; marks of a batch that fails are dropped so it can be retried.
+func (in *Ingester) Trades(ctx context.Context, arena string, trades []types.Trade) (Result, error) {
+	var res Result
+	for i, t := range trades {
+		if t.Id == "" || t.ModelId == "" || t.Symbol == "" {
+			return res, errs.InvalidArgument("trades[%d]: id, model_id and symbol are required", i)
+		}
+	}
+
+	var fresh []types.Trade
+	var marks []string
+	seen := map[string]struct{}{}
+	for _, t := range trades {
+		if _, ok := seen[t.Id]; ok {
+			res.Duplicates++
+			continue
+		}
+		seen[t.Id] = struct{}{}
+		ok, err := in.cache.MarkIngested(ctx, t.Id, seconds(in.conf.DedupeTTL))
+		if err != nil {
+			in.unmark(ctx, marks)
+			return Result{}, err
+		}
+		if !ok {
+			res.Duplicates++
+			continue
+		}
+		marks = append(marks, cache.IngestTradeKey(t.Id))
+		fresh = append(fresh, t)
+	}
+	if len(fresh) == 0 {
+		return res, nil
+	}
+
+	err := in.transact(ctx, arena, modelIds(fresh, func(t types.Trade) string { return t.ModelId }), func(ctx context.Context, s sqlx.Session) error {
+		for i := range fresh {
+			t := &fresh[i]
+			if err := UpsertSymbol(ctx, s, t.Symbol); err != nil {
+				return err
+			}
+			if err := UpsertTrade(ctx, s, arena, t, data.ToMillis(t.EntryTime), data.ToMillis(t.ExitTime), ContentHash(t)); err != nil {
+				return err
+			}
+			res.Ids = append(res.Ids, t.Id)
+		}
+		return nil
+	})
+	if err != nil {
+		in.unmark(ctx, marks)
+		return Result{}, err
+	}
+	res.Written = len(fresh)
+
+	models := modelIds(fresh, func(t types.Trade) string { return t.ModelId })
+	stale := []string{cache.TradesKey(arena), cache.LeaderboardCacheKey(arena), cache.ArenaAnalyticsKey(arena)}
+	for _, m := range models {
+		stale = append(stale, cache.AnalyticsKey(arena, m))
+	}
+	in.invalidate(ctx, "trades", stale...)
+	in.publish(ctx, events.TypeTrades, arena, models, res.Ids)
+	return res, nil
+}
+
+func (in *Ingester) unmark(ctx context.Context, keys []string) {
+	if err := in.cache.Invalidate(ctx, keys...); err != nil {
+		logx.WithContext(ctx).Errorf("ingest: drop trade marks: %v", err)
+	}
+}
+
+// Positions replaces each model's open positions: listed positions are
+// upserted (keyed by model, symbol and entry time) and the model's other open
+// positions are closed.
+func (in *Ingester) Positions(ctx context.Context, arena string, models []types.PositionsByModel) (Result, error) {
+	var res Result
+	for i, pm := range models {
+		if pm.ModelId == "" {
+			return res, errs.InvalidArgument("positions[%d]: model_id is required", i)
+		}
+		for sym := range pm.Positions {
+			if strings.TrimSpace(sym) == "" {
+				return res, errs.InvalidArgument("positions[%d]: empty symbol", i)
+			}
+		}
+	}
+
+	nowMs := in.now().UnixMilli()
+	err := in.transact(ctx, arena, modelIds(models, func(pm types.PositionsByModel) string { return pm.ModelId }), func(ctx context.Context, s sqlx.Session) error {
+		for _, pm := range models {
+			ids := []string{} // non-nil: an empty list closes every open position
+			for sym, pos := range pm.Positions {
+				if pos.Symbol == "" {
+					pos.Symbol = sym
+				}
+				if err := UpsertSymbol(ctx, s, sym); err != nil {
+					return err
+				}
+				entryMs := data.ToMillis(pos.EntryTime)
+				if err := UpsertPositionOpen(ctx, s, arena, pm.ModelId, sym, &pos, entryMs, ContentHash(pos), nowMs); err != nil {
+					return err
+				}
+				ids = append(ids, PositionID(arena, pm.ModelId, sym, entryMs))
+			}
+			q := `UPDATE positions SET status='closed', status_ts_ms=$3
+                  WHERE arena_id=$1 AND model_id=$2 AND status NOT IN ('closed','liquidated')
+                    AND id <> ALL($4::text[])`
+			if err := Exec(ctx, s, q, arena, pm.ModelId, nowMs, ids); err != nil {
+				return err
+			}
+			res.Ids = append(res.Ids, ids...)
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	res.Written = len(res.Ids)
+
+	in.invalidate(ctx, "positions", cache.ArenaPositionsKey(arena))
+	in.publish(ctx, events.TypePositions, arena, modelIds(models, func(pm types.PositionsByModel) string { return pm.ModelId }), res.Ids)
+	return res, nil
+}
+
+// AccountSnapshots upserts account totals as equity snapshots with their
+// position snapshots, keyed like cmd/importer: by id, else model and time.
+func (in *Ingester) AccountSnapshots(ctx context.Context, arena string, totals []types.AccountTotal) (Result, error) {
+	var res Result
+	for i, at := range totals {
+		if at.ModelId == "" || at.Timestamp <= 0 {
+			return res, errs.InvalidArgument("account-snapshots[%d]: model_id and timestamp are required", i)
+		}
+	}
+
+	err := in.transact(ctx, arena, modelIds(totals, func(at types.AccountTotal) string { return at.ModelId }), func(ctx context.Context, s sqlx.Session) error {
+		for i := range totals {
+			at := &totals[i]
+			for sym := range at.Positions {
+				if err := UpsertSymbol(ctx, s, sym); err != nil {
+					return err
+				}
+			}
+			key := at.Id
+			if key == "" {
+				key = fmt.Sprintf("%s:%d", at.ModelId, data.ToMillis(at.Timestamp))
+			}
+			if err := UpsertEquitySnapshot(ctx, s, arena, key, at, ContentHash(at)); err != nil {
+				return err
+			}
+			res.Ids = append(res.Ids, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	res.Written = len(totals)
+
+	in.invalidate(ctx, "account snapshots", cache.AccountTotalsKey(arena), cache.SinceInceptionKey(arena), cache.LeaderboardCacheKey(arena))
+	in.publish(ctx, events.TypeAccountSnapshots, arena, modelIds(totals, func(at types.AccountTotal) string { return at.ModelId }), res.Ids)
+	return res, nil
+}
+
+// Conversations inserts conversations with their messages. A conversation
+// already stored in the arena (same content hash) is a duplicate.
+func (in *Ingester) Conversations(ctx context.Context, arena string, convs []types.Conversation) (Result, error) {
+	var res Result
+	for i, c := range convs {
+		if c.ModelId == "" || len(c.Messages) == 0 {
+			return res, errs.InvalidArgument("conversations[%d]: model_id and messages are required", i)
+		}
+	}
+
+	var stored []types.Conversation
+	err := in.transact(ctx, arena, modelIds(convs, func(c types.Conversation) string { return c.ModelId }), func(ctx context.Context, s sqlx.Session) error {
+		for _, c := range convs {
+			hash := ContentHash(c)
+			var n int
+			if err := s.QueryRowCtx(ctx, &n, `SELECT count(*) FROM conversations WHERE arena_id=$1 AND content_hash=$2`, arena, hash); err != nil {
+				return fmt.Errorf("find conversation: %w", err)
+			}
+			if n > 0 {
+				res.Duplicates++
+				continue
+			}
+			id, err := InsertConversation(ctx, s, arena, c.ModelId, hash)
+			if err != nil {
+				return err
+			}
+			for _, m := range c.Messages {
+				if _, err := InsertConversationMessage(ctx, s, id, m.Role, m.Content, ToMs(m.Timestamp)); err != nil {
+					return err
+				}
+			}
+			stored = append(stored, c)
+			res.Ids = append(res.Ids, strconv.FormatInt(id, 10))
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	res.Written = len(res.Ids)
+
+	if res.Written > 0 {
+		in.invalidate(ctx, "conversations", cache.ConversationsKey(arena))
+		in.publish(ctx, events.TypeConversations, arena, modelIds(stored, func(c types.Conversation) string { return c.ModelId }), res.Ids)
+	}
+	return res, nil
+}
+
+// transact runs fn in one transaction after registering the arena and models.
+func (in *Ingester) transact(ctx context.Context, arena string, models []string, fn func(context.Context, sqlx.Session) error) error {
+	return in.conn.TransactCtx(ctx, func(ctx context.Context, s sqlx.Session) error {
+		if err := UpsertArena(ctx, s, arena); err != nil {
+			return err
+		}
+		for _, m := range models {
+			if err := UpsertModel(ctx, s, arena, m, m); err != nil {
+				return err
+			}
+		}
+		return fn(ctx, s)
+	})
+}
+
+// invalidate drops the cached payloads a batch changed. A failure is only
+// logged: Postgres has the data, and the cache TTLs bound how long a stale
+// payload is served.
+func (in *Ingester) invalidate(ctx context.Context, what string, keys ...string) {
+	if err := in.cache.Invalidate(ctx, keys...); err != nil {
+		logx.WithContext(ctx).Errorf("ingest: invalidate %s: %v", what, err)
+	}
+}
+
+func (in *Ingester) publish(ctx context.Context, typ, arena string, models, ids []string) {
+	in.bus.Publish(ctx, types.ChangeEvent{
+		Type:     typ,
+		Arena:    arena,
+		ModelIds: models,
+		Ids:      ids,
+		Count:    len(ids),
+		Ts:       in.now().UnixMilli(),
+	})
+}
+
+// modelIds returns the distinct model ids of items in order of appearance.
+func modelIds[T any](items []T, id func(T) string) []string {
+	var out []string
+	seen := map[string]struct{}{}
+	for _, it := range items {
+		m := id(it)
+		if _, ok := seen[m]; ok || m == "" {
+			continue
+		}
+		seen[m] = struct{}{}
+		out = append(out, m)
+	}
+	return out
+}
+
+func seconds(s int) time.Duration { return time.Duration(s) * time.Second }
@@ -0,0 +1,164 @@
+package ingest
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/cache"
+	"nof0-api/internal/config"
+	"nof0-api/internal/events"
+	"nof0-api/internal/types"
+)
+
+// arrayConverter passes []string through as the pgx driver does.
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	if ss, ok := v.([]string); ok {
+		return ss, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+func newTestIngester(t *testing.T) (*Ingester, sqlmock.Sqlmock, *miniredis.Miniredis, *events.Bus) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	mr := miniredis.RunT(t)
+	rds := redis.New(mr.Addr())
+	bus := events.NewBus(rds)
+	in := New(sqlx.NewSqlConnFromDB(db), cache.New(rds), bus,
+		config.IngestConf{DedupeTTL: 3600})
+	in.now = func() time.Time { return time.UnixMilli(1760740000000) }
+	return in, mock, mr, bus
+}
+
+func expectRegistration(mock sqlmock.Sqlmock, arena string, models ...string) {
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO arenas").WithArgs(arena).WillReturnResult(sqlmock.NewResult(0, 0))
+	for _, m := range models {
+		mock.ExpectExec("INSERT INTO models").WithArgs(m, m).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO arena_models").WithArgs(arena, m).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+}
+
+func TestDecode(t *testing.T) {
+	one, err := Decode[types.CryptoPrice](strings.NewReader(`{"symbol":"BTC","price":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, []types.CryptoPrice{{Symbol: "BTC", Price: 1}}, one)
+
+	many, err := Decode[types.CryptoPrice](strings.NewReader(` [{"symbol":"BTC"},{"symbol":"ETH"}]`))
+	require.NoError(t, err)
+	assert.Len(t, many, 2)
+
+	_, err = Decode[types.CryptoPrice](strings.NewReader(`[]`))
+	assert.ErrorIs(t, err, ErrEmpty)
+	_, err = Decode[types.CryptoPrice](strings.NewReader(`{"symbol":`))
+	assert.Error(t, err)
+}
+
+func TestIngestTradesDedupes(t *testing.T) {
+	in, mock, mr, bus := newTestIngester(t)
+	ctx := context.Background()
+	sub, unsubscribe := bus.Subscribe(1)
+	defer unsubscribe()
+	mr.Set(cache.TradesKey("s1"), "{}")
+	mr.Set(cache.AnalyticsKey("s1", "gpt-5"), "{}")
+
+	trades := []types.Trade{
+		{Id: "t1", ModelId: "gpt-5", Symbol: "BTC", Side: "long", EntryPrice: 100, EntryTime: 1760700000, ExitTime: 1760710000},
+		{Id: "t1", ModelId: "gpt-5", Symbol: "BTC", Side: "long", EntryPrice: 100, EntryTime: 1760700000, ExitTime: 1760710000},
+	}
+	expectRegistration(mock, "s1", "gpt-5")
+	mock.ExpectExec("INSERT INTO symbols").WithArgs("BTC").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO trades").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	res, err := in.Trades(ctx, "s1", trades)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Written: 1, Duplicates: 1, Ids: []string{"t1"}}, res)
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.True(t, mr.Exists(cache.IngestTradeKey("t1")))
+	assert.Equal(t, time.Hour, mr.TTL(cache.IngestTradeKey("t1")))
+	assert.False(t, mr.Exists(cache.TradesKey("s1")), "Trades drop the arena trades payload")
+	assert.False(t, mr.Exists(cache.AnalyticsKey("s1", "gpt-5")))
+	e := <-sub
+	assert.Equal(t, types.ChangeEvent{Type: events.TypeTrades, Arena: "s1", ModelIds: []string{"gpt-5"}, Ids: []string{"t1"}, Count: 1, Ts: 1760740000000}, e)
+
+	res, err = in.Trades(ctx, "s1", trades[:1])
+	require.NoError(t, err)
+	assert.Equal(t, Result{Duplicates: 1}, res, "Already ingested trades skip the database")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestTradesFailureAllowsRetry(t *testing.T) {
+	in, mock, mr, _ := newTestIngester(t)
+
+	expectRegistration(mock, "s1", "gpt-5")
+	mock.ExpectExec("INSERT INTO symbols").WillReturnError(errors.New("db down"))
+	mock.ExpectRollback()
+
+	_, err := in.Trades(context.Background(), "s1", []types.Trade{{Id: "t2", ModelId: "gpt-5", Symbol: "BTC"}})
+	require.Error(t, err)
+	assert.False(t, mr.Exists(cache.IngestTradeKey("t2")), "A failed batch is not marked as ingested")
+
+	_, err = in.Trades(context.Background(), "s1", []types.Trade{{Id: "t3", Symbol: "BTC"}})
+	assert.EqualError(t, err, "trades[0]: id, model_id and symbol are required")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIngestPositionsClosesMissing(t *testing.T) {
+	in, mock, mr, _ := newTestIngester(t)
+	mr.Set(cache.ArenaPositionsKey("s1"), "{}")
+
+	expectRegistration(mock, "s1", "gpt-5")
+	mock.ExpectExec("INSERT INTO symbols").WithArgs("ETH,X").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO positions").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE positions SET status='closed'").
+		WithArgs("s1", "gpt-5", int64(1760740000000), []string{"s1:gpt-5:ETH,X:1760700000000"}).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	res, err := in.Positions(context.Background(), "s1", []types.PositionsByModel{{
+		ModelId:   "gpt-5",
+		Positions: map[string]types.Position{"ETH,X": {Quantity: 2, EntryPrice: 4000, EntryTime: 1760700000}},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1:gpt-5:ETH,X:1760700000000"}, res.Ids, "Commas in symbols stay in one id")
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.False(t, mr.Exists(cache.ArenaPositionsKey("s1")))
+}
+
+func TestIngestConversationsSkipsKnown(t *testing.T) {
+	in, mock, _, _ := newTestIngester(t)
+	convs := []types.Conversation{
+		{ModelId: "gpt-5", Messages: []types.ConversationMessage{{Role: "user", Content: "hi", Timestamp: 1760700000.0}}},
+		{ModelId: "gpt-5", Messages: []types.ConversationMessage{{Content: "long BTC"}}},
+	}
+
+	expectRegistration(mock, "s1", "gpt-5")
+	mock.ExpectQuery("SELECT count").WithArgs("s1", ContentHash(convs[0])).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("SELECT count").WithArgs("s1", ContentHash(convs[1])).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery("INSERT INTO conversations").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(9)))
+	mock.ExpectQuery("INSERT INTO conversation_messages").WithArgs(int64(9), "assistant", "long BTC", int64(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(90)))
+	mock.ExpectCommit()
+
+	res, err := in.Conversations(context.Background(), "s1", convs)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Written: 1, Duplicates: 1, Ids: []string{"9"}}, res)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
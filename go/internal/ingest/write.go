@@ -0,0 +1,243 @@
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/types"
+)
+
+// SQL writers shared by the ingest API and cmd/importer. Each writes one row
+// (plus its children) on the given session and leaves transactions to the
+// caller.
+
+// ContentHash is the hex sha256 of v's JSON encoding (map keys are sorted, so
+// it is stable across runs).
+func ContentHash(v interface{}) string {
+	b, _ := json.Marshal(v)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// ToMs converts a JSON timestamp (seconds or ms, number or RFC 3339) to epoch
+// ms; anything else is 0.
+func ToMs(v interface{}) int64 {
+	switch t := v.(type) {
+	case int64:
+		return t
+	case int:
+		return int64(t)
+	case float64:
+		return data.ToMillis(t)
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if f, err := t.Float64(); err == nil {
+			return int64(f)
+		}
+		return 0
+	case string:
+		// iso8601 support best-effort
+		if ts, err := time.Parse(time.RFC3339, t); err == nil {
+			return ts.UnixMilli()
+		}
+		return 0
+	default:
+		return 0
+	}
+}
+
+// Exec runs a statement, wrapping its error.
+func Exec(ctx context.Context, s sqlx.Session, query string, args ...interface{}) error {
+	if _, err := s.ExecCtx(ctx, query, args...); err != nil {
+		return fmt.Errorf("exec failed: %w", err)
+	}
+	return nil
+}
+
+// UpsertModel only registers unknown models; existing registry attributes
+// (display name, color, status, ...) are managed via /api/models and kept.
+// The model is also enrolled in arena.
+func UpsertModel(ctx context.Context, s sqlx.Session, arena, id, display string) error {
+	q := `INSERT INTO models(id, display_name) VALUES ($1,$2)
+          ON CONFLICT (id) DO NOTHING`
+	if err := Exec(ctx, s, q, id, display); err != nil {
+		return err
+	}
+	q = `INSERT INTO arena_models(arena_id, model_id) VALUES ($1,$2) ON CONFLICT DO NOTHING`
+	return Exec(ctx, s, q, arena, id)
+}
+
+// UpsertArena registers an arena id (named after itself) if unknown.
+func UpsertArena(ctx context.Context, s sqlx.Session, id string) error {
+	return Exec(ctx, s, `INSERT INTO arenas(id, name) VALUES ($1,$1) ON CONFLICT (id) DO NOTHING`, id)
+}
+
+func UpsertSymbol(ctx context.Context, s sqlx.Session, symbol string) error {
+	q := `INSERT INTO symbols(symbol) VALUES ($1) ON CONFLICT (symbol) DO NOTHING`
+	return Exec(ctx, s, q, symbol)
+}
+
+// UpsertPriceLatest records the latest price of symbol; an older price never
+// replaces a newer one.
+func UpsertPriceLatest(ctx context.Context, s sqlx.Session, symbol string, price float64, ts int64, hash string) error {
+	q := `INSERT INTO price_latest(symbol, price, ts_ms, content_hash) VALUES ($1,$2,$3,$4)
+          ON CONFLICT (symbol) DO UPDATE SET price=EXCLUDED.price, ts_ms=EXCLUDED.ts_ms, content_hash=EXCLUDED.content_hash
+          WHERE price_latest.ts_ms <= EXCLUDED.ts_ms`
+	return Exec(ctx, s, q, symbol, price, ts, hash)
+}
+
+// InsertPriceTick appends a price to the symbol's history.
+func InsertPriceTick(ctx context.Context, s sqlx.Session, symbol string, price float64, ts int64) error {
+	return Exec(ctx, s, `INSERT INTO price_ticks(symbol, price, ts_ms) VALUES ($1,$2,$3)`, symbol, price, ts)
+}
+
+// UpsertEquitySnapshot writes an account total and replaces its position
+// snapshots.
+func UpsertEquitySnapshot(ctx context.Context, s sqlx.Session, arena, sourceId string, at *types.AccountTotal, hash string) error {
+	q := `INSERT INTO account_equity_snapshots(
+            arena_id, source_id, model_id, ts_ms, equity_usd, realized_pnl, unrealized_pnl, cum_pnl_pct, sharpe_ratio,
+            since_inception_hourly_marker, since_inception_minute_marker, content_hash)
+          VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+          ON CONFLICT (arena_id, source_id) DO UPDATE SET
+            model_id=EXCLUDED.model_id, ts_ms=EXCLUDED.ts_ms, equity_usd=EXCLUDED.equity_usd,
+            realized_pnl=EXCLUDED.realized_pnl, unrealized_pnl=EXCLUDED.unrealized_pnl, cum_pnl_pct=EXCLUDED.cum_pnl_pct,
+            sharpe_ratio=EXCLUDED.sharpe_ratio, since_inception_hourly_marker=EXCLUDED.since_inception_hourly_marker,
+            since_inception_minute_marker=EXCLUDED.since_inception_minute_marker, content_hash=EXCLUDED.content_hash
+          RETURNING id`
+	var id int64
+	if err := s.QueryRowCtx(ctx, &id, q, arena, sourceId, at.ModelId, data.ToMillis(at.Timestamp), at.DollarEquity,
+		at.RealizedPnl, at.TotalUnrealizedPnl, at.CumPnlPct, at.SharpeRatio,
+		at.SinceInceptionHourlyMarker, at.SinceInceptionMinuteMarker, hash); err != nil {
+		return fmt.Errorf("upsert equity snapshot: %w", err)
+	}
+
+	if err := Exec(ctx, s, `DELETE FROM account_position_snapshots WHERE snapshot_id=$1`, id); err != nil {
+		return err
+	}
+	q = `INSERT INTO account_position_snapshots(snapshot_id, symbol, side, quantity, entry_price, current_price,
+            unrealized_pnl, leverage, payload)
+          VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`
+	for sym, p := range at.Positions {
+		if err := Exec(ctx, s, q, id, sym, data.PositionSide(p.Quantity), p.Quantity, p.EntryPrice,
+			nullFloat(p.CurrentPrice), nullFloat(p.UnrealizedPnl), nullFloat(p.Leverage), nullJSON(p)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func UpsertTrade(ctx context.Context, s sqlx.Session, arena string, t *types.Trade, entryMs, exitMs int64, hash string) error {
+	q := `INSERT INTO trades(
+            id, arena_id, model_id, symbol, side, trade_type, quantity, leverage, confidence,
+            entry_price, entry_ts_ms, exit_price, exit_ts_ms,
+            realized_gross_pnl, realized_net_pnl, total_commission_dollars, content_hash)
+          VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17)
+          ON CONFLICT (id) DO UPDATE SET
+            arena_id=EXCLUDED.arena_id, model_id=EXCLUDED.model_id, symbol=EXCLUDED.symbol, side=EXCLUDED.side,
+            trade_type=EXCLUDED.trade_type, quantity=EXCLUDED.quantity, leverage=EXCLUDED.leverage,
+            confidence=EXCLUDED.confidence, entry_price=EXCLUDED.entry_price, entry_ts_ms=EXCLUDED.entry_ts_ms,
+            exit_price=EXCLUDED.exit_price, exit_ts_ms=EXCLUDED.exit_ts_ms, realized_gross_pnl=EXCLUDED.realized_gross_pnl,
+            realized_net_pnl=EXCLUDED.realized_net_pnl, total_commission_dollars=EXCLUDED.total_commission_dollars,
+            content_hash=EXCLUDED.content_hash`
+	return Exec(ctx, s, q, t.Id, arena, t.ModelId, t.Symbol, t.Side, nullIfEmpty(t.TradeType),
+		nullFloat(t.Quantity), nullFloat(t.Leverage), nullFloat(t.Confidence), t.EntryPrice, entryMs,
+		t.ExitPrice, exitMs, t.RealizedGrossPnl, t.RealizedNetPnl, t.TotalCommissionDollars, hash)
+}
+
+func nullIfEmpty(s string) interface{} {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return s
+}
+
+func nullFloat(f float64) interface{} {
+	if f == 0 {
+		return nil
+	}
+	return f
+}
+
+// PositionID identifies a position by where and when it was opened.
+func PositionID(arena, modelId, symbol string, entryMs int64) string {
+	return fmt.Sprintf("%s:%s:%s:%d", arena, modelId, symbol, entryMs)
+}
+
+// UpsertPositionOpen writes an open position with all of its fields; side
+// follows the sign of quantity. A closed or liquidated row is re-opened at
+// nowMs; otherwise the status of an existing row is left to its lifecycle.
+func UpsertPositionOpen(ctx context.Context, s sqlx.Session, arena, modelId, symbol string, pos *types.Position, entryMs int64, hash string, nowMs int64) error {
+	q := `INSERT INTO positions(
+            id, arena_id, model_id, symbol, side, entry_price, quantity, leverage, confidence, entry_ts_ms,
+            current_price, liquidation_price, commission, margin, risk_usd, closed_pnl, unrealized_pnl, slippage,
+            exit_plan, entry_oid, tp_oid, sl_oid, oid, wait_for_fill, index_col, status, content_hash)
+          VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,'open',$26)
+          ON CONFLICT (id) DO UPDATE SET
+            side=EXCLUDED.side, entry_price=EXCLUDED.entry_price, quantity=EXCLUDED.quantity, leverage=EXCLUDED.leverage,
+            confidence=EXCLUDED.confidence, current_price=EXCLUDED.current_price, liquidation_price=EXCLUDED.liquidation_price,
+            commission=EXCLUDED.commission, margin=EXCLUDED.margin, risk_usd=EXCLUDED.risk_usd, closed_pnl=EXCLUDED.closed_pnl,
+            unrealized_pnl=EXCLUDED.unrealized_pnl, slippage=EXCLUDED.slippage, exit_plan=EXCLUDED.exit_plan,
+            entry_oid=EXCLUDED.entry_oid, tp_oid=EXCLUDED.tp_oid, sl_oid=EXCLUDED.sl_oid, oid=EXCLUDED.oid,
+            wait_for_fill=EXCLUDED.wait_for_fill, index_col=EXCLUDED.index_col, content_hash=EXCLUDED.content_hash,
+            status=CASE WHEN positions.status IN ('closed','liquidated') THEN 'open' ELSE positions.status END,
+            status_ts_ms=CASE WHEN positions.status IN ('closed','liquidated') THEN $27 ELSE positions.status_ts_ms END,
+            exit_price=CASE WHEN positions.status IN ('closed','liquidated') THEN NULL ELSE positions.exit_price END,
+            exit_ts_ms=CASE WHEN positions.status IN ('closed','liquidated') THEN NULL ELSE positions.exit_ts_ms END`
+	pid := PositionID(arena, modelId, symbol, entryMs)
+	return Exec(ctx, s, q, pid, arena, modelId, symbol, data.PositionSide(pos.Quantity), pos.EntryPrice, pos.Quantity,
+		nullFloat(pos.Leverage), nullFloat(pos.Confidence), entryMs,
+		nullFloat(pos.CurrentPrice), nullFloat(pos.LiquidationPrice), nullFloat(pos.Commission), nullFloat(pos.Margin),
+		nullFloat(pos.RiskUsd), nullFloat(pos.ClosedPnl), nullFloat(pos.UnrealizedPnl), nullFloat(pos.Slippage),
+		nullJSON(pos.ExitPlan), nullIfZeroID(pos.EntryOid), nullIfZeroID(pos.TpOid), nullIfZeroID(pos.SlOid), nullIfZeroID(pos.Oid),
+		pos.WaitForFill, nullJSON(pos.IndexCol), hash, nowMs)
+}
+
+func InsertConversation(ctx context.Context, s sqlx.Session, arena, modelId, hash string) (int64, error) {
+	q := `INSERT INTO conversations(arena_id, model_id, content_hash) VALUES ($1,$2,$3) RETURNING id`
+	var id int64
+	if err := s.QueryRowCtx(ctx, &id, q, arena, modelId, hash); err != nil {
+		return 0, fmt.Errorf("insert conversation: %w", err)
+	}
+	return id, nil
+}
+
+func InsertConversationMessage(ctx context.Context, s sqlx.Session, convId int64, role, content string, ts int64) (int64, error) {
+	if role == "" {
+		role = "assistant"
+	}
+	q := `INSERT INTO conversation_messages(conversation_id, role, content, ts_ms) VALUES ($1,$2,$3,$4) RETURNING id`
+	var id int64
+	if err := s.QueryRowCtx(ctx, &id, q, convId, role, content, ts); err != nil {
+		return 0, fmt.Errorf("insert conversation message: %w", err)
+	}
+	return id, nil
+}
+
+// nullJSON encodes v for a jsonb column; nil stays NULL.
+func nullJSON(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return string(b)
+}
+
+func nullIfZeroID(id int64) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
@@ -0,0 +1,43 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/types"
+)
+
+func TestUpsertEquitySnapshot(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	at := &types.AccountTotal{
+		Id: "snap-1", ModelId: "gpt-5", Timestamp: 1760740000, DollarEquity: 10500, RealizedPnl: 120,
+		TotalUnrealizedPnl: 380, CumPnlPct: 5, SharpeRatio: 0.8, SinceInceptionHourlyMarker: 12, SinceInceptionMinuteMarker: 720,
+		Positions: map[string]types.Position{"BTC": {Symbol: "BTC", Quantity: -0.5, EntryPrice: 107000, CurrentPrice: 106000}},
+	}
+	mock.ExpectQuery("INSERT INTO account_equity_snapshots").
+		WithArgs("default", "snap-1", "gpt-5", int64(1760740000000), 10500.0, 120.0, 380.0, 5.0, 0.8, 12, 720, "h").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(7)))
+	mock.ExpectExec("DELETE FROM account_position_snapshots").WithArgs(int64(7)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO account_position_snapshots").
+		WithArgs(int64(7), "BTC", "short", -0.5, 107000.0, 106000.0, nil, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	require.NoError(t, UpsertEquitySnapshot(context.Background(), sqlx.NewSqlConnFromDB(db), "default", "snap-1", at, "h"))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContentHash(t *testing.T) {
+	a := ContentHash(map[string]interface{}{"b": 1, "a": []int{1, 2}})
+	b := ContentHash(map[string]interface{}{"a": []int{1, 2}, "b": 1})
+	assert.Equal(t, a, b, "Hashes should not depend on map order")
+	assert.Len(t, a, 64)
+	assert.NotEqual(t, a, ContentHash(map[string]interface{}{"a": []int{2, 1}, "b": 1}))
+}
@@ -0,0 +1,152 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/cache"
+)
+
+// Built-in job names accepted in the Jobs config.
+const (
+	JobRefreshViews   = "refresh_views"
+	JobLeaderboard    = "leaderboard"
+	JobAnalytics      = "analytics"
+	JobEquitySnapshot = "equity_snapshot"
+)
+
+// Builtin returns the Postgres-backed jobs by name. c has stale keys
+// dropped.
+func Builtin(conn sqlx.SqlConn, c *cache.Cache) map[string]Func {
+	b := builtin{conn: conn, cache: c}
+	return map[string]Func{
+		JobRefreshViews:   b.refreshViews,
+		JobLeaderboard:    b.leaderboard,
+		JobAnalytics:      b.analytics,
+		JobEquitySnapshot: b.equitySnapshot,
+	}
+}
+
+type builtin struct {
+	conn  sqlx.SqlConn
+	cache *cache.Cache
+}
+
+func (b builtin) refreshViews(ctx context.Context) (string, error) {
+	if _, err := b.conn.ExecCtx(ctx, `SELECT refresh_views_nof0()`); err != nil {
+		return "", err
+	}
+	if err := b.cache.Invalidate(ctx, cache.CryptoPricesKey); err != nil {
+		return "", err
+	}
+	return "refreshed v_crypto_prices_latest, v_leaderboard, v_since_inception", nil
+}
+
+// leaderboard refreshes v_leaderboard. The /leaderboard payloads are
+// computed from the tables, so no cache key depends on the view.
+func (b builtin) leaderboard(ctx context.Context) (string, error) {
+	if _, err := b.conn.ExecCtx(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY v_leaderboard`); err != nil {
+		return "", err
+	}
+	return "refreshed v_leaderboard", nil
+}
+
+// analytics recomputes the trade-derived fields of each model's overview
+// table from closed trades, keeping the rest of the imported payload.
+func (b builtin) analytics(ctx context.Context) (string, error) {
+	const q = `WITH closed AS (
+            SELECT arena_id, model_id, id, exit_ts_ms,
+                   (exit_ts_ms - entry_ts_ms) / 60000.0 AS holding_mins,
+                   abs(quantity * entry_price)         AS notional
+            FROM trades
+            WHERE entry_ts_ms IS NOT NULL AND exit_ts_ms IS NOT NULL
+          ), s AS (
+            SELECT arena_id, model_id,
+                   count(*)                                                  AS total_trades,
+                   avg(holding_mins)                                         AS avg_holding,
+                   percentile_cont(0.5) WITHIN GROUP (ORDER BY holding_mins) AS median_holding,
+                   coalesce(stddev_samp(holding_mins), 0)                    AS std_holding,
+                   avg(notional)                                             AS avg_notional,
+                   percentile_cont(0.5) WITHIN GROUP (ORDER BY notional)     AS median_notional,
+                   coalesce(stddev_samp(notional), 0)                        AS std_notional,
+                   max(exit_ts_ms)                                           AS last_exit_ms,
+                   (array_agg(id ORDER BY exit_ts_ms DESC))[1]               AS last_trade_id
+            FROM closed
+            GROUP BY arena_id, model_id
+          )
+          INSERT INTO model_analytics(arena_id, model_id, updated_at, payload)
+          SELECT arena_id, model_id, now(), jsonb_build_object(
+                   'id', model_id,
+                   'model_id', model_id,
+                   'updated_at', extract(epoch FROM now()),
+                   'last_trade_exit_time', last_exit_ms / 1000.0,
+                   'last_trade_doc_id', last_trade_id,
+                   'overall_trades_overview_table', jsonb_build_object(
+                       'total_trades', total_trades,
+                       'avg_holding_period_mins', avg_holding,
+                       'median_holding_period_mins', median_holding,
+                       'std_holding_period_mins', std_holding,
+                       'avg_size_of_trade_notional', avg_notional,
+                       'median_size_of_trade_notional', median_notional,
+                       'std_size_of_trade_notional', std_notional))
+          FROM s
+          ON CONFLICT (arena_id, model_id) DO UPDATE SET
+              updated_at = EXCLUDED.updated_at,
+              payload = model_analytics.payload
+                  || (EXCLUDED.payload - 'overall_trades_overview_table')
+                  || jsonb_build_object('overall_trades_overview_table',
+                         coalesce(model_analytics.payload->'overall_trades_overview_table', '{}'::jsonb)
+                         || (EXCLUDED.payload->'overall_trades_overview_table'))`
+	res, err := b.conn.ExecCtx(ctx, q)
+	if err != nil {
+		return "", err
+	}
+	// Matches the arena and per-model analytics payloads of every arena.
+	if err := b.cache.InvalidateMatch(ctx, cache.ArenaAnalyticsKey("*")); err != nil {
+		return "", err
+	}
+	n, _ := res.RowsAffected()
+	return fmt.Sprintf("recomputed analytics for %d models", n), nil
+}
+
+// equitySnapshot records each arena model's current equity: starting
+// capital plus realized PnL of its trades plus unrealized PnL of its open
+// positions marked to the latest price.
+func (b builtin) equitySnapshot(ctx context.Context) (string, error) {
+	const q = `WITH realized AS (
+            SELECT arena_id, model_id, sum(realized_net_pnl) AS pnl
+            FROM trades GROUP BY arena_id, model_id
+          ), unrealized AS (
+            SELECT p.arena_id, p.model_id,
+                   sum((coalesce(pl.price, p.current_price, p.entry_price) - p.entry_price) * p.quantity) AS pnl
+            FROM positions p
+            LEFT JOIN price_latest pl ON pl.symbol = p.symbol
+            WHERE p.status IN ('open','reduced')
+            GROUP BY p.arena_id, p.model_id
+          ), eq AS (
+            SELECT am.arena_id, am.model_id, m.starting_capital,
+                   coalesce(r.pnl, 0) AS realized, coalesce(u.pnl, 0) AS unrealized
+            FROM arena_models am
+            JOIN models m ON m.id = am.model_id
+            LEFT JOIN realized r ON r.arena_id = am.arena_id AND r.model_id = am.model_id
+            LEFT JOIN unrealized u ON u.arena_id = am.arena_id AND u.model_id = am.model_id
+          )
+          INSERT INTO account_equity_snapshots(arena_id, model_id, ts_ms, equity_usd, realized_pnl, unrealized_pnl, cum_pnl_pct)
+          SELECT arena_id, model_id, $1, starting_capital + realized + unrealized, realized, unrealized,
+                 CASE WHEN starting_capital > 0 THEN (realized + unrealized) / starting_capital * 100 END
+          FROM eq`
+	res, err := b.conn.ExecCtx(ctx, q, time.Now().UnixMilli())
+	if err != nil {
+		return "", err
+	}
+	for _, pattern := range []string{cache.AccountTotalsKey("*"), cache.SinceInceptionKey("*"), cache.LeaderboardCacheKey("*")} {
+		if err := b.cache.InvalidateMatch(ctx, pattern); err != nil {
+			return "", err
+		}
+	}
+	n, _ := res.RowsAffected()
+	return fmt.Sprintf("recorded %d equity snapshots", n), nil
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	at := func(s string) time.Time {
+		v, err := time.Parse(time.DateTime, s)
+		require.NoError(t, err)
+		return v
+	}
+	base := at("2025-10-17 10:07:30") // a Friday
+
+	cases := []struct{ spec, want string }{
+		{"* * * * *", "2025-10-17 10:08:00"},
+		{"*/5 * * * *", "2025-10-17 10:10:00"},
+		{"0 * * * *", "2025-10-17 11:00:00"},
+		{"@hourly", "2025-10-17 11:00:00"},
+		{"30 2 * * *", "2025-10-18 02:30:00"},
+		{"@daily", "2025-10-18 00:00:00"},
+		{"0 9-17/4 * * 1-5", "2025-10-17 13:00:00"},
+		{"0 0 * * 0", "2025-10-19 00:00:00"},
+		{"0 0 * * 7", "2025-10-19 00:00:00"},
+		{"0 0 1 * 5", "2025-10-24 00:00:00"}, // day-of-month or weekday
+		{"0 0 29 2 *", "2028-02-29 00:00:00"},
+		{"15,45 * * * *", "2025-10-17 10:15:00"},
+		{"@every 30s", "2025-10-17 10:08:00"},
+		{"@every 1h", "2025-10-17 11:00:00"},
+	}
+	for _, c := range cases {
+		s, err := ParseSchedule(c.spec)
+		require.NoError(t, err, c.spec)
+		assert.Equal(t, at(c.want), s.Next(base), c.spec)
+	}
+
+	for _, bad := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@every 10ms", "@every soon"} {
+		_, err := ParseSchedule(bad)
+		assert.Error(t, err, bad)
+	}
+}
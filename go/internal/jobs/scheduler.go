@@ -0,0 +1,228 @@
+// Package jobs runs periodic maintenance (view refreshes, analytics,
+// leaderboard and equity snapshots) inside the API process. Each job runs at
+// most once at a time: in-process, and across instances through a Redis lock
+// at nof0:lock:job:{name} when Redis is configured.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+
+	"nof0-api/internal/config"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/types"
+)
+
+// Run outcomes reported in JobStatus.LastStatus.
+const (
+	StatusOk      = "ok"
+	StatusError   = "error"
+	StatusSkipped = "skipped" // another run held the lock
+)
+
+var (
+	ErrUnknownJob = errs.NotFound("unknown job")
+	ErrLocked     = errs.Unavailable("job is already running")
+)
+
+// Func does one run of a job and returns a short summary of what it did.
+type Func func(ctx context.Context) (string, error)
+
+type job struct {
+	schedule Schedule
+	timeout  time.Duration
+	fn       Func
+	status   types.JobStatus
+}
+
+// Scheduler runs registered jobs on their schedules.
+type Scheduler struct {
+	rds *redis.Redis
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	started bool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewScheduler returns an empty scheduler; rds may be nil for in-process
+// locking only.
+func NewScheduler(rds *redis.Redis) *Scheduler {
+	return &Scheduler{rds: rds, jobs: map[string]*job{}}
+}
+
+// Add registers fn under name to run on spec (see ParseSchedule), each run
+// bounded by timeout.
+func (s *Scheduler) Add(name, spec string, timeout time.Duration, fn Func) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("job %s: scheduled twice", name)
+	}
+	s.jobs[name] = &job{schedule: schedule, timeout: timeout, fn: fn,
+		status: types.JobStatus{Name: name, Schedule: spec}}
+	return nil
+}
+
+// Configure adds every enabled job in confs, looking its function up by name.
+func (s *Scheduler) Configure(confs []config.JobConf, funcs map[string]Func) error {
+	for _, c := range confs {
+		if c.Disabled {
+			continue
+		}
+		fn, ok := funcs[c.Name]
+		if !ok {
+			return fmt.Errorf("job %s: %w", c.Name, ErrUnknownJob)
+		}
+		if err := s.Add(c.Name, c.Schedule, time.Duration(c.Timeout)*time.Second, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start runs every job on its schedule until Stop.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+	s.stop = make(chan struct{})
+	for name, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(name, j.schedule, s.stop)
+	}
+}
+
+// Stop stops scheduling and waits for running jobs to finish.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return
+	}
+	s.started = false
+	close(s.stop)
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(name string, schedule Schedule, stop <-chan struct{}) {
+	defer s.wg.Done()
+	for {
+		timer := time.NewTimer(time.Until(schedule.Next(time.Now())))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			// Failures are recorded in the status and logged by Run.
+			_ = s.Run(context.Background(), name)
+		}
+	}
+}
+
+// Run runs the job now unless a run is already in progress, here or (with
+// Redis) in another instance, in which case it returns ErrLocked.
+func (s *Scheduler) Run(ctx context.Context, name string) error {
+	s.mu.Lock()
+	j, ok := s.jobs[name]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("job %s: %w", name, ErrUnknownJob)
+	}
+	if j.status.Running {
+		j.status.Skipped++
+		s.mu.Unlock()
+		return ErrLocked
+	}
+	j.status.Running = true
+	s.mu.Unlock()
+
+	start := time.Now()
+	result, err := s.runLocked(ctx, name, j)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.status.Running = false
+	switch {
+	case errors.Is(err, ErrLocked):
+		j.status.Skipped++
+		j.status.LastStatus = StatusSkipped
+		return err
+	case err != nil:
+		j.status.Failures++
+		j.status.LastStatus, j.status.LastError, j.status.LastResult = StatusError, err.Error(), ""
+		logx.Errorf("job %s failed: %v", name, err)
+	default:
+		j.status.LastStatus, j.status.LastError, j.status.LastResult = StatusOk, "", result
+		logx.Infof("job %s: %s", name, result)
+	}
+	j.status.Runs++
+	j.status.LastRunAt = start.UnixMilli()
+	j.status.LastDurationMs = time.Since(start).Milliseconds()
+	return err
+}
+
+func (s *Scheduler) runLocked(ctx context.Context, name string, j *job) (result string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, j.timeout)
+	defer cancel()
+
+	if s.rds != nil {
+		lock := redis.NewRedisLock(s.rds, "nof0:lock:job:"+name)
+		lock.SetExpire(int((j.timeout + time.Second - 1) / time.Second))
+		ok, err := lock.AcquireCtx(ctx)
+		if err != nil {
+			return "", fmt.Errorf("acquire lock: %w", err)
+		}
+		if !ok {
+			return "", ErrLocked
+		}
+		defer func() {
+			if _, err := lock.ReleaseCtx(context.Background()); err != nil {
+				logx.Errorf("job %s: release lock: %v", name, err)
+			}
+		}()
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return j.fn(ctx)
+}
+
+// Status lists every job, ordered by name.
+func (s *Scheduler) Status() []types.JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	out := make([]types.JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		st := j.status
+		if s.started {
+			st.NextRunAt = j.schedule.Next(now).UnixMilli()
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, k int) bool { return out[i].Name < out[k].Name })
+	return out
+}
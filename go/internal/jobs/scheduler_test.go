@@ -0,0 +1,95 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/stores/redis"
+
+	"nof0-api/internal/config"
+)
+
+func TestSchedulerRunRecordsStatus(t *testing.T) {
+	s := NewScheduler(nil)
+	fail := true
+	require.NoError(t, s.Add("flaky", "@every 1h", time.Second, func(ctx context.Context) (string, error) {
+		if fail {
+			return "", errors.New("boom")
+		}
+		return "did it", nil
+	}))
+	require.NoError(t, s.Add("panics", "@daily", time.Second, func(ctx context.Context) (string, error) {
+		panic("oops")
+	}))
+
+	assert.EqualError(t, s.Run(context.Background(), "flaky"), "boom")
+	fail = false
+	require.NoError(t, s.Run(context.Background(), "flaky"))
+	assert.EqualError(t, s.Run(context.Background(), "panics"), "panic: oops")
+	assert.ErrorIs(t, s.Run(context.Background(), "missing"), ErrUnknownJob)
+
+	st := s.Status()
+	require.Len(t, st, 2)
+	assert.Equal(t, "flaky", st[0].Name)
+	assert.Equal(t, StatusOk, st[0].LastStatus)
+	assert.Equal(t, "did it", st[0].LastResult)
+	assert.Empty(t, st[0].LastError)
+	assert.Equal(t, 2, st[0].Runs)
+	assert.Equal(t, 1, st[0].Failures)
+	assert.NotZero(t, st[0].LastRunAt)
+	assert.Zero(t, st[0].NextRunAt, "Not started")
+	assert.Equal(t, StatusError, st[1].LastStatus)
+}
+
+func TestSchedulerSingleFlight(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rds := redis.New(mr.Addr())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	slow := func(ctx context.Context) (string, error) {
+		close(started)
+		<-release
+		return "done", nil
+	}
+	a, b := NewScheduler(rds), NewScheduler(rds)
+	require.NoError(t, a.Add("refresh", "@every 1m", 10*time.Second, slow))
+	require.NoError(t, b.Add("refresh", "@every 1m", 10*time.Second, slow))
+
+	done := make(chan error)
+	go func() { done <- a.Run(context.Background(), "refresh") }()
+	<-started
+	assert.True(t, mr.Exists("nof0:lock:job:refresh"))
+	assert.ErrorIs(t, a.Run(context.Background(), "refresh"), ErrLocked, "Already running in this process")
+	assert.ErrorIs(t, b.Run(context.Background(), "refresh"), ErrLocked, "Locked by another instance")
+	assert.Equal(t, StatusSkipped, b.Status()[0].LastStatus)
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.False(t, mr.Exists("nof0:lock:job:refresh"), "Lock is released after the run")
+	assert.False(t, a.Status()[0].Running)
+}
+
+func TestSchedulerConfigure(t *testing.T) {
+	noop := func(ctx context.Context) (string, error) { return "", nil }
+	funcs := map[string]Func{"a": noop, "b": noop}
+
+	s := NewScheduler(nil)
+	require.NoError(t, s.Configure([]config.JobConf{
+		{Name: "a", Schedule: "* * * * *"},
+		{Name: "b", Schedule: "bad", Disabled: true},
+	}, funcs))
+	require.Len(t, s.Status(), 1)
+
+	assert.ErrorIs(t, NewScheduler(nil).Configure([]config.JobConf{{Name: "c", Schedule: "@hourly"}}, funcs), ErrUnknownJob)
+	assert.Error(t, NewScheduler(nil).Configure([]config.JobConf{{Name: "a", Schedule: "bad"}}, funcs))
+
+	s.Start()
+	assert.NotZero(t, s.Status()[0].NextRunAt)
+	s.Stop()
+}
@@ -5,7 +5,9 @@ package logic
 
 import (
 	"context"
+	"time"
 
+	"nof0-api/internal/data"
 	"nof0-api/internal/svc"
 	"nof0-api/internal/types"
 
@@ -27,5 +29,26 @@ func NewAccountTotalsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Acc
 }
 
 func (l *AccountTotalsLogic) AccountTotals(req *types.AccountTotalsRequest) (resp *types.AccountTotalsResponse, err error) {
-	return l.svcCtx.DataLoader.LoadAccountTotals()
+	models := joinModels(l.ctx, l.svcCtx)
+	if req.AsOf > 0 {
+		asOf := data.ToMillis(float64(req.AsOf))
+		history, err := loadHistory(l.ctx, l.svcCtx, models, asOf)
+		if err != nil {
+			return nil, err
+		}
+		return &types.AccountTotalsResponse{
+			AccountTotals: history.AccountTotals(asOf),
+			ServerTime:    time.Now().UnixMilli(),
+			AsOf:          asOf,
+			Models:        models,
+		}, nil
+	}
+
+	resp, err = l.svcCtx.Source(l.ctx).LoadAccountTotals()
+	if err != nil {
+		return nil, err
+	}
+	resp.Models = models
+	resp.Stale = l.svcCtx.Stale(data.ResourceAccountTotals, resp.DataAsOf)
+	return resp, nil
 }
@@ -6,6 +6,7 @@ package logic
 import (
 	"context"
 
+	"nof0-api/internal/data"
 	"nof0-api/internal/svc"
 	"nof0-api/internal/types"
 
@@ -27,5 +28,11 @@ func NewAnalyticsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Analyti
 }
 
 func (l *AnalyticsLogic) Analytics() (resp *types.AnalyticsResponse, err error) {
-	return l.svcCtx.DataLoader.LoadAnalytics()
+	resp, err = l.svcCtx.Source(l.ctx).LoadAnalytics()
+	if err != nil {
+		return nil, err
+	}
+	resp.Models = joinModels(l.ctx, l.svcCtx)
+	resp.Stale = l.svcCtx.Stale(data.ResourceAnalytics, resp.DataAsOf)
+	return resp, nil
 }
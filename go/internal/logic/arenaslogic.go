@@ -0,0 +1,35 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ArenasLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewArenasLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ArenasLogic {
+	return &ArenasLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ArenasLogic) Arenas() (resp *types.ArenasResponse, err error) {
+	return &types.ArenasResponse{
+		Arenas:     l.svcCtx.Arenas.List(),
+		ServerTime: time.Now().UnixMilli(),
+	}, nil
+}
@@ -0,0 +1,63 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ConversationLinksLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewConversationLinksLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ConversationLinksLogic {
+	return &ConversationLinksLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ConversationLinks lists which assistant decisions led to which trades and
+// open positions, optionally narrowed to a model, conversation or trade.
+func (l *ConversationLinksLogic) ConversationLinks(req *types.ConversationLinksRequest) (resp *types.ConversationLinksResponse, err error) {
+	links, err := l.svcCtx.Loader(l.ctx).ConversationLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	resp = &types.ConversationLinksResponse{
+		Links:      []types.ConversationLink{},
+		ServerTime: time.Now().UnixMilli(),
+	}
+	for _, link := range links {
+		switch {
+		case req.ModelId != "" && link.ModelId != req.ModelId:
+		case req.ConversationId > 0 && link.ConversationId != req.ConversationId:
+		case req.TradeId != "" && link.TradeId != req.TradeId:
+		default:
+			resp.Links = append(resp.Links, link)
+		}
+	}
+	return resp, nil
+}
+
+// conversationLinks returns the decision links for the request's arena.
+// Linking is best effort: failures are logged and yield no links.
+func conversationLinks(ctx context.Context, svcCtx *svc.ServiceContext) []types.ConversationLink {
+	links, err := svcCtx.Loader(ctx).ConversationLinks()
+	if err != nil {
+		logx.WithContext(ctx).Infof("conversation links unavailable: %v", err)
+		return nil
+	}
+	return links
+}
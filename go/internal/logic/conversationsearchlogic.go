@@ -0,0 +1,87 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/metrics"
+	"nof0-api/internal/snapshot"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const maxConversationSearchLimit = 200
+
+type ConversationSearchLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewConversationSearchLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ConversationSearchLogic {
+	return &ConversationSearchLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ConversationSearch filters conversation messages by model, role and time
+// and, when q is set, full-text matches their content. Postgres is used when
+// configured, falling back to the in-memory index over the data files.
+func (l *ConversationSearchLogic) ConversationSearch(req *types.ConversationSearchRequest) (resp *types.ConversationSearchResponse, err error) {
+	query := *req
+	query.Q = strings.TrimSpace(query.Q)
+	if query.Limit <= 0 || query.Limit > maxConversationSearchLimit {
+		query.Limit = maxConversationSearchLimit
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	if query.From > 0 {
+		query.From = data.ToMillis(float64(query.From))
+	}
+	if query.To > 0 {
+		query.To = data.ToMillis(float64(query.To))
+	}
+
+	hits, total, err := l.search(&query)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ConversationSearchResponse{
+		Query:      query.Q,
+		Total:      total,
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+		Hits:       hits,
+		ServerTime: time.Now().UnixMilli(),
+		Models:     joinModels(l.ctx, l.svcCtx),
+	}, nil
+}
+
+func (l *ConversationSearchLogic) search(query *types.ConversationSearchRequest) ([]types.ConversationHit, int, error) {
+	// Postgres holds live data only; snapshots are read from their files.
+	if l.svcCtx.ConversationSearch != nil && snapshot.FromContext(l.ctx) == "" {
+		hits, total, err := l.svcCtx.ConversationSearch.Search(l.ctx, l.svcCtx.ArenaId(l.ctx), query)
+		if err == nil {
+			return hits, total, nil
+		}
+		l.Errorf("db conversation search failed, falling back: %v", err)
+		metrics.DBFallbacks.Inc("conversation_search")
+	}
+
+	index, err := l.svcCtx.Loader(l.ctx).ConversationIndex()
+	if err != nil {
+		return nil, 0, err
+	}
+	hits, total := index.Search(query)
+	return hits, total, nil
+}
@@ -0,0 +1,37 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/types"
+)
+
+func TestConversationSearch(t *testing.T) {
+	svcCtx := createTestServiceContext(t)
+	logic := NewConversationSearchLogic(context.Background(), svcCtx)
+
+	resp, err := logic.ConversationSearch(&types.ConversationSearchRequest{Q: "  MACD ", Role: "assistant", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "MACD", resp.Query)
+	assert.Equal(t, 5, resp.Total)
+	assert.Equal(t, 2, resp.Limit)
+	assert.Len(t, resp.Hits, 2, "Hits should be paginated")
+	assert.NotZero(t, resp.ServerTime)
+
+	// from/to accept seconds like the source timestamps
+	resp, err = logic.ConversationSearch(&types.ConversationSearchRequest{From: 1760738000, To: 1760738200})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Total)
+	for _, hit := range resp.Hits {
+		assert.Equal(t, "gpt-5", hit.ModelId)
+	}
+
+	resp, err = logic.ConversationSearch(&types.ConversationSearchRequest{Limit: 10000})
+	require.NoError(t, err)
+	assert.Equal(t, maxConversationSearchLimit, resp.Limit, "Limit should be capped")
+	assert.Equal(t, 18, resp.Total)
+}
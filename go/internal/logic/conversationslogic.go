@@ -6,6 +6,7 @@ package logic
 import (
 	"context"
 
+	"nof0-api/internal/data"
 	"nof0-api/internal/svc"
 	"nof0-api/internal/types"
 
@@ -27,5 +28,12 @@ func NewConversationsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Con
 }
 
 func (l *ConversationsLogic) Conversations() (resp *types.ConversationsResponse, err error) {
-	return l.svcCtx.DataLoader.LoadConversations()
+	resp, err = l.svcCtx.Source(l.ctx).LoadConversations()
+	if err != nil {
+		return nil, err
+	}
+	data.ApplyConversationLinks(resp.Conversations, conversationLinks(l.ctx, l.svcCtx))
+	resp.Models = joinModels(l.ctx, l.svcCtx)
+	resp.Stale = l.svcCtx.Stale(data.ResourceConversations, resp.DataAsOf)
+	return resp, nil
 }
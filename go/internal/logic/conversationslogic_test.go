@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/types"
 )
 
 func TestConversations(t *testing.T) {
@@ -157,3 +159,42 @@ func BenchmarkConversations(b *testing.B) {
 		_, _ = logic.Conversations()
 	}
 }
+
+func TestConversationLinks(t *testing.T) {
+	svcCtx := createTestServiceContext(t)
+	logic := NewConversationLinksLogic(context.Background(), svcCtx)
+
+	resp, err := logic.ConversationLinks(&types.ConversationLinksRequest{ModelId: "gpt-5"})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Links)
+	var linked *types.ConversationLink
+	for i, link := range resp.Links {
+		assert.Equal(t, "gpt-5", link.ModelId)
+		if link.TradeId != "" && linked == nil {
+			linked = &resp.Links[i]
+		}
+	}
+	require.NotNil(t, linked)
+
+	trades, err := NewTradesLogic(context.Background(), svcCtx).Trades()
+	require.NoError(t, err)
+	var trade *types.Trade
+	for i := range trades.Trades {
+		if trades.Trades[i].Id == linked.TradeId {
+			trade = &trades.Trades[i]
+		}
+	}
+	require.NotNil(t, trade)
+	assert.Equal(t, linked.ConversationId, trade.ConversationId, "Linked trade should carry its conversation id")
+
+	convs, err := NewConversationsLogic(context.Background(), svcCtx).Conversations()
+	require.NoError(t, err)
+	var conv *types.Conversation
+	for i := range convs.Conversations {
+		if convs.Conversations[i].Id == linked.ConversationId {
+			conv = &convs.Conversations[i]
+		}
+	}
+	require.NotNil(t, conv)
+	assert.Contains(t, conv.TradeIds, linked.TradeId)
+}
@@ -0,0 +1,373 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	returnSourceAccountTotals = "account_totals"
+	returnSourceTrades        = "trades"
+
+	hourMs = int64(time.Hour / time.Millisecond)
+)
+
+type CorrelationLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCorrelationLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CorrelationLogic {
+	return &CorrelationLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Correlation compares how differently the models trade: pairwise correlation
+// of hourly equity returns, time spent holding the same/opposite direction per
+// symbol, and how often two models open the same-direction trade within
+// WindowMins of each other.
+func (l *CorrelationLogic) Correlation(req *types.CorrelationRequest) (resp *types.CorrelationResponse, err error) {
+	window := req.WindowMins
+	if window <= 0 {
+		window = 30
+	}
+
+	trades, err := l.svcCtx.Source(l.ctx).LoadTrades()
+	if err != nil {
+		return nil, err
+	}
+	positions, err := l.svcCtx.Source(l.ctx).LoadPositions()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	resp = &types.CorrelationResponse{
+		WindowMins: window,
+		ServerTime: now,
+	}
+
+	// Prefer real equity snapshots; fall back to a realized-PnL curve rebuilt
+	// from closed trades when account totals are unavailable.
+	var equity map[string]map[int64]float64
+	totals, err := l.svcCtx.Source(l.ctx).LoadAccountTotals()
+	if err == nil && len(totals.AccountTotals) > 0 {
+		resp.ReturnSource = returnSourceAccountTotals
+		equity = equityFromAccountTotals(totals.AccountTotals)
+	} else {
+		if err != nil {
+			l.Infof("account totals unavailable, deriving returns from trades: %v", err)
+		}
+		resp.ReturnSource = returnSourceTrades
+		equity = equityFromTrades(trades.Trades, startingCapital(joinModels(l.ctx, l.svcCtx)))
+	}
+
+	legs := directionLegs(trades.Trades, positions.AccountTotals, now)
+	resp.ReturnCorrelations = returnCorrelations(equity)
+	resp.DirectionOverlaps = directionOverlaps(legs)
+	resp.CoincidentTrades = coincidentTrades(legs, int64(window)*int64(time.Minute/time.Millisecond))
+	return resp, nil
+}
+
+// directionLeg is a period during which a model held one direction on a symbol.
+type directionLeg struct {
+	modelId string
+	symbol  string
+	long    bool
+	startMs int64
+	endMs   int64
+}
+
+// equityFromAccountTotals buckets equity snapshots by hour, keeping the most
+// recent snapshot within each hour.
+func equityFromAccountTotals(totals []types.AccountTotal) map[string]map[int64]float64 {
+	sorted := make([]types.AccountTotal, len(totals))
+	copy(sorted, totals)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+
+	out := map[string]map[int64]float64{}
+	for _, t := range sorted {
+		if t.ModelId == "" {
+			continue
+		}
+		if out[t.ModelId] == nil {
+			out[t.ModelId] = map[int64]float64{}
+		}
+		out[t.ModelId][data.ToMillis(t.Timestamp)/hourMs] = t.DollarEquity
+	}
+	return out
+}
+
+// equityFromTrades rebuilds an hourly realized-equity curve per model from
+// closed trades, starting from each model's capital. Every model shares the
+// same hour range so returns align.
+func equityFromTrades(trades []types.Trade, capital func(modelId string) float64) map[string]map[int64]float64 {
+	byModel := map[string][]types.Trade{}
+	first, last := int64(math.MaxInt64), int64(math.MinInt64)
+	for _, t := range trades {
+		if t.ModelId == "" || t.ExitTime == 0 {
+			continue
+		}
+		byModel[t.ModelId] = append(byModel[t.ModelId], t)
+		if h := data.ToMillis(t.EntryTime) / hourMs; h < first {
+			first = h
+		}
+		if h := data.ToMillis(t.ExitTime) / hourMs; h > last {
+			last = h
+		}
+	}
+
+	out := map[string]map[int64]float64{}
+	for modelId, ts := range byModel {
+		sort.Slice(ts, func(i, j int) bool { return ts[i].ExitTime < ts[j].ExitTime })
+		series := map[int64]float64{}
+		equity, i := capital(modelId), 0
+		for h := first; h <= last; h++ {
+			for i < len(ts) && data.ToMillis(ts[i].ExitTime)/hourMs <= h {
+				equity += ts[i].RealizedNetPnl
+				i++
+			}
+			series[h] = equity
+		}
+		out[modelId] = series
+	}
+	return out
+}
+
+// hourlyReturns converts an hourly equity series into simple returns keyed by
+// hour, only where the previous hour is also present.
+func hourlyReturns(series map[int64]float64) map[int64]float64 {
+	out := map[int64]float64{}
+	for h, eq := range series {
+		prev, ok := series[h-1]
+		if !ok || prev == 0 {
+			continue
+		}
+		out[h] = eq/prev - 1
+	}
+	return out
+}
+
+// returnCorrelations computes the Pearson correlation of hourly returns for
+// every model pair. Pairs with fewer than two shared observations or a flat
+// return series are omitted since the correlation is undefined.
+func returnCorrelations(equity map[string]map[int64]float64) []types.ReturnCorrelation {
+	returns := map[string]map[int64]float64{}
+	for modelId, series := range equity {
+		returns[modelId] = hourlyReturns(series)
+	}
+
+	out := []types.ReturnCorrelation{}
+	for _, pair := range modelPairs(returns) {
+		ra, rb := returns[pair[0]], returns[pair[1]]
+		var xs, ys []float64
+		for h, x := range ra {
+			if y, ok := rb[h]; ok {
+				xs = append(xs, x)
+				ys = append(ys, y)
+			}
+		}
+		corr, ok := pearson(xs, ys)
+		if !ok {
+			continue
+		}
+		out = append(out, types.ReturnCorrelation{
+			ModelA:          pair[0],
+			ModelB:          pair[1],
+			Correlation:     corr,
+			NumObservations: len(xs),
+		})
+	}
+	return out
+}
+
+func pearson(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	if len(xs) < 2 {
+		return 0, false
+	}
+	var sx, sy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+	}
+	mx, my := sx/n, sy/n
+	var cov, vx, vy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(vx*vy), true
+}
+
+// directionLegs flattens closed trades and currently open positions into
+// direction legs. Open positions are treated as held until now.
+func directionLegs(trades []types.Trade, positions []types.PositionsByModel, nowMs int64) []directionLeg {
+	legs := make([]directionLeg, 0, len(trades))
+	for _, t := range trades {
+		if t.ModelId == "" || t.Symbol == "" || t.EntryTime == 0 {
+			continue
+		}
+		end := data.ToMillis(t.ExitTime)
+		if t.ExitTime == 0 {
+			end = nowMs
+		}
+		legs = append(legs, directionLeg{
+			modelId: t.ModelId,
+			symbol:  t.Symbol,
+			long:    t.Side != "short",
+			startMs: data.ToMillis(t.EntryTime),
+			endMs:   end,
+		})
+	}
+	for _, pm := range positions {
+		for sym, p := range pm.Positions {
+			if p.Quantity == 0 || p.EntryTime == 0 {
+				continue
+			}
+			legs = append(legs, directionLeg{
+				modelId: pm.ModelId,
+				symbol:  sym,
+				long:    p.Quantity > 0,
+				startMs: data.ToMillis(p.EntryTime),
+				endMs:   nowMs,
+			})
+		}
+	}
+	return legs
+}
+
+type pairSymbolKey struct {
+	modelA, modelB, symbol string
+}
+
+// directionOverlaps measures, per symbol and model pair, how long both models
+// held the same direction versus opposite directions at the same time.
+func directionOverlaps(legs []directionLeg) []types.DirectionOverlap {
+	acc := map[pairSymbolKey]*types.DirectionOverlap{}
+	for i := range legs {
+		for j := i + 1; j < len(legs); j++ {
+			a, b := legs[i], legs[j]
+			if a.symbol != b.symbol || a.modelId == b.modelId {
+				continue
+			}
+			start, end := max(a.startMs, b.startMs), min(a.endMs, b.endMs)
+			if end <= start {
+				continue
+			}
+			if a.modelId > b.modelId {
+				a, b = b, a
+			}
+			key := pairSymbolKey{a.modelId, b.modelId, a.symbol}
+			o := acc[key]
+			if o == nil {
+				o = &types.DirectionOverlap{Symbol: a.symbol, ModelA: a.modelId, ModelB: b.modelId}
+				acc[key] = o
+			}
+			mins := float64(end-start) / float64(time.Minute/time.Millisecond)
+			if a.long == b.long {
+				o.SameDirectionMins += mins
+			} else {
+				o.OppositeDirectionMins += mins
+			}
+		}
+	}
+
+	out := make([]types.DirectionOverlap, 0, len(acc))
+	for _, o := range acc {
+		if total := o.SameDirectionMins + o.OppositeDirectionMins; total > 0 {
+			o.OverlapPct = o.SameDirectionMins / total * 100
+		}
+		out = append(out, *o)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ModelA != out[j].ModelA {
+			return out[i].ModelA < out[j].ModelA
+		}
+		if out[i].ModelB != out[j].ModelB {
+			return out[i].ModelB < out[j].ModelB
+		}
+		return out[i].Symbol < out[j].Symbol
+	})
+	return out
+}
+
+// coincidentTrades counts, per model pair, how many times both models opened a
+// same-direction position on the same symbol within windowMs of each other.
+func coincidentTrades(legs []directionLeg, windowMs int64) []types.CoincidentTrades {
+	acc := map[[2]string]*types.CoincidentTrades{}
+	for i := range legs {
+		for j := i + 1; j < len(legs); j++ {
+			a, b := legs[i], legs[j]
+			if a.symbol != b.symbol || a.modelId == b.modelId || a.long != b.long {
+				continue
+			}
+			gap := a.startMs - b.startMs
+			if gap < 0 {
+				gap = -gap
+			}
+			if gap > windowMs {
+				continue
+			}
+			if a.modelId > b.modelId {
+				a, b = b, a
+			}
+			key := [2]string{a.modelId, b.modelId}
+			c := acc[key]
+			if c == nil {
+				c = &types.CoincidentTrades{ModelA: a.modelId, ModelB: b.modelId, Counts: map[string]int{}}
+				acc[key] = c
+			}
+			c.Count++
+			c.Counts[a.symbol]++
+		}
+	}
+
+	out := make([]types.CoincidentTrades, 0, len(acc))
+	for _, c := range acc {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ModelA != out[j].ModelA {
+			return out[i].ModelA < out[j].ModelA
+		}
+		return out[i].ModelB < out[j].ModelB
+	})
+	return out
+}
+
+// modelPairs returns every unordered pair of map keys, sorted.
+func modelPairs[V any](m map[string]V) [][2]string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var pairs [][2]string
+	for i := range ids {
+		for j := i + 1; j < len(ids); j++ {
+			pairs = append(pairs, [2]string{ids[i], ids[j]})
+		}
+	}
+	return pairs
+}
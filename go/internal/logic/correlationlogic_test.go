@@ -0,0 +1,92 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/types"
+)
+
+func TestCorrelation(t *testing.T) {
+	svcCtx := createTestServiceContext(t)
+	logic := NewCorrelationLogic(context.Background(), svcCtx)
+
+	resp, err := logic.Correlation(&types.CorrelationRequest{WindowMins: 60})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	assert.Equal(t, 60, resp.WindowMins)
+	assert.NotEmpty(t, resp.ReturnSource)
+	assert.NotZero(t, resp.ServerTime)
+	assert.Greater(t, len(resp.DirectionOverlaps), 0, "Models should overlap on at least one symbol")
+
+	for _, c := range resp.ReturnCorrelations {
+		assert.Less(t, c.ModelA, c.ModelB, "Pairs should be ordered")
+		assert.GreaterOrEqual(t, c.Correlation, -1.0)
+		assert.LessOrEqual(t, c.Correlation, 1.0)
+		assert.GreaterOrEqual(t, c.NumObservations, 2)
+	}
+	for _, o := range resp.DirectionOverlaps {
+		assert.NotEmpty(t, o.Symbol)
+		assert.Less(t, o.ModelA, o.ModelB, "Pairs should be ordered")
+		assert.GreaterOrEqual(t, o.OverlapPct, 0.0)
+		assert.LessOrEqual(t, o.OverlapPct, 100.0)
+	}
+	for _, c := range resp.CoincidentTrades {
+		sum := 0
+		for _, n := range c.Counts {
+			sum += n
+		}
+		assert.Equal(t, c.Count, sum, "Per-symbol counts should add up to total")
+	}
+}
+
+func TestReturnCorrelations(t *testing.T) {
+	equity := map[string]map[int64]float64{
+		"a": {0: 100, 1: 110, 2: 99, 3: 108.9},
+		"b": {0: 200, 1: 220, 2: 198, 3: 217.8},
+		"c": {0: 100, 1: 90, 2: 99, 3: 89.1},
+		"d": {0: 100, 1: 100, 2: 100, 3: 100},
+	}
+
+	got := returnCorrelations(equity)
+	byPair := map[[2]string]types.ReturnCorrelation{}
+	for _, c := range got {
+		byPair[[2]string{c.ModelA, c.ModelB}] = c
+	}
+
+	assert.InDelta(t, 1.0, byPair[[2]string{"a", "b"}].Correlation, 1e-9)
+	assert.InDelta(t, -1.0, byPair[[2]string{"a", "c"}].Correlation, 1e-9)
+	assert.Equal(t, 3, byPair[[2]string{"a", "b"}].NumObservations)
+	_, hasFlat := byPair[[2]string{"a", "d"}]
+	assert.False(t, hasFlat, "Flat return series has no defined correlation")
+}
+
+func TestDirectionOverlapsAndCoincidentTrades(t *testing.T) {
+	const minute = int64(60_000)
+	legs := []directionLeg{
+		{modelId: "a", symbol: "BTC", long: true, startMs: 0, endMs: 60 * minute},
+		{modelId: "b", symbol: "BTC", long: true, startMs: 10 * minute, endMs: 40 * minute},
+		{modelId: "b", symbol: "BTC", long: false, startMs: 40 * minute, endMs: 90 * minute},
+		{modelId: "c", symbol: "ETH", long: true, startMs: 5 * minute, endMs: 20 * minute},
+	}
+
+	overlaps := directionOverlaps(legs)
+	require.Len(t, overlaps, 1)
+	assert.Equal(t, "BTC", overlaps[0].Symbol)
+	assert.InDelta(t, 30.0, overlaps[0].SameDirectionMins, 1e-9)
+	assert.InDelta(t, 20.0, overlaps[0].OppositeDirectionMins, 1e-9)
+	assert.InDelta(t, 60.0, overlaps[0].OverlapPct, 1e-9)
+
+	coincident := coincidentTrades(legs, 15*minute)
+	require.Len(t, coincident, 1)
+	assert.Equal(t, "a", coincident[0].ModelA)
+	assert.Equal(t, "b", coincident[0].ModelB)
+	assert.Equal(t, 1, coincident[0].Count)
+	assert.Equal(t, 1, coincident[0].Counts["BTC"])
+
+	assert.Empty(t, coincidentTrades(legs, 5*minute))
+}
@@ -0,0 +1,52 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/auth"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateApiKeyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateApiKeyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateApiKeyLogic {
+	return &CreateApiKeyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// CreateApiKey creates a key; the response is the only place its secret
+// appears.
+func (l *CreateApiKeyLogic) CreateApiKey(req *types.CreateApiKeyRequest) (resp *types.ApiKeyResponse, err error) {
+	if l.svcCtx.ApiKeys == nil {
+		return nil, errApiKeysUnavailable
+	}
+	role, err := auth.ParseRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+	key, err := l.svcCtx.ApiKeys.Create(l.ctx, req.Name, role)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := auth.FromContext(l.ctx); ok {
+		l.Infof("api key %s (%s, %s) created by %s", key.Id, key.Name, key.Role, p.Name)
+	}
+	return &types.ApiKeyResponse{
+		Key:        *key,
+		ServerTime: time.Now().UnixMilli(),
+	}, nil
+}
@@ -0,0 +1,49 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type CreateModelLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewCreateModelLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CreateModelLogic {
+	return &CreateModelLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *CreateModelLogic) CreateModel(req *types.CreateModelRequest) (resp *types.ModelResponse, err error) {
+	m, err := l.svcCtx.ModelRegistry.Create(l.ctx, types.ModelInfo{
+		Id:              req.Id,
+		DisplayName:     req.DisplayName,
+		Provider:        req.Provider,
+		Color:           req.Color,
+		IconUrl:         req.IconUrl,
+		StartingCapital: req.StartingCapital,
+		InceptionTime:   req.InceptionTime,
+		Status:          req.Status,
+		PromptVersion:   req.PromptVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &types.ModelResponse{
+		Model:      *m,
+		ServerTime: time.Now().UnixMilli(),
+	}, nil
+}
@@ -5,7 +5,9 @@ package logic
 
 import (
 	"context"
+	"time"
 
+	"nof0-api/internal/data"
 	"nof0-api/internal/svc"
 	"nof0-api/internal/types"
 
@@ -26,6 +28,23 @@ func NewCryptoPricesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Cryp
 	}
 }
 
-func (l *CryptoPricesLogic) CryptoPrices() (resp *types.CryptoPricesResponse, err error) {
-	return l.svcCtx.DataLoader.LoadCryptoPrices()
+func (l *CryptoPricesLogic) CryptoPrices(req *types.CryptoPricesRequest) (resp *types.CryptoPricesResponse, err error) {
+	if req.AsOf > 0 {
+		asOf := data.ToMillis(float64(req.AsOf))
+		history, err := data.NewHistory(l.svcCtx.Source(l.ctx), asOf)
+		if err != nil {
+			return nil, err
+		}
+		return &types.CryptoPricesResponse{
+			Prices:     history.Prices(asOf),
+			ServerTime: time.Now().UnixMilli(),
+			AsOf:       asOf,
+		}, nil
+	}
+	resp, err = l.svcCtx.Source(l.ctx).LoadCryptoPrices()
+	if err != nil {
+		return nil, err
+	}
+	resp.Stale = l.svcCtx.Stale(data.ResourcePrices, resp.DataAsOf)
+	return resp, nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"nof0-api/internal/config"
 	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,7 +23,7 @@ func TestCryptoPrices(t *testing.T) {
 	svcCtx := createTestServiceContext(t)
 	logic := NewCryptoPricesLogic(context.Background(), svcCtx)
 
-	resp, err := logic.CryptoPrices()
+	resp, err := logic.CryptoPrices(&types.CryptoPricesRequest{})
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 
@@ -48,7 +49,7 @@ func TestCryptoPricesTypes(t *testing.T) {
 	svcCtx := createTestServiceContext(t)
 	logic := NewCryptoPricesLogic(context.Background(), svcCtx)
 
-	resp, err := logic.CryptoPrices()
+	resp, err := logic.CryptoPrices(&types.CryptoPricesRequest{})
 	require.NoError(t, err)
 
 	// Validate data types
@@ -68,6 +69,6 @@ func BenchmarkCryptoPrices(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = logic.CryptoPrices()
+		_, _ = logic.CryptoPrices(&types.CryptoPricesRequest{})
 	}
 }
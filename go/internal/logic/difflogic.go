@@ -0,0 +1,82 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/diff"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type DiffLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDiffLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DiffLogic {
+	return &DiffLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Diff compares two versions of the selected arena.
+func (l *DiffLogic) Diff(req *types.DiffRequest) (resp *types.DiffResponse, err error) {
+	if req.Threshold < 0 {
+		return nil, errs.InvalidArgument("threshold must not be negative")
+	}
+	from, fromId, err := l.source(req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, toId, err := l.source(req.To)
+	if err != nil {
+		return nil, err
+	}
+	resp, err = diff.Compare(from, to, req.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	resp.From, resp.To = fromId, toId
+	return resp, nil
+}
+
+// source resolves "live", "latest" or a snapshot id to a loader and the name
+// reported for it; "latest" is reported as the snapshot id it stands for.
+func (l *DiffLogic) source(name string) (data.DataSource, string, error) {
+	arena := l.svcCtx.ArenaId(l.ctx)
+	if name == "live" {
+		dl, ok := l.svcCtx.Arenas.Loader(arena)
+		if !ok {
+			dl = l.svcCtx.DataLoader
+		}
+		return dl, name, nil
+	}
+	if l.svcCtx.Snapshots == nil {
+		return nil, "", errSnapshotsDisabled
+	}
+	if name == "latest" {
+		list, err := l.svcCtx.Snapshots.List(arena)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(list) == 0 {
+			return nil, "", errs.NotFound("arena %q has no snapshots yet", arena)
+		}
+		name = list[0].Id
+	}
+	dl, err := l.svcCtx.Snapshots.Loader(arena, name)
+	if err != nil {
+		return nil, "", errs.Wrap(errs.KindNotFound, err, "unknown snapshot %q of arena %q", name, arena)
+	}
+	return dl, name, nil
+}
@@ -0,0 +1,55 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/metrics"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type EventsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewEventsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *EventsLogic {
+	return &EventsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Events forwards change events published by ingest in this process until
+// the client disconnects. With ?arena= only that arena's events (and prices)
+// are sent.
+func (l *EventsLogic) Events(client chan<- *types.ChangeEvent) error {
+	arena := data.ArenaFromContext(l.ctx)
+	events, unsubscribe := l.svcCtx.Events.Subscribe(64)
+	defer unsubscribe()
+	metrics.Subscribers.Inc("sse")
+	defer metrics.Subscribers.Dec("sse")
+	for {
+		select {
+		case <-l.ctx.Done():
+			return nil
+		case e := <-events:
+			if arena != "" && e.Arena != "" && e.Arena != arena {
+				continue
+			}
+			select {
+			case client <- &e:
+			case <-l.ctx.Done():
+				return nil
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"nof0-api/internal/events"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type IngestAccountSnapshotsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewIngestAccountSnapshotsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *IngestAccountSnapshotsLogic {
+	return &IngestAccountSnapshotsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// IngestAccountSnapshots records account totals as equity snapshots.
+func (l *IngestAccountSnapshotsLogic) IngestAccountSnapshots(req []types.AccountTotal) (resp *types.IngestResponse, err error) {
+	if l.svcCtx.Ingest == nil {
+		return nil, errIngestUnavailable
+	}
+	res, err := l.svcCtx.Ingest.AccountSnapshots(l.ctx, l.svcCtx.ArenaId(l.ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	return ingestResponse(events.TypeAccountSnapshots, len(req), res), nil
+}
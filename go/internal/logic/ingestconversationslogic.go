@@ -0,0 +1,40 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"nof0-api/internal/events"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type IngestConversationsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewIngestConversationsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *IngestConversationsLogic {
+	return &IngestConversationsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// IngestConversations records new model conversations.
+func (l *IngestConversationsLogic) IngestConversations(req []types.Conversation) (resp *types.IngestResponse, err error) {
+	if l.svcCtx.Ingest == nil {
+		return nil, errIngestUnavailable
+	}
+	res, err := l.svcCtx.Ingest.Conversations(l.ctx, l.svcCtx.ArenaId(l.ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	return ingestResponse(events.TypeConversations, len(req), res), nil
+}
@@ -0,0 +1,40 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"nof0-api/internal/events"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type IngestPositionsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewIngestPositionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *IngestPositionsLogic {
+	return &IngestPositionsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// IngestPositions replaces the open positions of each listed model.
+func (l *IngestPositionsLogic) IngestPositions(req []types.PositionsByModel) (resp *types.IngestResponse, err error) {
+	if l.svcCtx.Ingest == nil {
+		return nil, errIngestUnavailable
+	}
+	res, err := l.svcCtx.Ingest.Positions(l.ctx, l.svcCtx.ArenaId(l.ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	return ingestResponse(events.TypePositions, len(req), res), nil
+}
@@ -0,0 +1,56 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/errs"
+	"nof0-api/internal/events"
+	"nof0-api/internal/ingest"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type IngestPricesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewIngestPricesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *IngestPricesLogic {
+	return &IngestPricesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// IngestPrices records the latest prices (shared by every arena).
+func (l *IngestPricesLogic) IngestPrices(req []types.CryptoPrice) (resp *types.IngestResponse, err error) {
+	if l.svcCtx.Ingest == nil {
+		return nil, errIngestUnavailable
+	}
+	res, err := l.svcCtx.Ingest.Prices(l.ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return ingestResponse(events.TypePrices, len(req), res), nil
+}
+
+var errIngestUnavailable = errs.Unavailable("ingest requires Postgres (Postgres.DSN)")
+
+func ingestResponse(typ string, received int, res ingest.Result) *types.IngestResponse {
+	return &types.IngestResponse{
+		Type:       typ,
+		Received:   received,
+		Written:    res.Written,
+		Duplicates: res.Duplicates,
+		Ids:        res.Ids,
+		ServerTime: time.Now().UnixMilli(),
+	}
+}
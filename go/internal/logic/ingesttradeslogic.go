@@ -0,0 +1,41 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"nof0-api/internal/events"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type IngestTradesLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewIngestTradesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *IngestTradesLogic {
+	return &IngestTradesLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// IngestTrades records trades of the selected arena, skipping ones already
+// ingested.
+func (l *IngestTradesLogic) IngestTrades(req []types.Trade) (resp *types.IngestResponse, err error) {
+	if l.svcCtx.Ingest == nil {
+		return nil, errIngestUnavailable
+	}
+	res, err := l.svcCtx.Ingest.Trades(l.ctx, l.svcCtx.ArenaId(l.ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	return ingestResponse(events.TypeTrades, len(req), res), nil
+}
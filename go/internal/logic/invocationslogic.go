@@ -0,0 +1,77 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/metrics"
+	"nof0-api/internal/snapshot"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type InvocationsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewInvocationsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *InvocationsLogic {
+	return &InvocationsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Invocations aggregates model invocations (count, errors, tokens, cost,
+// latency, gaps between calls) per model per UTC day.
+func (l *InvocationsLogic) Invocations(req *types.InvocationsRequest) (resp *types.InvocationsResponse, err error) {
+	var from, to int64
+	if req.From > 0 {
+		from = data.ToMillis(float64(req.From))
+	}
+	if req.To > 0 {
+		to = data.ToMillis(float64(req.To))
+	}
+
+	days, err := l.daily(req.ModelId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	models := joinModels(l.ctx, l.svcCtx)
+	for i := range days {
+		if days[i].Provider == "" {
+			days[i].Provider = models[days[i].ModelId].Provider
+		}
+	}
+	return &types.InvocationsResponse{
+		Days:       days,
+		ServerTime: time.Now().UnixMilli(),
+		Models:     models,
+	}, nil
+}
+
+func (l *InvocationsLogic) daily(modelId string, from, to int64) ([]types.InvocationDailyStats, error) {
+	// Postgres holds live data only; snapshots are read from their files.
+	if l.svcCtx.Invocations != nil && snapshot.FromContext(l.ctx) == "" {
+		days, err := l.svcCtx.Invocations.Daily(l.ctx, l.svcCtx.ArenaId(l.ctx), modelId, from, to)
+		if err == nil {
+			return days, nil
+		}
+		l.Errorf("db invocations failed, falling back: %v", err)
+		metrics.DBFallbacks.Inc("invocations")
+	}
+
+	invocations, err := l.svcCtx.Loader(l.ctx).LoadInvocations()
+	if err != nil {
+		return nil, err
+	}
+	return data.AggregateInvocations(invocations, modelId, from, to), nil
+}
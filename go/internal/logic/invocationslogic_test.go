@@ -0,0 +1,39 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/types"
+)
+
+func TestInvocations(t *testing.T) {
+	svcCtx := createTestServiceContext(t)
+	logic := NewInvocationsLogic(context.Background(), svcCtx)
+
+	resp, err := logic.Invocations(&types.InvocationsRequest{})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Days)
+	assert.NotZero(t, resp.ServerTime)
+	for _, d := range resp.Days {
+		assert.NotEmpty(t, d.Day)
+		assert.Greater(t, d.Invocations, 0)
+		assert.True(t, d.Estimated, "Fixture invocations are derived from conversations")
+	}
+
+	resp, err = logic.Invocations(&types.InvocationsRequest{ModelId: "gpt-5"})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Days)
+	for _, d := range resp.Days {
+		assert.Equal(t, "gpt-5", d.ModelId)
+	}
+
+	// from/to accept seconds like the source timestamps
+	resp, err = logic.Invocations(&types.InvocationsRequest{From: 1, To: 2})
+	require.NoError(t, err)
+	assert.NotNil(t, resp.Days)
+	assert.Empty(t, resp.Days)
+}
@@ -0,0 +1,36 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type JobsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewJobsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *JobsLogic {
+	return &JobsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Jobs reports the schedule and last run of every configured job.
+func (l *JobsLogic) Jobs() (resp *types.JobsResponse, err error) {
+	return &types.JobsResponse{
+		Jobs:       l.svcCtx.Jobs.Status(),
+		ServerTime: time.Now().UnixMilli(),
+	}, nil
+}
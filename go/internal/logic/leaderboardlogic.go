@@ -6,6 +6,7 @@ package logic
 import (
 	"context"
 
+	"nof0-api/internal/data"
 	"nof0-api/internal/svc"
 	"nof0-api/internal/types"
 
@@ -26,6 +27,26 @@ func NewLeaderboardLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Leade
 	}
 }
 
-func (l *LeaderboardLogic) Leaderboard() (resp *types.LeaderboardResponse, err error) {
-	return l.svcCtx.DataLoader.LoadLeaderboard()
+func (l *LeaderboardLogic) Leaderboard(req *types.LeaderboardRequest) (resp *types.LeaderboardResponse, err error) {
+	models := joinModels(l.ctx, l.svcCtx)
+	if req.AsOf > 0 {
+		asOf := data.ToMillis(float64(req.AsOf))
+		history, err := loadHistory(l.ctx, l.svcCtx, models, asOf)
+		if err != nil {
+			return nil, err
+		}
+		return &types.LeaderboardResponse{
+			Leaderboard: history.Leaderboard(asOf),
+			AsOf:        asOf,
+			Models:      models,
+		}, nil
+	}
+
+	resp, err = l.svcCtx.Source(l.ctx).LoadLeaderboard()
+	if err != nil {
+		return nil, err
+	}
+	resp.Models = models
+	resp.Stale = l.svcCtx.Stale(data.ResourceLeaderboard, resp.DataAsOf)
+	return resp, nil
 }
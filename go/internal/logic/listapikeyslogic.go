@@ -0,0 +1,46 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/errs"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListApiKeysLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListApiKeysLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListApiKeysLogic {
+	return &ListApiKeysLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+var errApiKeysUnavailable = errs.Unavailable("API keys are managed in Postgres (Postgres.DSN); use Auth.Keys without it")
+
+// ListApiKeys lists the keys stored in Postgres; config keys are not listed.
+func (l *ListApiKeysLogic) ListApiKeys() (resp *types.ApiKeysResponse, err error) {
+	if l.svcCtx.ApiKeys == nil {
+		return nil, errApiKeysUnavailable
+	}
+	keys, err := l.svcCtx.ApiKeys.List(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ApiKeysResponse{
+		Keys:       keys,
+		ServerTime: time.Now().UnixMilli(),
+	}, nil
+}
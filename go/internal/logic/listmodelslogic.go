@@ -0,0 +1,54 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/registry"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type ListModelsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewListModelsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ListModelsLogic {
+	return &ListModelsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ListModelsLogic) ListModels() (resp *types.ModelsResponse, err error) {
+	models, err := l.svcCtx.ModelRegistry.List(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ModelsResponse{
+		Models:     models,
+		ServerTime: time.Now().UnixMilli(),
+	}, nil
+}
+
+// joinModels returns registry attributes keyed by model id for embedding in
+// other responses. Registry failures are logged and never fail the caller.
+func joinModels(ctx context.Context, svcCtx *svc.ServiceContext) map[string]types.ModelInfo {
+	if svcCtx.ModelRegistry == nil {
+		return nil
+	}
+	models, err := registry.Lookup(ctx, svcCtx.ModelRegistry)
+	if err != nil {
+		logx.WithContext(ctx).Errorf("join model registry: %v", err)
+		return nil
+	}
+	return models
+}
@@ -6,6 +6,7 @@ package logic
 import (
 	"context"
 
+	"nof0-api/internal/data"
 	"nof0-api/internal/svc"
 	"nof0-api/internal/types"
 
@@ -27,5 +28,11 @@ func NewModelAnalyticsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Mo
 }
 
 func (l *ModelAnalyticsLogic) ModelAnalytics(modelId string) (resp *types.ModelAnalyticsResponse, err error) {
-	return l.svcCtx.DataLoader.LoadModelAnalytics(modelId)
+	resp, err = l.svcCtx.Source(l.ctx).LoadModelAnalytics(modelId)
+	if err != nil {
+		return nil, err
+	}
+	resp.Models = joinModels(l.ctx, l.svcCtx)
+	resp.Stale = l.svcCtx.Stale(data.ResourceAnalytics, resp.DataAsOf)
+	return resp, nil
 }
@@ -0,0 +1,248 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"nof0-api/internal/errs"
+	"nof0-api/internal/registry"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/threading"
+)
+
+// Section names used as keys in ModelDetailResponse.Errors.
+const (
+	sectionModel          = "model"
+	sectionAccountTotal   = "accountTotal"
+	sectionPositions      = "positions"
+	sectionTrades         = "trades"
+	sectionAnalytics      = "analytics"
+	sectionSinceInception = "sinceInception"
+	sectionLeaderboard    = "leaderboard"
+	sectionConversation   = "conversation"
+
+	numModelDetailSections = 8
+)
+
+type ModelDetailLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+
+	mu   sync.Mutex
+	resp *types.ModelDetailResponse
+}
+
+func NewModelDetailLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ModelDetailLogic {
+	return &ModelDetailLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ModelDetail assembles everything known about one model. Sections are loaded
+// concurrently; a failing section is reported in Errors and left empty rather
+// than failing the whole response.
+func (l *ModelDetailLogic) ModelDetail(req *types.ModelDetailRequest) (resp *types.ModelDetailResponse, err error) {
+	if req.ModelId == "" {
+		return nil, errs.InvalidArgument("modelId required")
+	}
+
+	l.resp = &types.ModelDetailResponse{
+		ModelId:        req.ModelId,
+		Positions:      map[string]types.Position{},
+		Trades:         []types.Trade{},
+		SinceInception: []types.SinceInceptionValue{},
+		Errors:         map[string]string{},
+	}
+
+	group := threading.NewRoutineGroup()
+	l.run(group, sectionModel, l.loadModel)
+	l.run(group, sectionAccountTotal, l.loadAccountTotal)
+	l.run(group, sectionPositions, l.loadPositions)
+	l.run(group, sectionTrades, func() error { return l.loadTrades(req.Trades) })
+	l.run(group, sectionAnalytics, l.loadAnalytics)
+	l.run(group, sectionSinceInception, l.loadSinceInception)
+	l.run(group, sectionLeaderboard, l.loadLeaderboard)
+	l.run(group, sectionConversation, l.loadConversation)
+	group.Wait()
+
+	resp = l.resp
+	if len(resp.Errors) == numModelDetailSections {
+		return nil, errs.Unavailable("model %s: no section could be loaded", req.ModelId)
+	}
+	if !l.known() {
+		return nil, errs.NotFound("model %s not found", req.ModelId)
+	}
+	resp.ServerTime = time.Now().UnixMilli()
+	return resp, nil
+}
+
+func (l *ModelDetailLogic) run(group *threading.RoutineGroup, section string, fn func() error) {
+	group.RunSafe(func() {
+		if err := fn(); err != nil {
+			l.Errorf("model detail %s for %s: %v", section, l.resp.ModelId, err)
+			l.mu.Lock()
+			l.resp.Errors[section] = errs.Message(err)
+			l.mu.Unlock()
+		}
+	})
+}
+
+// known reports whether any section found data for the model.
+func (l *ModelDetailLogic) known() bool {
+	r := l.resp
+	return r.Model != nil || r.AccountTotal != nil || len(r.Positions) > 0 || len(r.Trades) > 0 ||
+		r.Analytics != nil || len(r.SinceInception) > 0 || r.Leaderboard != nil || r.Conversation != nil
+}
+
+func (l *ModelDetailLogic) loadModel() error {
+	m, err := l.svcCtx.ModelRegistry.Get(l.ctx, l.resp.ModelId)
+	if errors.Is(err, registry.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	l.resp.Model = m
+	return nil
+}
+
+func (l *ModelDetailLogic) loadAccountTotal() error {
+	totals, err := l.svcCtx.Source(l.ctx).LoadAccountTotals()
+	if err != nil {
+		return err
+	}
+	var latest *types.AccountTotal
+	for i := range totals.AccountTotals {
+		t := &totals.AccountTotals[i]
+		if t.ModelId == l.resp.ModelId && (latest == nil || t.Timestamp > latest.Timestamp) {
+			latest = t
+		}
+	}
+	l.resp.AccountTotal = latest
+	return nil
+}
+
+func (l *ModelDetailLogic) loadPositions() error {
+	positions, err := l.svcCtx.Source(l.ctx).LoadPositions()
+	if err != nil {
+		return err
+	}
+	for _, pm := range positions.AccountTotals {
+		if pm.ModelId == l.resp.ModelId && pm.Positions != nil {
+			l.resp.Positions = pm.Positions
+			break
+		}
+	}
+	return nil
+}
+
+// loadTrades keeps the model's most recent limit trades, newest exit first.
+func (l *ModelDetailLogic) loadTrades(limit int) error {
+	trades, err := l.svcCtx.Source(l.ctx).LoadTrades()
+	if err != nil {
+		return err
+	}
+	out := []types.Trade{}
+	for _, t := range trades.Trades {
+		if t.ModelId == l.resp.ModelId {
+			out = append(out, t)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].ExitTime > out[j].ExitTime })
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	l.resp.Trades = out
+	return nil
+}
+
+func (l *ModelDetailLogic) loadAnalytics() error {
+	analytics, err := l.svcCtx.Source(l.ctx).LoadModelAnalytics(l.resp.ModelId)
+	if errs.Is(err, errs.KindNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	l.resp.Analytics = &analytics.Analytics
+	return nil
+}
+
+func (l *ModelDetailLogic) loadSinceInception() error {
+	values, err := l.svcCtx.Source(l.ctx).LoadSinceInception()
+	if err != nil {
+		return err
+	}
+	out := []types.SinceInceptionValue{}
+	for _, v := range values.SinceInceptionValues {
+		if v.ModelId == l.resp.ModelId {
+			out = append(out, v)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].InceptionDate < out[j].InceptionDate })
+	l.resp.SinceInception = out
+	return nil
+}
+
+// loadLeaderboard finds the model's entry and its rank by equity, highest first.
+func (l *ModelDetailLogic) loadLeaderboard() error {
+	board, err := l.svcCtx.Source(l.ctx).LoadLeaderboard()
+	if err != nil {
+		return err
+	}
+	entries := make([]types.LeaderboardEntry, len(board.Leaderboard))
+	copy(entries, board.Leaderboard)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Equity > entries[j].Equity })
+	for i := range entries {
+		if entries[i].Id == l.resp.ModelId {
+			l.resp.Leaderboard = &entries[i]
+			l.resp.Rank = i + 1
+			break
+		}
+	}
+	return nil
+}
+
+// loadConversation picks the model's conversation with the newest message.
+func (l *ModelDetailLogic) loadConversation() error {
+	convos, err := l.svcCtx.Source(l.ctx).LoadConversations()
+	if err != nil {
+		return err
+	}
+	var latest *types.Conversation
+	var latestTs float64
+	for i := range convos.Conversations {
+		c := &convos.Conversations[i]
+		if c.ModelId != l.resp.ModelId {
+			continue
+		}
+		ts := lastMessageTimestamp(c)
+		if latest == nil || ts > latestTs {
+			latest, latestTs = c, ts
+		}
+	}
+	l.resp.Conversation = latest
+	return nil
+}
+
+func lastMessageTimestamp(c *types.Conversation) float64 {
+	var last float64
+	for _, m := range c.Messages {
+		if ts, ok := m.Timestamp.(float64); ok && ts > last {
+			last = ts
+		}
+	}
+	return last
+}
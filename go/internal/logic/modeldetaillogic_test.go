@@ -0,0 +1,83 @@
+package logic
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/config"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+)
+
+func TestModelDetail(t *testing.T) {
+	svcCtx := createTestServiceContext(t)
+	logic := NewModelDetailLogic(context.Background(), svcCtx)
+
+	resp, err := logic.ModelDetail(&types.ModelDetailRequest{ModelId: "gpt-5", Trades: 5})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	assert.Equal(t, "gpt-5", resp.ModelId)
+	assert.NotZero(t, resp.ServerTime)
+	assert.Greater(t, len(resp.Positions), 0, "gpt-5 should have open positions")
+	assert.LessOrEqual(t, len(resp.Trades), 5, "Trades should honour the limit")
+	for i, trade := range resp.Trades {
+		assert.Equal(t, "gpt-5", trade.ModelId)
+		if i > 0 {
+			assert.GreaterOrEqual(t, resp.Trades[i-1].ExitTime, trade.ExitTime, "Trades should be newest first")
+		}
+	}
+	require.NotNil(t, resp.Analytics)
+	assert.Equal(t, "gpt-5", resp.Analytics.ModelId)
+	require.NotNil(t, resp.Leaderboard)
+	assert.Equal(t, "gpt-5", resp.Leaderboard.Id)
+	assert.GreaterOrEqual(t, resp.Rank, 1)
+	require.NotNil(t, resp.Conversation)
+	assert.Equal(t, "gpt-5", resp.Conversation.ModelId)
+	for _, v := range resp.SinceInception {
+		assert.Equal(t, "gpt-5", v.ModelId)
+	}
+}
+
+func TestModelDetailPartialFailure(t *testing.T) {
+	// Only the leaderboard is available; every other section should report an error.
+	dir := t.TempDir()
+	board := `{"leaderboard":[{"id":"gpt-5","equity":3000},{"id":"qwen3-max","equity":10000}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "leaderboard.json"), []byte(board), 0o644))
+
+	cfg := config.Config{}
+	cfg.DataPath = dir
+	logic := NewModelDetailLogic(context.Background(), svc.NewServiceContext(cfg))
+
+	resp, err := logic.ModelDetail(&types.ModelDetailRequest{ModelId: "gpt-5"})
+	require.NoError(t, err, "Section failures should not fail the response")
+	assert.Equal(t, 2, resp.Rank)
+	assert.NotContains(t, resp.Errors, sectionLeaderboard)
+	for _, section := range []string{sectionAccountTotal, sectionPositions, sectionTrades, sectionConversation} {
+		assert.Contains(t, resp.Errors, section)
+	}
+	assert.Empty(t, resp.Trades)
+
+	_, err = NewModelDetailLogic(context.Background(), svc.NewServiceContext(config.Config{DataPath: t.TempDir()})).
+		ModelDetail(&types.ModelDetailRequest{ModelId: "gpt-5"})
+	assert.Error(t, err, "All sections failing should fail the response")
+}
+
+func TestModelDetailUnknownModel(t *testing.T) {
+	svcCtx := createTestServiceContext(t)
+	logic := NewModelDetailLogic(context.Background(), svcCtx)
+
+	resp, err := logic.ModelDetail(&types.ModelDetailRequest{ModelId: "no-such-model"})
+	assert.True(t, errs.Is(err, errs.KindNotFound))
+	assert.Nil(t, resp)
+
+	analytics, err := NewModelAnalyticsLogic(context.Background(), svcCtx).ModelAnalytics("no-such-model")
+	assert.True(t, errs.Is(err, errs.KindNotFound))
+	assert.Nil(t, analytics)
+}
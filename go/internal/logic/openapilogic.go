@@ -0,0 +1,33 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+
+	"nof0-api/internal/openapi"
+	"nof0-api/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type OpenApiLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewOpenApiLogic(ctx context.Context, svcCtx *svc.ServiceContext) *OpenApiLogic {
+	return &OpenApiLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// OpenApi returns the OpenAPI 3 document generated from the route table and
+// the response types.
+func (l *OpenApiLogic) OpenApi() (resp *openapi.Document, err error) {
+	return openapi.Spec(), nil
+}
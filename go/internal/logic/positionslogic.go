@@ -5,7 +5,10 @@ package logic
 
 import (
 	"context"
+	"time"
 
+	"nof0-api/internal/data"
+	"nof0-api/internal/registry"
 	"nof0-api/internal/svc"
 	"nof0-api/internal/types"
 
@@ -27,5 +30,49 @@ func NewPositionsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Positio
 }
 
 func (l *PositionsLogic) Positions(req *types.PositionsRequest) (resp *types.PositionsResponse, err error) {
-	return l.svcCtx.DataLoader.LoadPositions()
+	models := joinModels(l.ctx, l.svcCtx)
+	if req.AsOf > 0 {
+		asOf := data.ToMillis(float64(req.AsOf))
+		history, err := loadHistory(l.ctx, l.svcCtx, models, asOf)
+		if err != nil {
+			return nil, err
+		}
+		return &types.PositionsResponse{
+			AccountTotals: history.Positions(asOf),
+			ServerTime:    time.Now().UnixMilli(),
+			AsOf:          asOf,
+			Models:        models,
+		}, nil
+	}
+
+	resp, err = l.svcCtx.Source(l.ctx).LoadPositions()
+	if err != nil {
+		return nil, err
+	}
+	data.ApplyPositionLinks(resp.AccountTotals, conversationLinks(l.ctx, l.svcCtx))
+	resp.Models = models
+	resp.Stale = l.svcCtx.Stale(data.ResourcePositions, resp.DataAsOf)
+	return resp, nil
+}
+
+// loadHistory prepares point-in-time reconstruction at asOf (ms) for the
+// request's arena, using registry starting capital where known.
+func loadHistory(ctx context.Context, svcCtx *svc.ServiceContext, models map[string]types.ModelInfo, asOf int64) (*data.History, error) {
+	history, err := data.NewHistory(svcCtx.Source(ctx), asOf)
+	if err != nil {
+		return nil, err
+	}
+	history.StartingCapital = startingCapital(models)
+	return history, nil
+}
+
+// startingCapital returns each model's registry starting capital, or
+// registry.DefaultStartingCapital for models without one.
+func startingCapital(models map[string]types.ModelInfo) func(modelId string) float64 {
+	return func(modelId string) float64 {
+		if c := models[modelId].StartingCapital; c > 0 {
+			return c
+		}
+		return registry.DefaultStartingCapital
+	}
 }
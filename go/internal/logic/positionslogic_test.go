@@ -135,3 +135,22 @@ func BenchmarkPositions(b *testing.B) {
 	}
 	_ = cfg
 }
+
+func TestPositionsAsOf(t *testing.T) {
+	svcCtx := createTestServiceContext(t)
+	logic := NewPositionsLogic(context.Background(), svcCtx)
+
+	// 2025-10-24 12:00 UTC, given in seconds
+	resp, err := logic.Positions(&types.PositionsRequest{AsOf: 1761307200})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1761307200000), resp.AsOf, "as_of should be normalized to ms")
+	assert.NotZero(t, resp.ServerTime)
+	require.NotEmpty(t, resp.AccountTotals)
+
+	for _, pm := range resp.AccountTotals {
+		for symbol, p := range pm.Positions {
+			assert.Equal(t, symbol, p.Symbol)
+			assert.LessOrEqual(t, p.EntryTime, 1761307200.0, "Positions must be entered by as_of")
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/auth"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type RevokeApiKeyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewRevokeApiKeyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *RevokeApiKeyLogic {
+	return &RevokeApiKeyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// RevokeApiKey disables a key; requests using it are rejected from now on.
+func (l *RevokeApiKeyLogic) RevokeApiKey(req *types.RevokeApiKeyRequest) (resp *types.ApiKeyResponse, err error) {
+	if l.svcCtx.ApiKeys == nil {
+		return nil, errApiKeysUnavailable
+	}
+	key, err := l.svcCtx.ApiKeys.Revoke(l.ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := auth.FromContext(l.ctx); ok {
+		l.Infof("api key %s (%s) revoked by %s", key.Id, key.Name, p.Name)
+	}
+	return &types.ApiKeyResponse{
+		Key:        *key,
+		ServerTime: time.Now().UnixMilli(),
+	}, nil
+}
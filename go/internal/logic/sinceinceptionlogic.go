@@ -6,6 +6,7 @@ package logic
 import (
 	"context"
 
+	"nof0-api/internal/data"
 	"nof0-api/internal/svc"
 	"nof0-api/internal/types"
 
@@ -27,5 +28,11 @@ func NewSinceInceptionLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Si
 }
 
 func (l *SinceInceptionLogic) SinceInception() (resp *types.SinceInceptionResponse, err error) {
-	return l.svcCtx.DataLoader.LoadSinceInception()
+	resp, err = l.svcCtx.Source(l.ctx).LoadSinceInception()
+	if err != nil {
+		return nil, err
+	}
+	resp.Models = joinModels(l.ctx, l.svcCtx)
+	resp.Stale = l.svcCtx.Stale(data.ResourceSinceInception, resp.DataAsOf)
+	return resp, nil
 }
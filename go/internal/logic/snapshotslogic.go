@@ -0,0 +1,46 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/errs"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+var errSnapshotsDisabled = errs.Unavailable("snapshots are not recorded (set Snapshots.Path)")
+
+type SnapshotsLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewSnapshotsLogic(ctx context.Context, svcCtx *svc.ServiceContext) *SnapshotsLogic {
+	return &SnapshotsLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Snapshots lists the recorded snapshots of the selected arena, newest first.
+func (l *SnapshotsLogic) Snapshots() (resp *types.SnapshotsResponse, err error) {
+	if l.svcCtx.Snapshots == nil {
+		return nil, errSnapshotsDisabled
+	}
+	list, err := l.svcCtx.Snapshots.List(l.svcCtx.ArenaId(l.ctx))
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []types.SnapshotInfo{}
+	}
+	return &types.SnapshotsResponse{Snapshots: list, ServerTime: time.Now().UnixMilli()}, nil
+}
@@ -0,0 +1,107 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type StatusLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewStatusLogic(ctx context.Context, svcCtx *svc.ServiceContext) *StatusLogic {
+	return &StatusLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Status reports how old the selected arena's prices, positions, trades,
+// analytics and conversations are.
+func (l *StatusLogic) Status() (resp *types.StatusResponse, err error) {
+	dl := l.svcCtx.Loader(l.ctx)
+	checks := []struct {
+		resource string
+		dataAsOf func() (int64, error)
+	}{
+		{data.ResourcePrices, func() (int64, error) {
+			r, err := dl.LoadCryptoPrices()
+			if err != nil {
+				return 0, err
+			}
+			return r.DataAsOf, nil
+		}},
+		{data.ResourcePositions, func() (int64, error) {
+			r, err := dl.LoadPositions()
+			if err != nil {
+				return 0, err
+			}
+			return r.DataAsOf, nil
+		}},
+		{data.ResourceTrades, func() (int64, error) {
+			r, err := dl.LoadTrades()
+			if err != nil {
+				return 0, err
+			}
+			return r.DataAsOf, nil
+		}},
+		{data.ResourceAnalytics, func() (int64, error) {
+			r, err := dl.LoadAnalytics()
+			if err != nil {
+				return 0, err
+			}
+			return r.DataAsOf, nil
+		}},
+		{data.ResourceConversations, func() (int64, error) {
+			r, err := dl.LoadConversations()
+			if err != nil {
+				return 0, err
+			}
+			return r.DataAsOf, nil
+		}},
+	}
+
+	now := time.Now()
+	resp = &types.StatusResponse{
+		Arena:      l.svcCtx.ArenaId(l.ctx),
+		Resources:  make([]types.ResourceFreshness, 0, len(checks)),
+		ServerTime: now.UnixMilli(),
+	}
+	for _, c := range checks {
+		f := types.ResourceFreshness{
+			Resource:      c.resource,
+			MaxAgeSeconds: int64(l.svcCtx.FreshnessLimit(c.resource) / time.Second),
+		}
+		asOf, err := c.dataAsOf()
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			f.Error = "no data"
+		case err != nil:
+			l.Errorf("status %s: %v", c.resource, err)
+			f.Error = "load failed"
+		default:
+			f.DataAsOf = asOf
+			f.Stale = l.svcCtx.Stale(c.resource, asOf)
+			if asOf > 0 {
+				f.AgeSeconds = int64(now.Sub(time.UnixMilli(asOf)) / time.Second)
+			}
+		}
+		resp.Stale = resp.Stale || f.Stale || f.Error != ""
+		resp.Resources = append(resp.Resources, f)
+	}
+	return resp, nil
+}
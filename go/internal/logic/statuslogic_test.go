@@ -0,0 +1,45 @@
+package logic
+
+import (
+	"context"
+	"testing"
+
+	"nof0-api/internal/config"
+	"nof0-api/internal/data"
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	cfg := config.Config{}
+	cfg.DataPath = "../../../mcp/data"
+	cfg.Freshness.Prices = 60 // the fixtures are far older
+	svcCtx := svc.NewServiceContext(cfg)
+
+	resp, err := NewStatusLogic(context.Background(), svcCtx).Status()
+	require.NoError(t, err)
+	assert.Equal(t, data.DefaultArena, resp.Arena)
+	assert.True(t, resp.Stale)
+	require.Len(t, resp.Resources, 5)
+
+	byName := map[string]int{}
+	for i, r := range resp.Resources {
+		byName[r.Resource] = i
+		assert.Empty(t, r.Error, r.Resource)
+		assert.NotZero(t, r.DataAsOf, r.Resource)
+	}
+	prices := resp.Resources[byName[data.ResourcePrices]]
+	assert.True(t, prices.Stale)
+	assert.Equal(t, int64(60), prices.MaxAgeSeconds)
+	assert.Greater(t, prices.AgeSeconds, int64(60))
+	// A zero threshold never flags staleness.
+	assert.False(t, resp.Resources[byName[data.ResourceTrades]].Stale)
+
+	pricesResp, err := NewCryptoPricesLogic(context.Background(), svcCtx).CryptoPrices(&types.CryptoPricesRequest{})
+	require.NoError(t, err)
+	assert.True(t, pricesResp.Stale)
+	assert.Equal(t, prices.DataAsOf, pricesResp.DataAsOf)
+}
@@ -6,6 +6,7 @@ package logic
 import (
 	"context"
 
+	"nof0-api/internal/data"
 	"nof0-api/internal/svc"
 	"nof0-api/internal/types"
 
@@ -27,5 +28,12 @@ func NewTradesLogic(ctx context.Context, svcCtx *svc.ServiceContext) *TradesLogi
 }
 
 func (l *TradesLogic) Trades() (resp *types.TradesResponse, err error) {
-	return l.svcCtx.DataLoader.LoadTrades()
+	resp, err = l.svcCtx.Source(l.ctx).LoadTrades()
+	if err != nil {
+		return nil, err
+	}
+	data.ApplyTradeLinks(resp.Trades, conversationLinks(l.ctx, l.svcCtx))
+	resp.Models = joinModels(l.ctx, l.svcCtx)
+	resp.Stale = l.svcCtx.Stale(data.ResourceTrades, resp.DataAsOf)
+	return resp, nil
 }
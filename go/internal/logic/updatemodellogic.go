@@ -0,0 +1,39 @@
+// Code scaffolded by goctl. Safe to edit.
+// goctl 1.9.2
+
+package logic
+
+import (
+	"context"
+	"time"
+
+	"nof0-api/internal/svc"
+	"nof0-api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+type UpdateModelLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewUpdateModelLogic(ctx context.Context, svcCtx *svc.ServiceContext) *UpdateModelLogic {
+	return &UpdateModelLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *UpdateModelLogic) UpdateModel(req *types.UpdateModelRequest) (resp *types.ModelResponse, err error) {
+	m, err := l.svcCtx.ModelRegistry.Update(l.ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ModelResponse{
+		Model:      *m,
+		ServerTime: time.Now().UnixMilli(),
+	}, nil
+}
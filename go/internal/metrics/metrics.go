@@ -0,0 +1,130 @@
+// Package metrics holds the service's Prometheus metrics. They are served,
+// next to go-zero's own, by the dev server's /metrics (DevServer in
+// etc/nof0.yaml) and are only recorded while it exports metrics.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/zeromicro/go-zero/core/metric"
+)
+
+const namespace = "nof0"
+
+// Sources passed to ObserveLoad.
+const (
+	SourceFile     = "file"
+	SourceDB       = "db"
+	SourceUpstream = "upstream"
+)
+
+// Cache results counted by CacheRequests.
+const (
+	CacheHit   = "hit"
+	CacheStale = "stale" // served while reloading in the background
+	CacheMiss  = "miss"
+)
+
+var (
+	// LoadDuration is the latency of DataSource calls by source and method.
+	LoadDuration = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: namespace,
+		Subsystem: "data",
+		Name:      "load_duration_ms",
+		Help:      "Data source load latency in milliseconds.",
+		Labels:    []string{"source", "method"},
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000},
+	})
+
+	// ParseDuration is the time spent decoding a data file.
+	ParseDuration = metric.NewHistogramVec(&metric.HistogramVecOpts{
+		Namespace: namespace,
+		Subsystem: "data",
+		Name:      "parse_duration_ms",
+		Help:      "JSON data file read and decode time in milliseconds.",
+		Labels:    []string{"file"},
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 25, 50, 100, 250, 500},
+	})
+
+	// CacheRequests counts read-through cache lookups by result; the hit
+	// ratio is (hit+stale)/all.
+	CacheRequests = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Redis read-through cache lookups by result (hit, stale, miss).",
+		Labels:    []string{"result"},
+	})
+
+	// DBFallbacks counts Postgres reads that failed and were served from the
+	// data files (or the in-memory index) instead.
+	DBFallbacks = metric.NewCounterVec(&metric.CounterVecOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "fallbacks_total",
+		Help:      "Failed Postgres reads served from the file fallback.",
+		Labels:    []string{"resource"},
+	})
+
+	// Subscribers is the number of connected event stream clients by
+	// transport.
+	Subscribers = metric.NewGaugeVec(&metric.GaugeVecOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "subscribers",
+		Help:      "Connected change event subscribers.",
+		Labels:    []string{"transport"},
+	})
+)
+
+// ObserveLoad records the latency of a load started at start; use it as
+// defer metrics.ObserveLoad(metrics.SourceFile, "trades", time.Now()).
+func ObserveLoad(source, method string, start time.Time) {
+	LoadDuration.ObserveFloat(float64(time.Since(start))/float64(time.Millisecond), source, method)
+}
+
+// FileTimes lists data files and their modification times by arena.
+type FileTimes func() map[string]map[string]time.Time
+
+// dataAge reports how long ago each data file was written, computed when
+// Prometheus scrapes rather than on a timer.
+type dataAge struct {
+	desc *prom.Desc
+
+	mu    sync.Mutex
+	files FileTimes
+}
+
+var age = &dataAge{desc: prom.NewDesc(prom.BuildFQName(namespace, "data", "file_age_seconds"),
+	"Seconds since a data file was last modified.", []string{"arena", "file"}, nil)}
+
+func init() {
+	prom.MustRegister(age)
+}
+
+// SetDataFiles sets where the file ages come from, replacing any earlier
+// source.
+func SetDataFiles(files FileTimes) {
+	age.mu.Lock()
+	age.files = files
+	age.mu.Unlock()
+}
+
+func (a *dataAge) Describe(ch chan<- *prom.Desc) { ch <- a.desc }
+
+func (a *dataAge) Collect(ch chan<- prom.Metric) {
+	a.mu.Lock()
+	files := a.files
+	a.mu.Unlock()
+	if files == nil {
+		return
+	}
+	now := time.Now()
+	for arena, byFile := range files() {
+		for file, mod := range byFile {
+			ch <- prom.MustNewConstMetric(a.desc, prom.GaugeValue, now.Sub(mod).Seconds(), arena, file)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/snapshot"
+)
+
+// ArenaMiddleware resolves the optional ?arena= and ?snapshot= query
+// parameters and stores them in the request context for logic to pick the
+// matching data source.
+type ArenaMiddleware struct {
+	arenas    *data.ArenaSet
+	snapshots *snapshot.Store // nil when snapshots are not recorded
+}
+
+func NewArenaMiddleware(arenas *data.ArenaSet, snapshots *snapshot.Store) *ArenaMiddleware {
+	return &ArenaMiddleware{arenas: arenas, snapshots: snapshots}
+}
+
+func (m *ArenaMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.URL.Query().Get("arena")
+		if id != "" {
+			if _, ok := m.arenas.Loader(id); !ok {
+				httpx.ErrorCtx(ctx, w, errs.NotFound("unknown arena %q", id))
+				return
+			}
+			ctx = data.WithArena(ctx, id)
+		}
+		if snap := r.URL.Query().Get("snapshot"); snap != "" {
+			if id == "" {
+				id = m.arenas.DefaultId()
+			}
+			if m.snapshots == nil {
+				httpx.ErrorCtx(ctx, w, errs.Unavailable("snapshots are not recorded"))
+				return
+			}
+			if _, err := m.snapshots.Loader(id, snap); err != nil {
+				httpx.ErrorCtx(ctx, w, errs.NotFound("unknown snapshot %q of arena %q", snap, id))
+				return
+			}
+			ctx = snapshot.WithId(ctx, snap)
+		}
+		next(w, r.WithContext(ctx))
+	}
+}
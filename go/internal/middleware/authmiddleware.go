@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+
+	"nof0-api/internal/auth"
+	"nof0-api/internal/errs"
+)
+
+// AuthMiddleware admits requests whose API key has at least role; see
+// auth.Token for where the key is read from.
+type AuthMiddleware struct {
+	auth *auth.Authenticator
+	role auth.Role
+}
+
+func NewAuthMiddleware(a *auth.Authenticator, role auth.Role) *AuthMiddleware {
+	return &AuthMiddleware{auth: a, role: role}
+}
+
+func (m *AuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, ok := auth.FromContext(r.Context())
+		err, failed := authError(r.Context())
+		if !ok && !failed {
+			// Not already tried by RateLimitMiddleware.
+			p, err = m.auth.Authenticate(r.Context(), auth.Token(r))
+		}
+		switch {
+		case errors.Is(err, auth.ErrUnauthorized):
+			w.Header().Set("WWW-Authenticate", `Bearer realm="nof0"`)
+			httpx.ErrorCtx(r.Context(), w, err)
+			return
+		case err != nil:
+			httpx.ErrorCtx(r.Context(), w, errs.Wrap(errs.KindUnavailable, err, "authentication unavailable"))
+			return
+		case !p.Role.Allows(m.role):
+			httpx.ErrorCtx(r.Context(), w, auth.ErrForbidden)
+			return
+		}
+		next(w, r.WithContext(auth.WithPrincipal(r.Context(), p)))
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/rest/httpx"
+
+	"nof0-api/internal/auth"
+	"nof0-api/internal/config"
+	"nof0-api/internal/errs"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	httpx.SetErrorHandlerCtx(errs.Handler)
+	a, err := auth.NewAuthenticator([]config.ApiKeyConf{
+		{Name: "runner", Role: "ingester", Key: "ingest-key"},
+		{Name: "ops", Role: "admin", Key: "admin-key"},
+	}, nil)
+	require.NoError(t, err)
+
+	var seen *auth.Principal
+	next := func(w http.ResponseWriter, r *http.Request) { seen, _ = auth.FromContext(r.Context()) }
+	handler := NewAuthMiddleware(a, auth.RoleIngester).Handle(next)
+
+	for _, tc := range []struct {
+		key  string
+		code int
+		body string
+	}{
+		{"", http.StatusUnauthorized, `{"error":{"code":"unauthenticated","message":"missing or invalid API key"}}`},
+		{"wrong", http.StatusUnauthorized, `{"error":{"code":"unauthenticated","message":"missing or invalid API key"}}`},
+		{"ingest-key", http.StatusOK, ""},
+		{"admin-key", http.StatusOK, ""},
+	} {
+		seen = nil
+		r := httptest.NewRequest(http.MethodPost, "/api/ingest/trades", nil)
+		if tc.key != "" {
+			r.Header.Set("Authorization", "Bearer "+tc.key)
+		}
+		w := httptest.NewRecorder()
+		handler(w, r)
+		assert.Equal(t, tc.code, w.Code, tc.key)
+		assert.Equal(t, tc.code == http.StatusOK, seen != nil, tc.key)
+		if tc.body != "" {
+			assert.JSONEq(t, tc.body, w.Body.String(), tc.key)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/admin/keys", nil)
+	r.Header.Set("X-Api-Key", "ingest-key")
+	NewAuthMiddleware(a, auth.RoleAdmin).Handle(next)(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"permission_denied","message":"API key lacks the required role"}}`, w.Body.String())
+}
@@ -0,0 +1,257 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/rest/httpx"
+
+	"nof0-api/internal/auth"
+	"nof0-api/internal/config"
+	"nof0-api/internal/errs"
+)
+
+var errRateLimited = errs.ResourceExhausted("rate limit exceeded")
+
+// idleSweep is how often buckets that have refilled completely are dropped;
+// a full bucket is the same as no bucket.
+const idleSweep = time.Minute
+
+// RateLimitMiddleware keeps a token bucket per client and route rule (see
+// config.RateLimitConf) and answers 429 with Retry-After when it is empty.
+// It runs before the route middlewares: a valid API key names the client and
+// is stored on the context for AuthMiddleware; any other request, including
+// one with an unknown key, counts against its IP. Keys other than config
+// keys are only looked up after a token was taken from the IP's bucket (and
+// given back when the key is valid), so made-up keys cannot query the key
+// store faster than the IP's rate.
+type RateLimitMiddleware struct {
+	auth     *auth.Authenticator
+	rules    []config.RateLimitRule // longest Path first
+	fallback config.RateLimitRule
+	proxies  []netip.Prefix
+	disabled bool
+	now      func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	rule   *config.RateLimitRule
+}
+
+func NewRateLimitMiddleware(c config.RateLimitConf, a *auth.Authenticator) (*RateLimitMiddleware, error) {
+	proxies := make([]netip.Prefix, 0, len(c.TrustedProxies))
+	for _, p := range c.TrustedProxies {
+		prefix, err := parsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit trusted proxy %q: %w", p, err)
+		}
+		proxies = append(proxies, prefix)
+	}
+
+	rules := make([]config.RateLimitRule, len(c.Routes))
+	copy(rules, c.Routes)
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].Path) > len(rules[j].Path) })
+	for i := range rules {
+		rules[i].Burst = burst(rules[i])
+	}
+	fallback := config.RateLimitRule{Rate: c.Rate, Burst: c.Burst}
+	fallback.Burst = burst(fallback)
+	return &RateLimitMiddleware{
+		auth:     a,
+		rules:    rules,
+		fallback: fallback,
+		proxies:  proxies,
+		disabled: c.Disabled,
+		now:      time.Now,
+		buckets:  map[string]*bucket{},
+	}, nil
+}
+
+// parsePrefix accepts a CIDR or a single address.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		return netip.ParsePrefix(s)
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func burst(r config.RateLimitRule) int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return int(math.Ceil(r.Rate))
+}
+
+func (m *RateLimitMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if m.disabled {
+			next(w, r)
+			return
+		}
+		rule := m.rule(r.URL.Path)
+		if rule.Rate <= 0 {
+			next(w, r)
+			return
+		}
+		token := auth.Token(r)
+		p, ok := m.auth.Configured(token)
+		if !ok {
+			ipKey := rule.Path + "|ip:" + m.clientIP(r)
+			if wait, ok := m.take(ipKey, rule); !ok {
+				tooManyRequests(w, r, wait)
+				return
+			}
+			if token == "" {
+				next(w, r)
+				return
+			}
+			var err error
+			if p, err = m.auth.Authenticate(r.Context(), token); err != nil {
+				// Charged to the IP; AuthMiddleware answers with this error
+				// instead of asking the store again.
+				next(w, r.WithContext(context.WithValue(r.Context(), authErrorKey{}, err)))
+				return
+			}
+			m.give(ipKey)
+		}
+		r = r.WithContext(auth.WithPrincipal(r.Context(), p))
+		client := "key:" + p.Name
+		if p.KeyId != "" {
+			client = "key:" + p.KeyId
+		}
+		if wait, ok := m.take(rule.Path+"|"+client, rule); !ok {
+			tooManyRequests(w, r, wait)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func tooManyRequests(w http.ResponseWriter, r *http.Request, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	httpx.ErrorCtx(r.Context(), w, errRateLimited)
+}
+
+type authErrorKey struct{}
+
+// authError returns the error of a key the rate limiter failed to
+// authenticate, if any.
+func authError(ctx context.Context) (error, bool) {
+	err, ok := ctx.Value(authErrorKey{}).(error)
+	return err, ok
+}
+
+func (m *RateLimitMiddleware) rule(path string) *config.RateLimitRule {
+	for i := range m.rules {
+		if strings.HasPrefix(path, m.rules[i].Path) {
+			return &m.rules[i]
+		}
+	}
+	return &m.fallback
+}
+
+// take spends a token from key's bucket, or reports how long until one is
+// available.
+func (m *RateLimitMiddleware) take(key string, rule *config.RateLimitRule) (time.Duration, bool) {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastSweep) >= idleSweep {
+		m.sweep(now)
+	}
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rule.Burst), last: now, rule: rule}
+		m.buckets[key] = b
+	}
+	b.refill(now)
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / rule.Rate * float64(time.Second)), false
+	}
+	b.tokens--
+	return 0, true
+}
+
+// give returns a token taken from key's bucket.
+func (m *RateLimitMiddleware) give(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if b, ok := m.buckets[key]; ok {
+		b.tokens = math.Min(float64(b.rule.Burst), b.tokens+1)
+	}
+}
+
+func (m *RateLimitMiddleware) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if b.refill(now); b.tokens >= float64(b.rule.Burst) {
+			delete(m.buckets, key)
+		}
+	}
+	m.lastSweep = now
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(b.rule.Burst), b.tokens+elapsed*b.rule.Rate)
+		b.last = now
+	}
+}
+
+// clientIP is the peer address without its port. When the peer is a trusted
+// proxy it is the last X-Forwarded-For address not itself a trusted proxy,
+// as only the proxies' own additions to the header can be believed.
+func (m *RateLimitMiddleware) clientIP(r *http.Request) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		peer = host
+	}
+	if !m.trusted(peer) {
+		return peer
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !m.trusted(hop) {
+			return hop
+		}
+		peer = hop
+	}
+	return peer
+}
+
+func (m *RateLimitMiddleware) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range m.proxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
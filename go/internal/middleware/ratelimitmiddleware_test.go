@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"github.com/zeromicro/go-zero/rest/httpx"
+
+	"nof0-api/internal/auth"
+	"nof0-api/internal/config"
+	"nof0-api/internal/errs"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	httpx.SetErrorHandlerCtx(errs.Handler)
+	a, err := auth.NewAuthenticator([]config.ApiKeyConf{{Name: "runner", Role: "ingester", Key: "ingest-key"}}, nil)
+	require.NoError(t, err)
+	m, err := NewRateLimitMiddleware(config.RateLimitConf{
+		Rate:  1,
+		Burst: 2,
+		Routes: []config.RateLimitRule{
+			{Path: "/api/ingest/", Rate: 0},
+			{Path: "/api/conversations", Rate: 10},
+			{Path: "/api/conversations/search", Rate: 0.5},
+		},
+	}, a)
+	require.NoError(t, err)
+	now := time.Unix(1760740000, 0)
+	m.now = func() time.Time { return now }
+
+	var seen *auth.Principal
+	handler := m.Handle(func(w http.ResponseWriter, r *http.Request) { seen, _ = auth.FromContext(r.Context()) })
+	get := func(path, ip, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = ip + ":40000"
+		if key != "" {
+			r.Header.Set("X-Api-Key", key)
+		}
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, get("/api/trades", "10.0.0.1", "").Code)
+	assert.Equal(t, http.StatusOK, get("/api/trades", "10.0.0.1", "").Code)
+	w := get("/api/trades", "10.0.0.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":{"code":"resource_exhausted","message":"rate limit exceeded"}}`, w.Body.String())
+	assert.Equal(t, http.StatusOK, get("/api/trades", "10.0.0.2", "").Code, "Buckets are per client")
+	assert.Equal(t, http.StatusOK, get("/api/trades", "10.0.0.1", "ingest-key").Code, "A valid key is its own client")
+	assert.Equal(t, "runner", seen.Name, "The principal is passed on to AuthMiddleware")
+	assert.Equal(t, http.StatusTooManyRequests, get("/api/trades", "10.0.0.1", "made-up").Code, "Unknown keys count against the IP")
+
+	// The longest prefix wins; Burst defaults to the rate rounded up.
+	assert.Equal(t, http.StatusOK, get("/api/conversations/search", "10.0.0.1", "").Code)
+	w = get("/api/conversations/search", "10.0.0.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, get("/api/conversations", "10.0.0.1", "").Code)
+	for range 5 {
+		assert.Equal(t, http.StatusOK, get("/api/ingest/trades", "10.0.0.1", "").Code, "Rate 0 is unlimited")
+	}
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, get("/api/trades", "10.0.0.1", "").Code, "Tokens refill over time")
+	assert.Equal(t, http.StatusTooManyRequests, get("/api/trades", "10.0.0.1", "").Code)
+
+	now = now.Add(idleSweep)
+	get("/api/trades", "10.0.0.3", "")
+	assert.Len(t, m.buckets, 1, "Refilled buckets are dropped")
+}
+
+// TestRateLimitKeyLookups checks that made-up keys are charged to the IP
+// before the store is asked, and asked only once per request.
+func TestRateLimitKeyLookups(t *testing.T) {
+	httpx.SetErrorHandlerCtx(errs.Handler)
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	a, err := auth.NewAuthenticator(nil, auth.NewKeyStore(sqlx.NewSqlConnFromDB(db)))
+	require.NoError(t, err)
+	m, err := NewRateLimitMiddleware(config.RateLimitConf{Rate: 1, Burst: 2}, a)
+	require.NoError(t, err)
+	now := time.Unix(1760740000, 0)
+	m.now = func() time.Time { return now }
+	handler := m.Handle(NewAuthMiddleware(a, auth.RoleAdmin).Handle(func(http.ResponseWriter, *http.Request) {}))
+
+	for range 2 {
+		mock.ExpectQuery("SELECT .* FROM api_keys").WithArgs("made").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	}
+	codes := []int{}
+	for range 3 {
+		r := httptest.NewRequest(http.MethodGet, "/api/admin/jobs", nil)
+		r.RemoteAddr = "203.0.113.7:40000"
+		r.Header.Set("X-Api-Key", "nof0_made_up")
+		w := httptest.NewRecorder()
+		handler(w, r)
+		codes = append(codes, w.Code)
+	}
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClientIP(t *testing.T) {
+	m, err := NewRateLimitMiddleware(config.RateLimitConf{TrustedProxies: []string{"10.0.0.0/24", "::1"}}, nil)
+	require.NoError(t, err)
+	get := func(peer string, fwd ...string) string {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = peer
+		for _, f := range fwd {
+			r.Header.Add("X-Forwarded-For", f)
+		}
+		return m.clientIP(r)
+	}
+
+	assert.Equal(t, "10.0.0.1", get("10.0.0.1:40000"))
+	assert.Equal(t, "203.0.113.7", get("10.0.0.1:40000", "203.0.113.7, 10.0.0.9"), "Trusted hops are skipped")
+	assert.Equal(t, "203.0.113.7", get("[::1]:40000", "198.51.100.1, 203.0.113.7"), "Only the proxy's own entry is believed")
+	assert.Equal(t, "203.0.113.7", get("10.0.0.1:40000", "198.51.100.1", "203.0.113.7"))
+	assert.Equal(t, "198.51.100.9", get("198.51.100.9:40000", "203.0.113.7"), "Untrusted peers cannot choose their address")
+
+	_, err = NewRateLimitMiddleware(config.RateLimitConf{TrustedProxies: []string{"proxy.local"}}, nil)
+	assert.Error(t, err)
+}
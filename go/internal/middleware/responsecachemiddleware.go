@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/collection"
+
+	"nof0-api/internal/config"
+)
+
+// ResponseCacheMiddleware serves GET responses from memory for a few seconds
+// and tags them with an ETag so clients can revalidate with If-None-Match.
+// Only 200 responses are cached, keyed by path and query.
+type ResponseCacheMiddleware struct {
+	cache *collection.Cache // nil when TTL is 0
+}
+
+type cachedResponse struct {
+	header http.Header
+	body   []byte
+	etag   string
+}
+
+func NewResponseCacheMiddleware(c config.ResponseCacheConf) (*ResponseCacheMiddleware, error) {
+	m := &ResponseCacheMiddleware{}
+	if c.TTL > 0 {
+		cache, err := collection.NewCache(time.Duration(c.TTL)*time.Second,
+			collection.WithLimit(c.MaxEntries), collection.WithName("response"))
+		if err != nil {
+			return nil, err
+		}
+		m.cache = cache
+	}
+	return m, nil
+}
+
+func (m *ResponseCacheMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next(w, r)
+			return
+		}
+		key := r.URL.RequestURI()
+		if m.cache != nil {
+			if v, ok := m.cache.Get(key); ok {
+				w.Header().Set("X-Cache", "HIT")
+				m.write(w, r, v.(*cachedResponse))
+				return
+			}
+		}
+
+		rec := &responseRecorder{header: http.Header{}, code: http.StatusOK}
+		next(rec, r)
+		if rec.code != http.StatusOK {
+			copyHeader(w.Header(), rec.header)
+			w.WriteHeader(rec.code)
+			w.Write(rec.body.Bytes())
+			return
+		}
+		sum := sha256.Sum256(rec.body.Bytes())
+		resp := &cachedResponse{header: rec.header, body: rec.body.Bytes(), etag: `"` + hex.EncodeToString(sum[:12]) + `"`}
+		if m.cache != nil {
+			m.cache.Set(key, resp)
+			w.Header().Set("X-Cache", "MISS")
+		}
+		m.write(w, r, resp)
+	}
+}
+
+func (m *ResponseCacheMiddleware) write(w http.ResponseWriter, r *http.Request, resp *cachedResponse) {
+	h := w.Header()
+	copyHeader(h, resp.header)
+	h.Set("ETag", resp.etag)
+	// Clients may keep the body but must revalidate before using it.
+	h.Set("Cache-Control", "no-cache")
+	if etagMatch(r.Header.Get("If-None-Match"), resp.etag) {
+		h.Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp.body)
+}
+
+// etagMatch implements the weak comparison If-None-Match uses.
+func etagMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func copyHeader(dst, src http.Header) {
+	for k, vs := range src {
+		dst[k] = append([]string(nil), vs...)
+	}
+}
+
+// responseRecorder buffers a handler's response so it can be hashed and
+// cached before anything is sent.
+type responseRecorder struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header { return r.header }
+
+func (r *responseRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+
+func (r *responseRecorder) WriteHeader(code int) { r.code = code }
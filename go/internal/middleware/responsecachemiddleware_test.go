@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/rest/httpx"
+
+	"nof0-api/internal/config"
+)
+
+func TestResponseCacheMiddleware(t *testing.T) {
+	m, err := NewResponseCacheMiddleware(config.ResponseCacheConf{TTL: 5, MaxEntries: 10})
+	require.NoError(t, err)
+	calls := 0
+	handler := m.Handle(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("fail") != "" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		httpx.OkJson(w, map[string]any{"arena": r.URL.Query().Get("arena")})
+	})
+	get := func(target, etag string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		if etag != "" {
+			r.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w
+	}
+
+	first := get("/api/trades?arena=s1", "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "MISS", first.Header().Get("X-Cache"))
+	assert.Equal(t, "application/json; charset=utf-8", first.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"arena":"s1"}`, first.Body.String())
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	second := get("/api/trades?arena=s1", "")
+	assert.Equal(t, "HIT", second.Header().Get("X-Cache"))
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, etag, second.Header().Get("ETag"))
+	assert.Equal(t, 1, calls)
+
+	notModified := get("/api/trades?arena=s1", `"other", W/`+etag)
+	assert.Equal(t, http.StatusNotModified, notModified.Code)
+	assert.Empty(t, notModified.Body.String())
+
+	assert.Equal(t, "MISS", get("/api/trades?arena=s2", "").Header().Get("X-Cache"), "The query is part of the key")
+	assert.Equal(t, http.StatusInternalServerError, get("/api/trades?fail=1", "").Code)
+	get("/api/trades?fail=1", "")
+	assert.Equal(t, 4, calls, "Errors are not cached")
+}
+
+func TestResponseCacheMiddlewareWithoutTTL(t *testing.T) {
+	m, err := NewResponseCacheMiddleware(config.ResponseCacheConf{})
+	require.NoError(t, err)
+	handler := m.Handle(func(w http.ResponseWriter, r *http.Request) { httpx.OkJson(w, []int{1}) })
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/api/arenas", nil))
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Empty(t, w.Header().Get("X-Cache"))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/arenas", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler(w, r)
+	assert.Equal(t, http.StatusNotModified, w.Code, "ETags work without the cache")
+}
@@ -0,0 +1,121 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // register pgx driver
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+const usage = `usage: nof0 migrate [-dsn DSN] <command>
+
+commands:
+  up [VERSION]   apply pending migrations (up to VERSION)
+  down [STEPS]   revert the last STEPS applied migrations (default 1)
+  redo           revert and re-apply the last applied migration
+  status         list migrations and whether they are applied
+`
+
+// Command runs the migrate subcommand with args (after "migrate"), writing
+// progress to w. dsn is the default for -dsn, normally Postgres.DSN.
+func Command(ctx context.Context, dsn string, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.Usage = func() { fmt.Fprint(w, usage) }
+	fs.StringVar(&dsn, "dsn", dsn, "Postgres DSN (defaults to Postgres.DSN of the config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("missing command")
+	}
+	if dsn == "" {
+		return errors.New("no Postgres DSN: set Postgres.DSN in the config or pass -dsn")
+	}
+	arg := func(def int64) (int64, error) {
+		if fs.NArg() < 2 {
+			return def, nil
+		}
+		return strconv.ParseInt(fs.Arg(1), 10, 64)
+	}
+
+	migrations, err := Embedded()
+	if err != nil {
+		return err
+	}
+	m := New(sqlx.NewSqlConn("pgx", dsn), migrations)
+
+	switch fs.Arg(0) {
+	case "up":
+		to, err := arg(0)
+		if err != nil {
+			return err
+		}
+		done, err := m.Up(ctx, to)
+		report(w, "applied", done, err)
+		return err
+	case "down":
+		steps, err := arg(1)
+		if err != nil {
+			return err
+		}
+		done, err := m.Down(ctx, int(steps))
+		report(w, "reverted", done, err)
+		return err
+	case "redo":
+		mg, err := m.Redo(ctx)
+		if mg != nil {
+			fmt.Fprintf(w, "redone %03d_%s\n", mg.Version, mg.Name)
+		}
+		return err
+	case "status":
+		st, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+		for _, s := range st {
+			at := "-"
+			if !s.AppliedAt.IsZero() {
+				at = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(tw, "%03d\t%s\t%s\t%s\n", s.Version, s.Name, s.State, at)
+		}
+		return tw.Flush()
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+}
+
+func report(w io.Writer, verb string, done []Migration, err error) {
+	if len(done) == 0 && err == nil {
+		fmt.Fprintln(w, "nothing to do")
+	}
+	for _, mg := range done {
+		fmt.Fprintf(w, "%s %03d_%s\n", verb, mg.Version, mg.Name)
+	}
+}
+
+// AutoUp applies every pending embedded migration; used on server start when
+// Postgres.AutoMigrate is set.
+func AutoUp(ctx context.Context, conn sqlx.SqlConn) error {
+	migrations, err := Embedded()
+	if err != nil {
+		return err
+	}
+	done, err := New(conn, migrations).Up(ctx, 0)
+	for _, mg := range done {
+		logx.Infof("migrate: applied %03d_%s", mg.Version, mg.Name)
+	}
+	return err
+}
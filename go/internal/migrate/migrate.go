@@ -0,0 +1,353 @@
+// Package migrate applies the versioned SQL migrations embedded from
+// migrations/ and records them in schema_migrations, replacing the external
+// golang-migrate CLI. Each migration is NNN_name.up.sql plus a
+// NNN_name.down.sql that reverts it.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/migrations"
+)
+
+// Migration states reported by Status.
+const (
+	StateApplied  = "applied"
+	StatePending  = "pending"
+	StateModified = "modified" // applied, but the up script changed since
+	StateMissing  = "missing"  // applied, but no longer embedded
+)
+
+// lockKey serializes migrators across processes (pg_advisory_xact_lock).
+const lockKey = 7_300_001
+
+var fileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// ErrModified is returned when an applied migration's script was edited.
+var ErrModified = errors.New("applied migrations were modified")
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // sha256 of Up
+}
+
+// File returns the file name of the up or down script.
+func (m Migration) File(direction string) string {
+	return fmt.Sprintf("%03d_%s.%s.sql", m.Version, m.Name, direction)
+}
+
+// MigrationStatus is the state of one migration in a database.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	State     string
+	AppliedAt time.Time
+}
+
+// Load reads the migrations in fsys, ordered by version. Every version needs
+// both an up and a down script; any other .sql file is an error.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+		m := fileRe.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("migration %s: name must be NNN_name.up.sql or NNN_name.down.sql", e.Name())
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		b, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mg
+		} else if mg.Name != m[2] {
+			return nil, fmt.Errorf("migration %s: version %d is already used by %s", e.Name(), version, mg.Name)
+		}
+		if m[3] == "up" {
+			mg.Up = string(b)
+			sum := sha256.Sum256(b)
+			mg.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			mg.Down = string(b)
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		switch {
+		case strings.TrimSpace(mg.Up) == "":
+			return nil, fmt.Errorf("migration %s: missing or empty", mg.File("up"))
+		case strings.TrimSpace(mg.Down) == "":
+			return nil, fmt.Errorf("migration %s: missing or empty", mg.File("down"))
+		}
+		out = append(out, *mg)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// Embedded returns the migrations compiled into the binary.
+func Embedded() ([]Migration, error) {
+	return Load(migrations.FS)
+}
+
+// Migrator applies migrations to one database.
+type Migrator struct {
+	conn       sqlx.SqlConn
+	migrations []Migration
+}
+
+func New(conn sqlx.SqlConn, migrations []Migration) *Migrator {
+	return &Migrator{conn: conn, migrations: migrations}
+}
+
+type appliedRow struct {
+	Version   int64     `db:"version"`
+	Name      string    `db:"name"`
+	Checksum  string    `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+// ensureTable creates schema_migrations, or upgrades the single-row table
+// left by golang-migrate (version, dirty) by recording every migration up to
+// its version as applied.
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	if _, err := m.conn.ExecCtx(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version bigint PRIMARY KEY)`); err != nil {
+		return err
+	}
+	if _, err := m.conn.ExecCtx(ctx, `ALTER TABLE schema_migrations
+        ADD COLUMN IF NOT EXISTS name       text NOT NULL DEFAULT '',
+        ADD COLUMN IF NOT EXISTS checksum   text NOT NULL DEFAULT '',
+        ADD COLUMN IF NOT EXISTS applied_at timestamptz NOT NULL DEFAULT now()`); err != nil {
+		return err
+	}
+
+	var legacy int
+	if err := m.conn.QueryRowCtx(ctx, &legacy, `SELECT count(*) FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'schema_migrations' AND column_name = 'dirty'`); err != nil {
+		return err
+	}
+	if legacy == 0 {
+		return nil
+	}
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, s sqlx.Session) error {
+		var rows []struct {
+			Version int64 `db:"version"`
+			Dirty   bool  `db:"dirty"`
+		}
+		if err := s.QueryRowsPartialCtx(ctx, &rows, `SELECT version, dirty FROM schema_migrations`); err != nil {
+			return err
+		}
+		var version int64
+		for _, r := range rows {
+			if r.Dirty {
+				return fmt.Errorf("golang-migrate left version %d dirty: fix the schema by hand, then clear the flag", r.Version)
+			}
+			version = max(version, r.Version)
+		}
+		if _, err := s.ExecCtx(ctx, `DELETE FROM schema_migrations`); err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			if mg.Version > version {
+				break
+			}
+			if err := record(ctx, s, mg); err != nil {
+				return err
+			}
+		}
+		_, err := s.ExecCtx(ctx, `ALTER TABLE schema_migrations DROP COLUMN dirty`)
+		return err
+	})
+}
+
+func record(ctx context.Context, s sqlx.Session, mg Migration) error {
+	_, err := s.ExecCtx(ctx, `INSERT INTO schema_migrations(version, name, checksum) VALUES ($1, $2, $3)`,
+		mg.Version, mg.Name, mg.Checksum)
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context) ([]appliedRow, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	var rows []appliedRow
+	err := m.conn.QueryRowsPartialCtx(ctx, &rows, `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`)
+	return rows, err
+}
+
+// Status lists every embedded migration and every applied one that is no
+// longer embedded, ordered by version.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	rows, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return m.status(rows), nil
+}
+
+func (m *Migrator) status(rows []appliedRow) []MigrationStatus {
+	done := map[int64]appliedRow{}
+	for _, r := range rows {
+		done[r.Version] = r
+	}
+	var out []MigrationStatus
+	for _, mg := range m.migrations {
+		st := MigrationStatus{Version: mg.Version, Name: mg.Name, State: StatePending}
+		if r, ok := done[mg.Version]; ok {
+			st.State, st.AppliedAt = StateApplied, r.AppliedAt
+			if r.Checksum != mg.Checksum {
+				st.State = StateModified
+			}
+			delete(done, mg.Version)
+		}
+		out = append(out, st)
+	}
+	for _, r := range done {
+		out = append(out, MigrationStatus{Version: r.Version, Name: r.Name, State: StateMissing, AppliedAt: r.AppliedAt})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
+
+// check refuses to run on top of migrations that were edited after being
+// applied: their down scripts may no longer match the schema.
+func (m *Migrator) check(ctx context.Context) ([]MigrationStatus, error) {
+	st, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var modified []string
+	for _, s := range st {
+		if s.State == StateModified {
+			modified = append(modified, fmt.Sprintf("%03d_%s", s.Version, s.Name))
+		}
+	}
+	if len(modified) > 0 {
+		return nil, fmt.Errorf("%w: %s (restore them and add a new migration instead)", ErrModified, strings.Join(modified, ", "))
+	}
+	return st, nil
+}
+
+// Up applies pending migrations in version order, up to and including to
+// (0 for all), and returns the ones applied. Each runs in its own
+// transaction, so a failure leaves the earlier ones applied.
+func (m *Migrator) Up(ctx context.Context, to int64) ([]Migration, error) {
+	st, err := m.check(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending := map[int64]bool{}
+	for _, s := range st {
+		pending[s.Version] = s.State == StatePending
+	}
+	var done []Migration
+	for _, mg := range m.migrations {
+		if to > 0 && mg.Version > to {
+			break
+		}
+		if !pending[mg.Version] {
+			continue
+		}
+		if err := m.run(ctx, mg, true); err != nil {
+			return done, err
+		}
+		done = append(done, mg)
+	}
+	return done, nil
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns
+// the ones reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	st, err := m.check(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]Migration{}
+	for _, mg := range m.migrations {
+		byVersion[mg.Version] = mg
+	}
+	var done []Migration
+	for i := len(st) - 1; i >= 0 && len(done) < steps; i-- {
+		switch st[i].State {
+		case StatePending:
+			continue
+		case StateMissing:
+			return done, fmt.Errorf("migration %03d_%s is applied but not embedded: cannot revert it", st[i].Version, st[i].Name)
+		}
+		mg := byVersion[st[i].Version]
+		if err := m.run(ctx, mg, false); err != nil {
+			return done, err
+		}
+		done = append(done, mg)
+	}
+	return done, nil
+}
+
+// Redo reverts the last applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	done, err := m.Down(ctx, 1)
+	if err != nil || len(done) == 0 {
+		return nil, err
+	}
+	if _, err := m.Up(ctx, done[0].Version); err != nil {
+		return nil, err
+	}
+	return &done[0], nil
+}
+
+func (m *Migrator) run(ctx context.Context, mg Migration, up bool) error {
+	return m.conn.TransactCtx(ctx, func(ctx context.Context, s sqlx.Session) error {
+		if _, err := s.ExecCtx(ctx, `SELECT pg_advisory_xact_lock($1)`, lockKey); err != nil {
+			return err
+		}
+		// Another migrator may have got here first while we waited.
+		var n int
+		if err := s.QueryRowCtx(ctx, &n, `SELECT count(*) FROM schema_migrations WHERE version = $1`, mg.Version); err != nil {
+			return err
+		}
+		if up == (n > 0) {
+			return nil
+		}
+
+		script, file := mg.Up, mg.File("up")
+		if !up {
+			script, file = mg.Down, mg.File("down")
+		}
+		if _, err := s.ExecCtx(ctx, script); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if !up {
+			_, err := s.ExecCtx(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mg.Version)
+			return err
+		}
+		return record(ctx, s, mg)
+	})
+}
@@ -0,0 +1,172 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+)
+
+func TestEmbedded(t *testing.T) {
+	migrations, err := Embedded()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	seen := map[string]bool{}
+	for i, m := range migrations {
+		if i > 0 {
+			assert.Greater(t, m.Version, migrations[i-1].Version)
+		}
+		assert.False(t, seen[m.Name], "Duplicate migration %s", m.Name)
+		seen[m.Name] = true
+		assert.Len(t, m.Checksum, 64)
+	}
+	assert.Equal(t, "domain", migrations[0].Name)
+}
+
+func TestLoadErrors(t *testing.T) {
+	file := func(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+	cases := map[string]fstest.MapFS{
+		"must be NNN_name": {"001_a.sql": file("SELECT 1")},
+		"already used":     {"001_a.up.sql": file("SELECT 1"), "001_a.down.sql": file("SELECT 1"), "001_b.up.sql": file("SELECT 1")},
+		"001_a.down.sql":   {"001_a.up.sql": file("SELECT 1")},
+		"missing or empty": {"001_a.up.sql": file(" \n"), "001_a.down.sql": file("SELECT 1")},
+	}
+	for want, fsys := range cases {
+		_, err := Load(fsys)
+		if assert.Error(t, err, want) {
+			assert.Contains(t, err.Error(), want)
+		}
+	}
+
+	migrations, err := Load(fstest.MapFS{
+		"002_b.up.sql": file("B"), "002_b.down.sql": file("-B"),
+		"001_a.up.sql": file("A"), "001_a.down.sql": file("-A"),
+		"README.md": file("ignored"),
+	})
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, Migration{Version: 1, Name: "a", Up: "A", Down: "-A", Checksum: migrations[0].Checksum}, migrations[0])
+	assert.Equal(t, "002_b.down.sql", migrations[1].File("down"))
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "a", Up: "CREATE TABLE a()", Down: "DROP TABLE a", Checksum: "ca"},
+	{Version: 2, Name: "b", Up: "CREATE TABLE b()", Down: "DROP TABLE b", Checksum: "cb"},
+	{Version: 3, Name: "c", Up: "CREATE TABLE c()", Down: "DROP TABLE c", Checksum: "cc"},
+}
+
+func expectTable(mock sqlmock.Sqlmock, legacy int) {
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ALTER TABLE schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("information_schema.columns").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(legacy))
+}
+
+func appliedRows(at time.Time, rows ...[2]interface{}) *sqlmock.Rows {
+	r := sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"})
+	for _, row := range rows {
+		v := row[0].(int64)
+		r.AddRow(v, testMigrations[v-1].Name, row[1], at)
+	}
+	return r
+}
+
+func TestUpAppliesPending(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	expectTable(mock, 0)
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(appliedRows(time.Now(), [2]interface{}{int64(1), "ca"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT count").WithArgs(int64(2)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec("CREATE TABLE b").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(2), "b", "cb").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	done, err := New(sqlx.NewSqlConnFromDB(db), testMigrations).Up(context.Background(), 2)
+	require.NoError(t, err)
+	require.Len(t, done, 1, "Version 3 is beyond the target")
+	assert.Equal(t, int64(2), done[0].Version)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStatusAndModified(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	at := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	m := New(sqlx.NewSqlConnFromDB(db), testMigrations[:2])
+
+	for i := 0; i < 2; i++ {
+		expectTable(mock, 0)
+		mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+			WillReturnRows(appliedRows(at, [2]interface{}{int64(1), "edited"}, [2]interface{}{int64(3), "cc"}))
+	}
+
+	st, err := m.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []MigrationStatus{
+		{Version: 1, Name: "a", State: StateModified, AppliedAt: at},
+		{Version: 2, Name: "b", State: StatePending},
+		{Version: 3, Name: "c", State: StateMissing, AppliedAt: at},
+	}, st)
+
+	_, err = m.Up(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrModified, "Nothing runs on top of an edited migration")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDownRevertsNewestFirst(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	expectTable(mock, 0)
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(appliedRows(time.Now(), [2]interface{}{int64(1), "ca"}, [2]interface{}{int64(2), "cb"}))
+	for _, v := range []int64{2, 1} {
+		mock.ExpectBegin()
+		mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT count").WithArgs(v).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectExec("DROP TABLE " + testMigrations[v-1].Name).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("DELETE FROM schema_migrations").WithArgs(v).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+
+	done, err := New(sqlx.NewSqlConnFromDB(db), testMigrations).Down(context.Background(), 5)
+	require.NoError(t, err)
+	require.Len(t, done, 2)
+	assert.Equal(t, []int64{2, 1}, []int64{done[0].Version, done[1].Version})
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdoptsGolangMigrateTable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	expectTable(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(int64(2), false))
+	mock.ExpectExec("DELETE FROM schema_migrations").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(1), "a", "ca").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(int64(2), "b", "cb").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DROP COLUMN dirty").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(appliedRows(time.Now(), [2]interface{}{int64(1), "ca"}, [2]interface{}{int64(2), "cb"}))
+
+	st, err := New(sqlx.NewSqlConnFromDB(db), testMigrations).Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{StateApplied, StateApplied, StatePending}, []string{st[0].State, st[1].State, st[2].State})
+	require.NoError(t, mock.ExpectationsWereMet())
+}
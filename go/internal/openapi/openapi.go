@@ -0,0 +1,217 @@
+// Package openapi generates the API's OpenAPI 3 document, served at
+// /api/openapi.json. Paths come from the route table in routes.go, which
+// mirrors handler.RegisterHandlers; schemas are derived by reflection from
+// the request and response types in internal/types, so the document
+// describes what the handlers actually encode.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"nof0-api/internal/types"
+)
+
+const (
+	Version = "3.0.3"
+	title   = "nof0 API"
+)
+
+// Document is the subset of an OpenAPI 3.0 document the generator emits.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower-case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationId string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"` // path or query
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// Schema is the subset of the OpenAPI schema object used for Go types.
+// AdditionalProperties is false for structs and a *Schema for maps.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+const (
+	mediaJSON  = "application/json"
+	mediaText  = "text/plain"
+	mediaEvent = "text/event-stream"
+)
+
+var (
+	once sync.Once
+	doc  *Document
+)
+
+// Spec returns the API document, generated on first use.
+func Spec() *Document {
+	once.Do(func() { doc = Generate() })
+	return doc
+}
+
+// Generate builds the document from the route table.
+func Generate() *Document {
+	g := &generator{schemas: map[string]*Schema{}}
+	d := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: "1.0.0",
+			Description: "Alpha Arena data API. Failed requests answer with an ErrorResponse whose code " +
+				"matches the status: not_found 404, invalid_argument 400, unauthenticated 401, permission_denied 403, " +
+				"resource_exhausted 429, unavailable 503, upstream 502, internal 500.",
+		},
+		Paths: map[string]PathItem{},
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearer": {Type: "http", Scheme: "bearer"},
+				"apiKey": {Type: "apiKey", In: "header", Name: "X-Api-Key"},
+			},
+		},
+	}
+	for _, grp := range groups {
+		for _, op := range grp.ops {
+			path := Path(grp.prefix + op.path)
+			if d.Paths[path] == nil {
+				d.Paths[path] = PathItem{}
+			}
+			d.Paths[path][strings.ToLower(op.method)] = g.operation(grp, op, path)
+		}
+	}
+	return d
+}
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// Path converts a go-zero route path (/models/:modelId) to an OpenAPI one
+// (/models/{modelId}).
+func Path(route string) string {
+	return pathParam.ReplaceAllString(route, "{$1}")
+}
+
+func (g *generator) operation(grp group, op operation, path string) *Operation {
+	o := &Operation{
+		OperationId: strings.TrimSuffix(op.handler, "Handler"),
+		Summary:     op.summary,
+		Tags:        []string{grp.tag},
+		Responses:   map[string]Response{},
+	}
+	if grp.role != "" {
+		o.Security = []map[string][]string{{"bearer": {}}, {"apiKey": {}}}
+		o.Summary += " (" + grp.role + " key)"
+	}
+
+	for _, m := range pathParam.FindAllStringSubmatch(op.path, -1) {
+		o.Parameters = append(o.Parameters, Parameter{Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	if op.request != nil {
+		o.Parameters = append(o.Parameters, g.queryParams(reflect.TypeOf(op.request))...)
+	}
+	if grp.arena {
+		o.Parameters = append(o.Parameters,
+			Parameter{Name: "arena", In: "query", Description: "arena id (see /api/arenas)", Schema: &Schema{Type: "string"}},
+			Parameter{Name: "snapshot", In: "query", Description: "snapshot id (see /api/snapshots)", Schema: &Schema{Type: "string"}})
+	}
+
+	switch {
+	case op.body != nil:
+		// One item or an array of them.
+		item := g.schema(reflect.TypeOf(op.body))
+		o.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{mediaJSON: {Schema: &Schema{
+			OneOf: []*Schema{item, {Type: "array", Items: item}},
+		}}}}
+	case op.request != nil && (op.method == http.MethodPost || op.method == http.MethodPatch):
+		o.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{mediaJSON: {Schema: g.schema(reflect.TypeOf(op.request))}}}
+	}
+
+	ok := Response{Description: "OK", Content: map[string]MediaType{}}
+	switch {
+	case op.stream:
+		ok.Content[mediaEvent] = MediaType{Schema: g.schema(reflect.TypeOf(op.response))}
+	case op.response != nil:
+		ok.Content[mediaJSON] = MediaType{Schema: g.schema(reflect.TypeOf(op.response))}
+	default:
+		ok.Content[mediaJSON] = MediaType{Schema: &Schema{Type: "object"}}
+	}
+	if op.text {
+		ok.Content[mediaText] = MediaType{Schema: &Schema{Type: "string"}}
+	}
+	o.Responses["200"] = ok
+	if op.failure != nil {
+		o.Responses["503"] = Response{Description: "A check failed", Content: map[string]MediaType{
+			mediaJSON: {Schema: g.schema(reflect.TypeOf(op.failure))},
+		}}
+	} else {
+		o.Responses["default"] = Response{Description: "Error", Content: map[string]MediaType{
+			mediaJSON: {Schema: g.schema(reflect.TypeOf(types.ErrorResponse{}))},
+		}}
+	}
+	return o
+}
@@ -0,0 +1,89 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	d := Generate()
+	assert.Equal(t, Version, d.OpenAPI)
+
+	ids := map[string]bool{}
+	for path, item := range d.Paths {
+		assert.NotContains(t, path, ":", "Paths use {param}")
+		for method, op := range item {
+			assert.False(t, ids[op.OperationId], "duplicate operationId %s", op.OperationId)
+			ids[op.OperationId] = true
+			assert.NotEmpty(t, op.Responses, "%s %s", method, path)
+		}
+	}
+
+	// Every $ref resolves.
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	for _, part := range strings.Split(string(b), `"$ref":"#/components/schemas/`)[1:] {
+		name := part[:strings.IndexByte(part, '"')]
+		assert.Contains(t, d.Components.Schemas, name)
+	}
+
+	trade := d.Components.Schemas["Trade"]
+	require.NotNil(t, trade)
+	assert.Contains(t, trade.Required, "id")
+	assert.NotContains(t, trade.Required, "conversation_id", "omitempty fields are optional")
+	assert.Equal(t, "number", trade.Properties["entry_time"].Type)
+	assert.Equal(t, false, trade.AdditionalProperties)
+
+	detail := d.Paths["/api/models/{modelId}"]["get"]
+	require.NotNil(t, detail)
+	names := []string{}
+	for _, p := range detail.Parameters {
+		names = append(names, p.In+":"+p.Name)
+	}
+	assert.Equal(t, []string{"path:modelId", "query:trades", "query:arena", "query:snapshot"}, names)
+	assert.Equal(t, int64(20), detail.Parameters[1].Schema.Default)
+
+	diff := d.Paths["/api/admin/diff"]["get"]
+	require.NotNil(t, diff)
+	assert.Contains(t, diff.Responses["200"].Content, mediaText)
+	assert.NotEmpty(t, diff.Security)
+}
+
+func TestValidateResponse(t *testing.T) {
+	d := Generate()
+	valid := `{"trades":[{"id":"t1","model_id":"gpt-5","symbol":"BTC","side":"long","trade_type":"long",
+		"trade_id":"x","quantity":1,"leverage":10,"confidence":0.5,"entry_price":1,"entry_time":1.5,
+		"entry_human_time":"","entry_sz":1,"entry_tid":1,"entry_oid":1,"entry_crossed":false,
+		"entry_liquidation":null,"entry_commission_dollars":0,"entry_closed_pnl":0,"exit_price":2,
+		"exit_time":2,"exit_human_time":"","exit_sz":1,"exit_tid":2,"exit_oid":2,"exit_crossed":true,
+		"exit_liquidation":null,"exit_commission_dollars":0,"exit_closed_pnl":1,"exit_plan":{},
+		"realized_gross_pnl":1,"realized_net_pnl":1,"total_commission_dollars":0}],
+		"serverTime":1,"stale":false}`
+	require.NoError(t, d.ValidateResponse("GET", "/api/trades", 200, []byte(valid)))
+	require.NoError(t, d.ValidateResponse("GET", "/api/trades", 200, []byte(`{"trades":null,"serverTime":1,"stale":false}`)))
+
+	tests := []struct {
+		name, route, body, err string
+		status                 int
+	}{
+		{"missing field", "/api/trades", strings.Replace(valid, `"symbol":"BTC",`, "", 1), `missing required "symbol"`, 200},
+		{"wrong type", "/api/trades", strings.Replace(valid, `"quantity":1`, `"quantity":"1"`, 1), "$.trades[0].quantity", 200},
+		{"integer", "/api/trades", strings.Replace(valid, `"entry_tid":1`, `"entry_tid":1.5`, 1), "not of type integer", 200},
+		{"undocumented", "/api/trades", strings.Replace(valid, `"serverTime":1`, `"serverTime":1,"pnl":3`, 1), "$.pnl: undocumented", 200},
+		{"error envelope", "/api/analytics/:modelId", `{"error":"not found"}`, "$.error", 404},
+		{"unknown route", "/api/nothing", `{}`, "not documented", 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := d.ValidateResponse("GET", tt.route, tt.status, []byte(tt.body))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+	assert.NoError(t, d.ValidateResponse("GET", "/api/analytics/:modelId", 404,
+		[]byte(`{"error":{"code":"not_found","message":"no analytics for model x"}}`)))
+}
@@ -0,0 +1,158 @@
+package openapi
+
+import (
+	"net/http"
+
+	"nof0-api/internal/types"
+)
+
+// group mirrors one server.AddRoutes call in handler.RegisterHandlers.
+type group struct {
+	prefix string
+	tag    string
+	role   string // API key role required; empty for public routes
+	arena  bool   // behind the Arena middleware: accepts ?arena= and ?snapshot=
+	ops    []operation
+}
+
+// operation is one route. request supplies the query parameters (form tags)
+// and, for POST and PATCH, the JSON body; response is the 200 body.
+type operation struct {
+	method   string
+	path     string
+	handler  string
+	summary  string
+	request  any
+	response any
+	body     any  // ingest item type; the body is one item or an array
+	text     bool // also answers text/plain (?format=text)
+	stream   bool // server-sent events of response
+	failure  any  // 503 body in place of types.ErrorResponse
+}
+
+var groups = []group{
+	{
+		tag: "probes",
+		ops: []operation{
+			{method: http.MethodGet, path: "/healthz", handler: "HealthzHandler", summary: "Dependency checks; always 200",
+				response: types.HealthResponse{}},
+			{method: http.MethodGet, path: "/readyz", handler: "ReadyzHandler", summary: "Dependency checks; 503 when one failed",
+				response: types.HealthResponse{}, failure: types.HealthResponse{}},
+		},
+	},
+	{
+		prefix: "/api",
+		tag:    "data",
+		arena:  true,
+		ops: []operation{
+			{method: http.MethodGet, path: "/arenas", handler: "ArenasHandler", summary: "Arenas and the default one",
+				response: types.ArenasResponse{}},
+			{method: http.MethodGet, path: "/snapshots", handler: "SnapshotsHandler", summary: "Recorded snapshots of the arena",
+				response: types.SnapshotsResponse{}},
+			{method: http.MethodGet, path: "/status", handler: "StatusHandler", summary: "Freshness of the main resources",
+				response: types.StatusResponse{}},
+			{method: http.MethodGet, path: "/account-totals", handler: "AccountTotalsHandler", summary: "Account totals with positions",
+				request: types.AccountTotalsRequest{}, response: types.AccountTotalsResponse{}},
+			{method: http.MethodGet, path: "/analytics", handler: "AnalyticsHandler", summary: "Analytics of every model",
+				response: types.AnalyticsResponse{}},
+			{method: http.MethodGet, path: "/analytics/correlation", handler: "CorrelationHandler", summary: "Pairwise return correlation",
+				request: types.CorrelationRequest{}, response: types.CorrelationResponse{}},
+			{method: http.MethodGet, path: "/analytics/:modelId", handler: "ModelAnalyticsHandler", summary: "Analytics of one model",
+				response: types.ModelAnalyticsResponse{}},
+			{method: http.MethodGet, path: "/models", handler: "ListModelsHandler", summary: "Registered models",
+				response: types.ModelsResponse{}},
+			{method: http.MethodGet, path: "/models/:modelId", handler: "ModelDetailHandler", summary: "Everything known about one model",
+				request: types.ModelDetailRequest{}, response: types.ModelDetailResponse{}},
+			{method: http.MethodGet, path: "/crypto-prices", handler: "CryptoPricesHandler", summary: "Latest crypto prices",
+				request: types.CryptoPricesRequest{}, response: types.CryptoPricesResponse{}},
+			{method: http.MethodGet, path: "/leaderboard", handler: "LeaderboardHandler", summary: "Model leaderboard",
+				request: types.LeaderboardRequest{}, response: types.LeaderboardResponse{}},
+			{method: http.MethodGet, path: "/since-inception-values", handler: "SinceInceptionHandler", summary: "NAV since inception",
+				response: types.SinceInceptionResponse{}},
+			{method: http.MethodGet, path: "/trades", handler: "TradesHandler", summary: "Closed trades",
+				response: types.TradesResponse{}},
+			{method: http.MethodGet, path: "/positions", handler: "PositionsHandler", summary: "Open positions by model",
+				request: types.PositionsRequest{}, response: types.PositionsResponse{}},
+			{method: http.MethodGet, path: "/conversations", handler: "ConversationsHandler", summary: "Model conversations",
+				response: types.ConversationsResponse{}},
+			{method: http.MethodGet, path: "/conversations/search", handler: "ConversationSearchHandler", summary: "Search conversation messages",
+				request: types.ConversationSearchRequest{}, response: types.ConversationSearchResponse{}},
+			{method: http.MethodGet, path: "/conversations/links", handler: "ConversationLinksHandler", summary: "Decisions linked to trades",
+				request: types.ConversationLinksRequest{}, response: types.ConversationLinksResponse{}},
+			{method: http.MethodGet, path: "/invocations", handler: "InvocationsHandler", summary: "Model invocations per day",
+				request: types.InvocationsRequest{}, response: types.InvocationsResponse{}},
+			{method: http.MethodGet, path: "/openapi.json", handler: "OpenApiHandler", summary: "This document"},
+		},
+	},
+	{
+		prefix: "/api",
+		tag:    "models",
+		role:   "admin",
+		arena:  true,
+		ops: []operation{
+			{method: http.MethodPost, path: "/models", handler: "CreateModelHandler", summary: "Register a model",
+				request: types.CreateModelRequest{}, response: types.ModelResponse{}},
+			{method: http.MethodPatch, path: "/models/:modelId", handler: "UpdateModelHandler", summary: "Update a model",
+				request: types.UpdateModelRequest{}, response: types.ModelResponse{}},
+		},
+	},
+	{
+		prefix: "/api/admin",
+		tag:    "admin",
+		role:   "admin",
+		ops: []operation{
+			{method: http.MethodGet, path: "/jobs", handler: "JobsHandler", summary: "Scheduled job state",
+				response: types.JobsResponse{}},
+		},
+	},
+	{
+		prefix: "/api/admin",
+		tag:    "admin",
+		role:   "admin",
+		arena:  true,
+		ops: []operation{
+			{method: http.MethodGet, path: "/diff", handler: "DiffHandler", summary: "Compare two versions of the arena",
+				request: types.DiffRequest{}, response: types.DiffResponse{}, text: true},
+		},
+	},
+	{
+		prefix: "/api/admin",
+		tag:    "admin",
+		role:   "admin",
+		ops: []operation{
+			{method: http.MethodGet, path: "/keys", handler: "ListApiKeysHandler", summary: "API keys",
+				response: types.ApiKeysResponse{}},
+			{method: http.MethodPost, path: "/keys", handler: "CreateApiKeyHandler", summary: "Create an API key",
+				request: types.CreateApiKeyRequest{}, response: types.ApiKeyResponse{}},
+			{method: http.MethodDelete, path: "/keys/:id", handler: "RevokeApiKeyHandler", summary: "Revoke an API key",
+				response: types.ApiKeyResponse{}},
+		},
+	},
+	{
+		prefix: "/api/ingest",
+		tag:    "ingest",
+		role:   "ingester",
+		arena:  true,
+		ops: []operation{
+			{method: http.MethodPost, path: "/prices", handler: "IngestPricesHandler", summary: "Push prices",
+				body: types.CryptoPrice{}, response: types.IngestResponse{}},
+			{method: http.MethodPost, path: "/trades", handler: "IngestTradesHandler", summary: "Push trades",
+				body: types.Trade{}, response: types.IngestResponse{}},
+			{method: http.MethodPost, path: "/positions", handler: "IngestPositionsHandler", summary: "Push positions",
+				body: types.PositionsByModel{}, response: types.IngestResponse{}},
+			{method: http.MethodPost, path: "/account-snapshots", handler: "IngestAccountSnapshotsHandler", summary: "Push account snapshots",
+				body: types.AccountTotal{}, response: types.IngestResponse{}},
+			{method: http.MethodPost, path: "/conversations", handler: "IngestConversationsHandler", summary: "Push conversations",
+				body: types.Conversation{}, response: types.IngestResponse{}},
+		},
+	},
+	{
+		prefix: "/api",
+		tag:    "events",
+		arena:  true,
+		ops: []operation{
+			{method: http.MethodGet, path: "/events", handler: "EventsHandler", summary: "Server-sent change events",
+				response: types.ChangeEvent{}, stream: true},
+		},
+	},
+}
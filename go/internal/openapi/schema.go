@@ -0,0 +1,144 @@
+package openapi
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// generator turns Go types into schemas; named structs become components
+// referenced by $ref.
+type generator struct {
+	schemas map[string]*Schema
+}
+
+// schema describes how encoding/json writes (and go-zero's httpx.Parse
+// reads) values of t. Slices, maps, pointers and interfaces may be null.
+func (g *generator) schema(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.schema(t.Elem())
+		if s.Ref != "" {
+			return &Schema{AllOf: []*Schema{s}, Nullable: true}
+		}
+		s.Nullable = true
+		return s
+	case reflect.Interface:
+		return &Schema{Nullable: true}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem()), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		if _, ok := g.schemas[t.Name()]; !ok {
+			g.schemas[t.Name()] = nil // reserve the name for recursive types
+			g.schemas[t.Name()] = g.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+	return &Schema{}
+}
+
+// object describes the JSON fields of struct t. Fields are required unless
+// tagged omitempty (responses) or optional (go-zero requests).
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("json")
+		if !ok || tag == "-" || !f.IsExported() {
+			continue
+		}
+		name, opts := parseTag(tag)
+		if name == "" {
+			name = f.Name
+		}
+		fs := g.schema(f.Type)
+		applyOptions(fs, opts)
+		s.Properties[name] = fs
+		if !opts.optional {
+			s.Required = append(s.Required, name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// queryParams lists the form-tagged fields of request type t.
+func (g *generator) queryParams(t reflect.Type) []Parameter {
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("form")
+		if !ok {
+			continue
+		}
+		name, opts := parseTag(tag)
+		s := g.schema(f.Type)
+		applyOptions(s, opts)
+		params = append(params, Parameter{Name: name, In: "query", Required: !opts.optional && s.Default == nil, Schema: s})
+	}
+	return params
+}
+
+type tagOptions struct {
+	optional bool
+	options  []string
+	def      string
+	hasDef   bool
+}
+
+// parseTag splits json/form tags, including go-zero's optional, options=
+// and default= options.
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	var opts tagOptions
+	for _, p := range parts[1:] {
+		switch {
+		case p == "omitempty", p == "optional":
+			opts.optional = true
+		case strings.HasPrefix(p, "options="):
+			opts.options = strings.Split(strings.TrimPrefix(p, "options="), "|")
+		case strings.HasPrefix(p, "default="):
+			opts.def, opts.hasDef = strings.TrimPrefix(p, "default="), true
+		}
+	}
+	return parts[0], opts
+}
+
+func applyOptions(s *Schema, opts tagOptions) {
+	for _, o := range opts.options {
+		s.Enum = append(s.Enum, o)
+	}
+	if !opts.hasDef {
+		return
+	}
+	switch s.Type {
+	case "integer":
+		if v, err := strconv.ParseInt(opts.def, 10, 64); err == nil {
+			s.Default = v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(opts.def, 64); err == nil {
+			s.Default = v
+		}
+	default:
+		s.Default = opts.def
+	}
+}
@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidateResponse checks a JSON response body of the route (a go-zero or
+// OpenAPI path) against the document: the status's response, or the
+// default one, must describe it.
+func (d *Document) ValidateResponse(method, route string, status int, body []byte) error {
+	item, ok := d.Paths[Path(route)]
+	if !ok {
+		return fmt.Errorf("%s is not documented", route)
+	}
+	op, ok := item[strings.ToLower(method)]
+	if !ok {
+		return fmt.Errorf("%s %s is not documented", method, route)
+	}
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		if resp, ok = op.Responses["default"]; !ok {
+			return fmt.Errorf("%s %s: status %d is not documented", method, route, status)
+		}
+	}
+	media, ok := resp.Content[mediaJSON]
+	if !ok {
+		return fmt.Errorf("%s %s: status %d has no JSON body", method, route, status)
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("%s %s: %w", method, route, err)
+	}
+	return d.validate(media.Schema, v, "$")
+}
+
+// validate checks v, decoded with json.Number, against s; at names the
+// location in errors.
+func (d *Document) validate(s *Schema, v any, at string) error {
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		ref, ok := d.Components.Schemas[name]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema %s", at, s.Ref)
+		}
+		return d.validate(ref, v, at)
+	}
+	if v == nil {
+		if s.Nullable || s.Type == "" && len(s.AllOf) == 0 && len(s.OneOf) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not allowed", at)
+	}
+	for _, sub := range s.AllOf {
+		if err := d.validate(sub, v, at); err != nil {
+			return err
+		}
+	}
+	if len(s.OneOf) > 0 {
+		matched := 0
+		for _, sub := range s.OneOf {
+			if d.validate(sub, v, at) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fmt.Errorf("%s: matches %d of the oneOf schemas", at, matched)
+		}
+	}
+	if len(s.Enum) > 0 && !contains(s.Enum, v) {
+		return fmt.Errorf("%s: %v is not one of %v", at, v, s.Enum)
+	}
+
+	switch s.Type {
+	case "":
+		return nil
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return typeError(at, s.Type, v)
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return typeError(at, s.Type, v)
+		}
+	case "number", "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return typeError(at, s.Type, v)
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return fmt.Errorf("%s: %w", at, err)
+		}
+		if s.Type == "integer" && f != math.Trunc(f) {
+			return typeError(at, s.Type, v)
+		}
+	case "array":
+		list, ok := v.([]any)
+		if !ok {
+			return typeError(at, s.Type, v)
+		}
+		for i, e := range list {
+			if err := d.validate(s.Items, e, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return typeError(at, s.Type, v)
+		}
+		return d.validateObject(s, obj, at)
+	}
+	return nil
+}
+
+func (d *Document) validateObject(s *Schema, obj map[string]any, at string) error {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			return fmt.Errorf("%s: missing required %q", at, name)
+		}
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		path := at + "." + k
+		if prop, ok := s.Properties[k]; ok {
+			if err := d.validate(prop, obj[k], path); err != nil {
+				return err
+			}
+			continue
+		}
+		switch extra := s.AdditionalProperties.(type) {
+		case *Schema:
+			if err := d.validate(extra, obj[k], path); err != nil {
+				return err
+			}
+		case bool:
+			if !extra {
+				return fmt.Errorf("%s: undocumented property", path)
+			}
+		}
+	}
+	return nil
+}
+
+func typeError(at, want string, v any) error {
+	return fmt.Errorf("%s: %T is not of type %s", at, v, want)
+}
+
+func contains(enum []any, v any) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
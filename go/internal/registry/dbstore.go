@@ -0,0 +1,138 @@
+package registry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/metrics"
+	"nof0-api/internal/types"
+)
+
+// DBStore keeps the registry in the Postgres models table.
+// Reads fall back to the file store when the database is unavailable.
+type DBStore struct {
+	conn     sqlx.SqlConn
+	fallback *FileStore
+}
+
+func NewDBStore(conn sqlx.SqlConn, fallback *FileStore) *DBStore {
+	return &DBStore{conn: conn, fallback: fallback}
+}
+
+const modelColumns = `id, display_name, coalesce(provider,'') AS provider, coalesce(color,'') AS color,
+       coalesce(icon_url,'') AS icon_url, starting_capital, coalesce(inception_ts_ms,0) AS inception_ts_ms,
+       status, coalesce(prompt_version,'') AS prompt_version`
+
+type modelRow struct {
+	Id              string  `db:"id"`
+	DisplayName     string  `db:"display_name"`
+	Provider        string  `db:"provider"`
+	Color           string  `db:"color"`
+	IconUrl         string  `db:"icon_url"`
+	StartingCapital float64 `db:"starting_capital"`
+	InceptionTsMs   int64   `db:"inception_ts_ms"`
+	Status          string  `db:"status"`
+	PromptVersion   string  `db:"prompt_version"`
+}
+
+func (r modelRow) toModelInfo() types.ModelInfo {
+	return types.ModelInfo{
+		Id:              r.Id,
+		DisplayName:     r.DisplayName,
+		Provider:        r.Provider,
+		Color:           r.Color,
+		IconUrl:         r.IconUrl,
+		StartingCapital: r.StartingCapital,
+		InceptionTime:   r.InceptionTsMs,
+		Status:          r.Status,
+		PromptVersion:   r.PromptVersion,
+	}
+}
+
+func (s *DBStore) List(ctx context.Context) ([]types.ModelInfo, error) {
+	var rows []modelRow
+	if err := s.conn.QueryRowsCtx(ctx, &rows, `SELECT `+modelColumns+` FROM models ORDER BY id`); err != nil {
+		logx.WithContext(ctx).Errorf("db models failed, falling back: %v", err)
+		metrics.DBFallbacks.Inc("models")
+		return s.fallback.List(ctx)
+	}
+	out := make([]types.ModelInfo, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row.toModelInfo())
+	}
+	return out, nil
+}
+
+func (s *DBStore) Get(ctx context.Context, id string) (*types.ModelInfo, error) {
+	var row modelRow
+	err := s.conn.QueryRowCtx(ctx, &row, `SELECT `+modelColumns+` FROM models WHERE id = $1`, id)
+	switch {
+	case err == nil:
+		m := row.toModelInfo()
+		return &m, nil
+	case errors.Is(err, sqlx.ErrNotFound):
+		return nil, ErrNotFound
+	default:
+		logx.WithContext(ctx).Errorf("db model %s failed, falling back: %v", id, err)
+		metrics.DBFallbacks.Inc("models")
+		return s.fallback.Get(ctx, id)
+	}
+}
+
+func (s *DBStore) Create(ctx context.Context, m types.ModelInfo) (*types.ModelInfo, error) {
+	m, err := withDefaults(m)
+	if err != nil {
+		return nil, err
+	}
+	const q = `INSERT INTO models(id, display_name, provider, color, icon_url, starting_capital, inception_ts_ms, status, prompt_version)
+          VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+          ON CONFLICT (id) DO NOTHING`
+	res, err := s.conn.ExecCtx(ctx, q, m.Id, m.DisplayName, nullIfEmpty(m.Provider), nullIfEmpty(m.Color),
+		nullIfEmpty(m.IconUrl), m.StartingCapital, nullIfZero(m.InceptionTime), m.Status, nullIfEmpty(m.PromptVersion))
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return nil, ErrExists
+	}
+	return &m, nil
+}
+
+func (s *DBStore) Update(ctx context.Context, req *types.UpdateModelRequest) (*types.ModelInfo, error) {
+	var row modelRow
+	err := s.conn.QueryRowCtx(ctx, &row, `SELECT `+modelColumns+` FROM models WHERE id = $1`, req.ModelId)
+	if errors.Is(err, sqlx.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := row.toModelInfo()
+	applyPatch(&m, req)
+
+	const q = `UPDATE models SET display_name=$2, provider=$3, color=$4, icon_url=$5, starting_capital=$6,
+            inception_ts_ms=$7, status=$8, prompt_version=$9, updated_at=now()
+          WHERE id=$1`
+	if _, err := s.conn.ExecCtx(ctx, q, m.Id, m.DisplayName, nullIfEmpty(m.Provider), nullIfEmpty(m.Color),
+		nullIfEmpty(m.IconUrl), m.StartingCapital, nullIfZero(m.InceptionTime), m.Status, nullIfEmpty(m.PromptVersion)); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nullIfZero(i int64) interface{} {
+	if i == 0 {
+		return nil
+	}
+	return i
+}
@@ -0,0 +1,205 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"nof0-api/internal/types"
+)
+
+// registryFile is the on-disk layout shared by the JSON and YAML formats.
+type registryFile struct {
+	Models []types.ModelInfo `json:"models"`
+}
+
+// FileStore keeps the registry in a YAML or JSON file (chosen by extension).
+// An empty path keeps the registry in memory only.
+type FileStore struct {
+	path string
+
+	mu     sync.RWMutex
+	loaded bool
+	models map[string]types.ModelInfo
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) List(ctx context.Context) ([]types.ModelInfo, error) {
+	if err := s.ensureLoaded(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]types.ModelInfo, 0, len(s.models))
+	for _, m := range s.models {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Id < out[j].Id })
+	return out, nil
+}
+
+func (s *FileStore) Get(ctx context.Context, id string) (*types.ModelInfo, error) {
+	if err := s.ensureLoaded(); err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.models[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &m, nil
+}
+
+func (s *FileStore) Create(ctx context.Context, m types.ModelInfo) (*types.ModelInfo, error) {
+	m, err := withDefaults(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureLoaded(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.models[m.Id]; ok {
+		return nil, ErrExists
+	}
+	s.models[m.Id] = m
+	if err := s.save(); err != nil {
+		delete(s.models, m.Id)
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (s *FileStore) Update(ctx context.Context, req *types.UpdateModelRequest) (*types.ModelInfo, error) {
+	if err := s.ensureLoaded(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, ok := s.models[req.ModelId]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	m := prev
+	applyPatch(&m, req)
+	s.models[m.Id] = m
+	if err := s.save(); err != nil {
+		s.models[m.Id] = prev
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ensureLoaded reads the file once; a missing file is an empty registry.
+func (s *FileStore) ensureLoaded() error {
+	s.mu.RLock()
+	loaded := s.loaded
+	s.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loaded {
+		return nil
+	}
+	s.models = map[string]types.ModelInfo{}
+	if s.path != "" {
+		raw, err := os.ReadFile(s.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if len(raw) > 0 {
+			var f registryFile
+			if err := s.decode(raw, &f); err != nil {
+				return err
+			}
+			for _, m := range f.Models {
+				if m, err := withDefaults(m); err == nil {
+					s.models[m.Id] = m
+				}
+			}
+		}
+	}
+	s.loaded = true
+	return nil
+}
+
+// save writes the registry back to disk. Callers must hold the write lock.
+func (s *FileStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+	f := registryFile{Models: make([]types.ModelInfo, 0, len(s.models))}
+	for _, m := range s.models {
+		f.Models = append(f.Models, m)
+	}
+	sort.Slice(f.Models, func(i, j int) bool { return f.Models[i].Id < f.Models[j].Id })
+
+	raw, err := s.encode(&f)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func (s *FileStore) isYAML() bool {
+	ext := strings.ToLower(filepath.Ext(s.path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// decode parses JSON or YAML into v. YAML goes through a generic map so the
+// json tags on types.ModelInfo apply to both formats.
+func (s *FileStore) decode(raw []byte, v interface{}) error {
+	if !s.isYAML() {
+		return json.Unmarshal(raw, v)
+	}
+	var generic interface{}
+	if err := yaml.Unmarshal(raw, &generic); err != nil {
+		return err
+	}
+	bs, err := json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bs, v)
+}
+
+func (s *FileStore) encode(v interface{}) ([]byte, error) {
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil || !s.isYAML() {
+		return bs, err
+	}
+	// JSON is valid YAML: parsing it as a node keeps key order and number
+	// literals intact; dropping the flow/quote styles yields block YAML.
+	var node yaml.Node
+	if err := yaml.Unmarshal(bs, &node); err != nil {
+		return nil, err
+	}
+	clearStyle(&node)
+	return yaml.Marshal(&node)
+}
+
+func clearStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		clearStyle(c)
+	}
+}
@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/types"
+)
+
+const testRegistryFile = "../../etc/models.yaml"
+
+func TestFileStoreLoadsShippedRegistry(t *testing.T) {
+	store := NewFileStore(testRegistryFile)
+
+	models, err := store.List(context.Background())
+	require.NoError(t, err)
+	assert.Greater(t, len(models), 0, "Should have at least one model")
+
+	for i, m := range models {
+		assert.NotEmpty(t, m.Id)
+		assert.NotEmpty(t, m.DisplayName)
+		assert.Contains(t, []string{StatusActive, StatusPaused, StatusRetired}, m.Status)
+		assert.Greater(t, m.StartingCapital, 0.0)
+		if i > 0 {
+			assert.Less(t, models[i-1].Id, m.Id, "Models should be sorted by id")
+		}
+	}
+
+	gpt, err := store.Get(context.Background(), "gpt-5")
+	require.NoError(t, err)
+	assert.Equal(t, "GPT-5", gpt.DisplayName)
+	assert.Greater(t, gpt.InceptionTime, int64(1e12), "Inception time should be in milliseconds")
+}
+
+func TestFileStoreCreateAndUpdate(t *testing.T) {
+	for _, name := range []string{"models.yaml", "models.json"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			ctx := context.Background()
+			store := NewFileStore(path)
+
+			created, err := store.Create(ctx, types.ModelInfo{Id: "new-model", InceptionTime: 1760738409834})
+			require.NoError(t, err)
+			assert.Equal(t, "new-model", created.DisplayName, "Display name should default to id")
+			assert.Equal(t, StatusActive, created.Status)
+			assert.Equal(t, DefaultStartingCapital, created.StartingCapital)
+
+			_, err = store.Create(ctx, types.ModelInfo{Id: "new-model"})
+			assert.ErrorIs(t, err, ErrExists)
+			_, err = store.Create(ctx, types.ModelInfo{Id: "  "})
+			assert.ErrorIs(t, err, ErrNoId)
+
+			status, color := StatusRetired, "#123456"
+			updated, err := store.Update(ctx, &types.UpdateModelRequest{ModelId: "new-model", Status: &status, Color: &color})
+			require.NoError(t, err)
+			assert.Equal(t, StatusRetired, updated.Status)
+			assert.Equal(t, "#123456", updated.Color)
+			assert.Equal(t, "new-model", updated.DisplayName, "Unset fields should be kept")
+
+			_, err = store.Update(ctx, &types.UpdateModelRequest{ModelId: "missing"})
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			// A fresh store must see the persisted state.
+			reloaded, err := NewFileStore(path).Get(ctx, "new-model")
+			require.NoError(t, err)
+			assert.Equal(t, *updated, *reloaded)
+
+			_, err = os.Stat(path + ".tmp")
+			assert.True(t, os.IsNotExist(err), "Temp file should be renamed away")
+		})
+	}
+}
+
+func TestFileStoreMissingFile(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "absent.yaml"))
+
+	models, err := store.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, models)
+
+	_, err = store.Get(context.Background(), "gpt-5")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,99 @@
+package registry
+
+import (
+	"context"
+	"strings"
+
+	"nof0-api/internal/errs"
+	"nof0-api/internal/types"
+)
+
+// Model lifecycle states.
+const (
+	StatusActive  = "active"
+	StatusPaused  = "paused"
+	StatusRetired = "retired"
+)
+
+// DefaultStartingCapital is the NAV a model starts with unless configured.
+const DefaultStartingCapital = 10000.0
+
+var (
+	ErrNotFound = errs.NotFound("model not found")
+	ErrExists   = errs.InvalidArgument("model already exists")
+	ErrNoId     = errs.InvalidArgument("model id required")
+)
+
+// Store abstracts where model metadata lives. It can be backed by a file, DB, etc.
+type Store interface {
+	List(ctx context.Context) ([]types.ModelInfo, error)
+	Get(ctx context.Context, id string) (*types.ModelInfo, error)
+	Create(ctx context.Context, m types.ModelInfo) (*types.ModelInfo, error)
+	Update(ctx context.Context, req *types.UpdateModelRequest) (*types.ModelInfo, error)
+}
+
+// Ensure both stores implement Store
+var (
+	_ Store = (*FileStore)(nil)
+	_ Store = (*DBStore)(nil)
+)
+
+// Lookup returns the registry keyed by model id, for joining model attributes
+// onto other responses.
+func Lookup(ctx context.Context, s Store) (map[string]types.ModelInfo, error) {
+	models, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]types.ModelInfo, len(models))
+	for _, m := range models {
+		out[m.Id] = m
+	}
+	return out, nil
+}
+
+// withDefaults fills the fields a new model must have.
+func withDefaults(m types.ModelInfo) (types.ModelInfo, error) {
+	m.Id = strings.TrimSpace(m.Id)
+	if m.Id == "" {
+		return m, ErrNoId
+	}
+	if m.DisplayName == "" {
+		m.DisplayName = m.Id
+	}
+	if m.StartingCapital == 0 {
+		m.StartingCapital = DefaultStartingCapital
+	}
+	if m.Status == "" {
+		m.Status = StatusActive
+	}
+	return m, nil
+}
+
+// applyPatch copies every field set in req onto m.
+func applyPatch(m *types.ModelInfo, req *types.UpdateModelRequest) {
+	if req.DisplayName != nil {
+		m.DisplayName = *req.DisplayName
+	}
+	if req.Provider != nil {
+		m.Provider = *req.Provider
+	}
+	if req.Color != nil {
+		m.Color = *req.Color
+	}
+	if req.IconUrl != nil {
+		m.IconUrl = *req.IconUrl
+	}
+	if req.StartingCapital != nil {
+		m.StartingCapital = *req.StartingCapital
+	}
+	if req.InceptionTime != nil {
+		m.InceptionTime = *req.InceptionTime
+	}
+	if req.Status != nil {
+		m.Status = *req.Status
+	}
+	if req.PromptVersion != nil {
+		m.PromptVersion = *req.PromptVersion
+	}
+}
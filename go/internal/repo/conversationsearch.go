@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/types"
+)
+
+// tsHeadlineOpts mirrors data.Snippet: one fragment with the matches
+// delimited for data.MarkHeadline, which escapes the raw content.
+const tsHeadlineOpts = "StartSel=" + data.HeadlineStart + ", StopSel=" + data.HeadlineStop + ", MaxFragments=1, MaxWords=35, MinWords=15"
+
+// ConversationSearch runs conversation search against Postgres using the
+// conversation_messages.content_tsv full-text index.
+type ConversationSearch struct {
+	conn sqlx.SqlConn
+}
+
+func NewConversationSearch(conn sqlx.SqlConn) *ConversationSearch {
+	return &ConversationSearch{conn: conn}
+}
+
+type conversationHitRow struct {
+	MessageId      int64   `db:"message_id"`
+	ConversationId int64   `db:"conversation_id"`
+	ModelId        string  `db:"model_id"`
+	Role           string  `db:"role"`
+	Content        string  `db:"content"`
+	TsMs           int64   `db:"ts_ms"`
+	Snippet        string  `db:"snippet"`
+	Score          float64 `db:"score"`
+}
+
+// Search returns the requested page of messages in arenaId matching req and
+// the total number of matches. From/To are epoch milliseconds.
+func (s *ConversationSearch) Search(ctx context.Context, arenaId string, req *types.ConversationSearchRequest) ([]types.ConversationHit, int, error) {
+	args := []interface{}{arenaId}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{"c.arena_id = $1"}
+	if req.ModelId != "" {
+		where = append(where, "c.model_id = "+arg(req.ModelId))
+	}
+	if req.Role != "" {
+		where = append(where, "m.role = "+arg(req.Role))
+	}
+	if req.From > 0 {
+		where = append(where, "m.ts_ms >= "+arg(req.From))
+	}
+	if req.To > 0 {
+		where = append(where, "m.ts_ms <= "+arg(req.To))
+	}
+	snippet, score := "left(m.content, 160)", "0::double precision"
+	if q := strings.TrimSpace(req.Q); q != "" {
+		tsq := "websearch_to_tsquery('english', " + arg(q) + ")"
+		where = append(where, "m.content_tsv @@ "+tsq)
+		snippet = "ts_headline('english', m.content, " + tsq + ", " + arg(tsHeadlineOpts) + ")"
+		score = "ts_rank(m.content_tsv, " + tsq + ")::double precision"
+	}
+	from := `FROM conversation_messages m JOIN conversations c ON c.id = m.conversation_id WHERE ` + strings.Join(where, " AND ")
+
+	var total int
+	if err := s.conn.QueryRowCtx(ctx, &total, `SELECT count(*) `+from, args...); err != nil {
+		return nil, 0, err
+	}
+
+	q := `SELECT m.id AS message_id, c.id AS conversation_id, c.model_id, m.role, m.content,
+            coalesce(m.ts_ms, 0) AS ts_ms, ` + snippet + ` AS snippet, ` + score + ` AS score
+          ` + from + `
+          ORDER BY score DESC, m.ts_ms DESC NULLS LAST, m.id`
+	if req.Limit > 0 {
+		q += " LIMIT " + arg(req.Limit)
+	}
+	if req.Offset > 0 {
+		q += " OFFSET " + arg(req.Offset)
+	}
+	var rows []conversationHitRow
+	if err := s.conn.QueryRowsCtx(ctx, &rows, q, args...); err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]types.ConversationHit, 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, types.ConversationHit{
+			ModelId:        row.ModelId,
+			ConversationId: row.ConversationId,
+			MessageId:      row.MessageId,
+			Role:           row.Role,
+			Timestamp:      row.TsMs,
+			Content:        row.Content,
+			Snippet:        data.MarkHeadline(row.Snippet),
+			Score:          row.Score,
+		})
+	}
+	return hits, total, nil
+}
@@ -2,60 +2,95 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/zeromicro/go-zero/core/logx"
-	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"nof0-api/internal/cache"
 	"nof0-api/internal/data"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/metrics"
 	"nof0-api/internal/types"
 )
 
-// TTLs bundles cache durations in seconds.
+// TTLs bundles cache durations in seconds. Stale is how long an expired
+// entry may still be served while it is reloaded in the background.
 type TTLs struct {
 	Short  int
 	Medium int
 	Long   int
+	Stale  int
 }
 
-// DBRepo loads data from Postgres and caches in Redis.
-// For resources not yet implemented in DB, it falls back to the file DataLoader.
+func (t TTLs) policy(fresh int) cache.Policy {
+	return cache.Policy{Fresh: time.Duration(fresh) * time.Second, Stale: time.Duration(t.Stale) * time.Second}
+}
+
+// DBRepo loads data from Postgres and caches in Redis. Arenas that have not
+// been imported (no models in arena_models), and reads that fail, fall back
+// to the file DataLoader.
 type DBRepo struct {
 	conn     sqlx.SqlConn
-	rds      *redis.Redis
+	cache    *cache.Cache
 	fallback *data.DataLoader
 	ttls     TTLs
+	arena    string
+}
+
+var (
+	_ data.DataSource = (*DBRepo)(nil)
+	_ data.Recorder   = (*DBRepo)(nil)
+)
+
+// errNotImported is returned by queries for an arena without models in
+// Postgres; it is never cached.
+var errNotImported = errors.New("arena not imported")
+
+func NewDBRepo(conn sqlx.SqlConn, c *cache.Cache, fallback *data.DataLoader, ttls TTLs) *DBRepo {
+	return &DBRepo{conn: conn, cache: c, fallback: fallback, ttls: ttls, arena: data.DefaultArena}
 }
 
-func NewDBRepo(conn sqlx.SqlConn, rds *redis.Redis, fallback *data.DataLoader, ttls TTLs) *DBRepo {
-	return &DBRepo{conn: conn, rds: rds, fallback: fallback, ttls: ttls}
+// ForArena returns a repo reading arena-scoped tables for arena, falling
+// back to that arena's files.
+func (r *DBRepo) ForArena(arena string, fallback *data.DataLoader) *DBRepo {
+	c := *r
+	c.arena, c.fallback = arena, fallback
+	return &c
 }
 
-// helper: get from redis into v
-func (r *DBRepo) getCache(ctx context.Context, key string, v interface{}) (bool, error) {
-	if r.rds == nil {
-		return false, nil
-	}
-	s, err := r.rds.GetCtx(ctx, key)
-	if err != nil || len(s) == 0 {
-		return false, err
+// load reads resource through the cache under key. Arenas not imported and
+// failed queries are served by file; not-found errors are returned as is.
+func load[T any](r *DBRepo, resource, key string, fresh int, query func(context.Context) (T, error), file func() (T, error)) (T, error) {
+	defer metrics.ObserveLoad(metrics.SourceDB, resource, time.Now())
+	ctx := context.Background()
+	resp, err := cache.Fetch(ctx, r.cache, key, r.ttls.policy(fresh), query)
+	switch {
+	case errors.Is(err, errNotImported):
+		return file()
+	case errs.Is(err, errs.KindNotFound):
+		return resp, err
+	case err != nil:
+		logx.WithContext(ctx).Errorf("db %s failed, falling back: %v", resource, err)
+		metrics.DBFallbacks.Inc(resource)
+		return file()
 	}
-	return json.Unmarshal([]byte(s), v) == nil, nil
+	return resp, nil
 }
 
-// helper: set redis from v
-func (r *DBRepo) setCache(ctx context.Context, key string, ttl int, v interface{}) {
-	if r.rds == nil || ttl <= 0 {
-		return
+// imported returns errNotImported unless the arena has models in Postgres.
+func (r *DBRepo) imported(ctx context.Context) error {
+	var n int
+	if err := r.conn.QueryRowCtx(ctx, &n, `SELECT count(*) FROM arena_models WHERE arena_id=$1`, r.arena); err != nil {
+		return err
 	}
-	bs, err := json.Marshal(v)
-	if err != nil {
-		logx.WithContext(ctx).Errorf("marshal cache %s: %v", key, err)
-		return
+	if n == 0 {
+		return errNotImported
 	}
-	_ = r.rds.SetexCtx(ctx, key, string(bs), ttl)
+	return nil
 }
 
 // ================= Crypto Prices =================
@@ -66,65 +101,589 @@ type cryptoRow struct {
 	Timestamp int64   `db:"timestamp_ms"`
 }
 
+// LoadCryptoPrices reads the latest price of every symbol; prices are not
+// arena-scoped, so files are served until any price is imported.
 func (r *DBRepo) LoadCryptoPrices() (*types.CryptoPricesResponse, error) {
-	ctx := context.Background()
-	const key = "nof0:crypto_prices"
-	var cached types.CryptoPricesResponse
-	if ok, _ := r.getCache(ctx, key, &cached); ok {
-		return &cached, nil
-	}
+	return load(r, "crypto_prices", cache.CryptoPricesKey, r.ttls.Short, r.queryCryptoPrices, r.fallback.LoadCryptoPrices)
+}
 
+func (r *DBRepo) queryCryptoPrices(ctx context.Context) (*types.CryptoPricesResponse, error) {
 	// Read from materialized view populated by migrations/importer
 	const q = `SELECT symbol, price, timestamp_ms FROM v_crypto_prices_latest`
 
 	var rows []cryptoRow
 	if err := r.conn.QueryRowsCtx(ctx, &rows, q); err != nil {
-		// Fallback to file data
-		logx.WithContext(ctx).Errorf("db crypto_prices failed, falling back: %v", err)
-		return r.fallback.LoadCryptoPrices()
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errNotImported
 	}
 
 	resp := &types.CryptoPricesResponse{Prices: map[string]types.CryptoPrice{}, ServerTime: time.Now().UnixMilli()}
 	for _, row := range rows {
 		resp.Prices[row.Symbol] = types.CryptoPrice{Symbol: row.Symbol, Price: row.Price, Timestamp: row.Timestamp}
+		resp.DataAsOf = max(resp.DataAsOf, row.Timestamp)
 	}
-	r.setCache(ctx, key, r.ttls.Short, resp)
 	return resp, nil
 }
 
-// For the complex AccountTotals (nested positions, markers, etc.),
-// we currently fall back to file data until a full schema is defined.
+// ================= Account Totals =================
+
+type accountTotalRow struct {
+	Id                         int64   `db:"id"`
+	SourceId                   string  `db:"source_id"`
+	ModelId                    string  `db:"model_id"`
+	TsMs                       int64   `db:"ts_ms"`
+	EquityUsd                  float64 `db:"equity_usd"`
+	RealizedPnl                float64 `db:"realized_pnl"`
+	UnrealizedPnl              float64 `db:"unrealized_pnl"`
+	CumPnlPct                  float64 `db:"cum_pnl_pct"`
+	SharpeRatio                float64 `db:"sharpe_ratio"`
+	SinceInceptionHourlyMarker int     `db:"since_inception_hourly_marker"`
+	SinceInceptionMinuteMarker int     `db:"since_inception_minute_marker"`
+}
+
+type positionSnapshotRow struct {
+	SnapshotId int64  `db:"snapshot_id"`
+	Symbol     string `db:"symbol"`
+	Payload    string `db:"payload"`
+}
+
+// LoadAccountTotals reads every equity snapshot of the arena with its
+// position snapshots, oldest first, in the shape of account-totals.json.
 func (r *DBRepo) LoadAccountTotals() (*types.AccountTotalsResponse, error) {
-	return r.fallback.LoadAccountTotals()
+	return load(r, "account_totals", cache.AccountTotalsKey(r.arena), r.ttls.Short, r.queryAccountTotals, r.fallback.LoadAccountTotals)
+}
+
+func (r *DBRepo) queryAccountTotals(ctx context.Context) (*types.AccountTotalsResponse, error) {
+	if err := r.imported(ctx); err != nil {
+		return nil, err
+	}
+	const q = `SELECT id, coalesce(source_id, '') AS source_id, model_id, ts_ms, equity_usd,
+            coalesce(realized_pnl, 0) AS realized_pnl, coalesce(unrealized_pnl, 0) AS unrealized_pnl,
+            coalesce(cum_pnl_pct, 0) AS cum_pnl_pct, coalesce(sharpe_ratio, 0) AS sharpe_ratio,
+            coalesce(since_inception_hourly_marker, 0) AS since_inception_hourly_marker,
+            coalesce(since_inception_minute_marker, 0) AS since_inception_minute_marker
+          FROM account_equity_snapshots
+          WHERE arena_id=$1
+          ORDER BY ts_ms, id`
+	var rows []accountTotalRow
+	if err := r.conn.QueryRowsCtx(ctx, &rows, q, r.arena); err != nil {
+		return nil, err
+	}
+	const pq = `SELECT ps.snapshot_id, ps.symbol, CAST(ps.payload AS text) AS payload
+          FROM account_position_snapshots ps
+          JOIN account_equity_snapshots s ON s.id = ps.snapshot_id
+          WHERE s.arena_id=$1`
+	var posRows []positionSnapshotRow
+	if err := r.conn.QueryRowsCtx(ctx, &posRows, pq, r.arena); err != nil {
+		return nil, err
+	}
+	positions := map[int64]map[string]types.Position{}
+	for _, row := range posRows {
+		var p types.Position
+		if err := json.Unmarshal([]byte(row.Payload), &p); err != nil {
+			return nil, fmt.Errorf("position snapshot %d %s: %w", row.SnapshotId, row.Symbol, err)
+		}
+		if positions[row.SnapshotId] == nil {
+			positions[row.SnapshotId] = map[string]types.Position{}
+		}
+		positions[row.SnapshotId][row.Symbol] = p
+	}
+
+	resp := &types.AccountTotalsResponse{AccountTotals: make([]types.AccountTotal, 0, len(rows)), ServerTime: time.Now().UnixMilli()}
+	for _, row := range rows {
+		resp.AccountTotals = append(resp.AccountTotals, types.AccountTotal{
+			Id:                         row.SourceId,
+			ModelId:                    row.ModelId,
+			Timestamp:                  float64(row.TsMs) / 1000,
+			DollarEquity:               row.EquityUsd,
+			RealizedPnl:                row.RealizedPnl,
+			TotalUnrealizedPnl:         row.UnrealizedPnl,
+			CumPnlPct:                  row.CumPnlPct,
+			SharpeRatio:                row.SharpeRatio,
+			SinceInceptionHourlyMarker: row.SinceInceptionHourlyMarker,
+			SinceInceptionMinuteMarker: row.SinceInceptionMinuteMarker,
+			Positions:                  positions[row.Id],
+		})
+		resp.LastHourlyMarkerRead = max(resp.LastHourlyMarkerRead, row.SinceInceptionHourlyMarker)
+		resp.DataAsOf = max(resp.DataAsOf, row.TsMs)
+	}
+	return resp, nil
 }
 
-func (r *DBRepo) LoadTrades() (*types.TradesResponse, error) { return r.fallback.LoadTrades() }
+// ================= Trades =================
+
+type tradeRow struct {
+	Id                     string          `db:"id"`
+	ModelId                string          `db:"model_id"`
+	Symbol                 string          `db:"symbol"`
+	Side                   string          `db:"side"`
+	TradeType              sql.NullString  `db:"trade_type"`
+	Quantity               sql.NullFloat64 `db:"quantity"`
+	Leverage               sql.NullFloat64 `db:"leverage"`
+	Confidence             sql.NullFloat64 `db:"confidence"`
+	EntryPrice             sql.NullFloat64 `db:"entry_price"`
+	EntryTsMs              sql.NullInt64   `db:"entry_ts_ms"`
+	ExitPrice              sql.NullFloat64 `db:"exit_price"`
+	ExitTsMs               sql.NullInt64   `db:"exit_ts_ms"`
+	RealizedGrossPnl       sql.NullFloat64 `db:"realized_gross_pnl"`
+	RealizedNetPnl         sql.NullFloat64 `db:"realized_net_pnl"`
+	TotalCommissionDollars sql.NullFloat64 `db:"total_commission_dollars"`
+	EntryOid               sql.NullInt64   `db:"entry_oid"`
+	ExitOid                sql.NullInt64   `db:"exit_oid"`
+	ConversationId         sql.NullInt64   `db:"conversation_id"`
+}
 
-// ======= Not yet DB-implemented: fallback to file loader =======
+// LoadTrades reads the arena's trades in the shape of trades.json (times in
+// seconds), oldest entry first.
+func (r *DBRepo) LoadTrades() (*types.TradesResponse, error) {
+	return load(r, "trades", cache.TradesKey(r.arena), r.ttls.Medium, r.queryTrades, r.fallback.LoadTrades)
+}
 
+func (r *DBRepo) queryTrades(ctx context.Context) (*types.TradesResponse, error) {
+	if err := r.imported(ctx); err != nil {
+		return nil, err
+	}
+	const q = `SELECT id, model_id, symbol, side, trade_type, quantity, leverage, confidence, entry_price, entry_ts_ms,
+            exit_price, exit_ts_ms, realized_gross_pnl, realized_net_pnl, total_commission_dollars, entry_oid, exit_oid,
+            conversation_id
+          FROM trades
+          WHERE arena_id=$1
+          ORDER BY entry_ts_ms, id`
+	var rows []tradeRow
+	if err := r.conn.QueryRowsCtx(ctx, &rows, q, r.arena); err != nil {
+		return nil, err
+	}
+
+	resp := &types.TradesResponse{Trades: make([]types.Trade, 0, len(rows)), ServerTime: time.Now().UnixMilli()}
+	for _, row := range rows {
+		resp.Trades = append(resp.Trades, types.Trade{
+			Id:                     row.Id,
+			ModelId:                row.ModelId,
+			Symbol:                 row.Symbol,
+			Side:                   row.Side,
+			TradeType:              row.TradeType.String,
+			Quantity:               row.Quantity.Float64,
+			Leverage:               row.Leverage.Float64,
+			Confidence:             row.Confidence.Float64,
+			EntryPrice:             row.EntryPrice.Float64,
+			EntryTime:              float64(row.EntryTsMs.Int64) / 1000,
+			EntryOid:               row.EntryOid.Int64,
+			ExitPrice:              row.ExitPrice.Float64,
+			ExitTime:               float64(row.ExitTsMs.Int64) / 1000,
+			ExitOid:                row.ExitOid.Int64,
+			RealizedGrossPnl:       row.RealizedGrossPnl.Float64,
+			RealizedNetPnl:         row.RealizedNetPnl.Float64,
+			TotalCommissionDollars: row.TotalCommissionDollars.Float64,
+			ConversationId:         row.ConversationId.Int64,
+		})
+		resp.DataAsOf = max(resp.DataAsOf, row.EntryTsMs.Int64, row.ExitTsMs.Int64)
+	}
+	return resp, nil
+}
+
+// ================= Since Inception =================
+
+type sinceInceptionRow struct {
+	ModelId           string  `db:"model_id"`
+	NavSinceInception float64 `db:"nav_since_inception"`
+	InceptionTsMs     int64   `db:"inception_ts_ms"`
+	NumInvocations    int     `db:"num_invocations"`
+}
+
+// LoadSinceInception derives one value per arena model: its registry
+// starting capital, its inception (registry, else first equity snapshot) and
+// its number of invocations.
 func (r *DBRepo) LoadSinceInception() (*types.SinceInceptionResponse, error) {
-	return r.fallback.LoadSinceInception()
+	return load(r, "since_inception", cache.SinceInceptionKey(r.arena), r.ttls.Long, r.querySinceInception, r.fallback.LoadSinceInception)
 }
 
+func (r *DBRepo) querySinceInception(ctx context.Context) (*types.SinceInceptionResponse, error) {
+	const q = `SELECT am.model_id, m.starting_capital AS nav_since_inception,
+            coalesce(m.inception_ts_ms, s.first_ts_ms, 0) AS inception_ts_ms,
+            coalesce(i.n, 0)::int AS num_invocations
+          FROM arena_models am
+          JOIN models m ON m.id = am.model_id
+          LEFT JOIN (SELECT model_id, min(ts_ms) AS first_ts_ms FROM account_equity_snapshots WHERE arena_id=$1 GROUP BY model_id) s
+            ON s.model_id = am.model_id
+          LEFT JOIN (SELECT model_id, count(*) AS n FROM invocations WHERE arena_id=$1 GROUP BY model_id) i
+            ON i.model_id = am.model_id
+          WHERE am.arena_id=$1
+          ORDER BY am.model_id`
+	var rows []sinceInceptionRow
+	if err := r.conn.QueryRowsCtx(ctx, &rows, q, r.arena); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errNotImported
+	}
+
+	resp := &types.SinceInceptionResponse{SinceInceptionValues: make([]types.SinceInceptionValue, 0, len(rows)), ServerTime: time.Now().UnixMilli()}
+	for _, row := range rows {
+		resp.SinceInceptionValues = append(resp.SinceInceptionValues, types.SinceInceptionValue{
+			Id:                row.ModelId,
+			ModelId:           row.ModelId,
+			NavSinceInception: row.NavSinceInception,
+			InceptionDate:     float64(row.InceptionTsMs) / 1000,
+			NumInvocations:    row.NumInvocations,
+		})
+	}
+	return resp, nil
+}
+
+// ================= Leaderboard =================
+
+type leaderboardRow struct {
+	ModelId     string  `db:"model_id"`
+	Equity      float64 `db:"equity"`
+	Sharpe      float64 `db:"sharpe"`
+	NumTrades   int     `db:"num_trades"`
+	NumWins     int     `db:"num_wins"`
+	NumLosses   int     `db:"num_losses"`
+	WinDollars  float64 `db:"win_dollars"`
+	LoseDollars float64 `db:"lose_dollars"`
+	ReturnPct   float64 `db:"return_pct"`
+}
+
+// LoadLeaderboard computes the arena leaderboard like v_leaderboard, from the
+// latest equity snapshots and closed trades, so ingested data shows without
+// waiting for a view refresh.
 func (r *DBRepo) LoadLeaderboard() (*types.LeaderboardResponse, error) {
-	return r.fallback.LoadLeaderboard()
+	return load(r, "leaderboard", cache.LeaderboardCacheKey(r.arena), r.ttls.Medium, r.queryLeaderboard, r.fallback.LoadLeaderboard)
 }
 
+func (r *DBRepo) queryLeaderboard(ctx context.Context) (*types.LeaderboardResponse, error) {
+	const q = `WITH last_eq AS (
+            SELECT DISTINCT ON (model_id) model_id, equity_usd, sharpe_ratio, cum_pnl_pct
+            FROM account_equity_snapshots
+            WHERE arena_id=$1
+            ORDER BY model_id, ts_ms DESC
+          ), closed AS (
+            SELECT model_id,
+                   count(*)                                                               AS num_trades,
+                   count(*) FILTER (WHERE realized_net_pnl > 0)                           AS num_wins,
+                   count(*) FILTER (WHERE realized_net_pnl < 0)                           AS num_losses,
+                   coalesce(sum(realized_net_pnl) FILTER (WHERE realized_net_pnl > 0), 0) AS win_dollars,
+                   coalesce(sum(realized_net_pnl) FILTER (WHERE realized_net_pnl < 0), 0) AS lose_dollars
+            FROM trades
+            WHERE arena_id=$1
+            GROUP BY model_id
+          )
+          SELECT am.model_id,
+                 coalesce(l.equity_usd, 0)::double precision   AS equity,
+                 coalesce(l.sharpe_ratio, 0)::double precision AS sharpe,
+                 coalesce(c.num_trades, 0)::int                AS num_trades,
+                 coalesce(c.num_wins, 0)::int                  AS num_wins,
+                 coalesce(c.num_losses, 0)::int                AS num_losses,
+                 coalesce(c.win_dollars, 0)::double precision  AS win_dollars,
+                 coalesce(c.lose_dollars, 0)::double precision AS lose_dollars,
+                 coalesce(l.cum_pnl_pct, 0)::double precision  AS return_pct
+          FROM arena_models am
+          LEFT JOIN last_eq l ON l.model_id = am.model_id
+          LEFT JOIN closed c ON c.model_id = am.model_id
+          WHERE am.arena_id=$1
+          ORDER BY equity DESC, am.model_id`
+	var rows []leaderboardRow
+	if err := r.conn.QueryRowsCtx(ctx, &rows, q, r.arena); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errNotImported
+	}
+
+	resp := &types.LeaderboardResponse{Leaderboard: make([]types.LeaderboardEntry, 0, len(rows))}
+	for _, row := range rows {
+		resp.Leaderboard = append(resp.Leaderboard, types.LeaderboardEntry{
+			Id:          row.ModelId,
+			NumTrades:   row.NumTrades,
+			Sharpe:      row.Sharpe,
+			WinDollars:  row.WinDollars,
+			NumLosses:   row.NumLosses,
+			LoseDollars: row.LoseDollars,
+			ReturnPct:   row.ReturnPct,
+			Equity:      row.Equity,
+			NumWins:     row.NumWins,
+		})
+	}
+	return resp, nil
+}
+
+// ================= Analytics =================
+
+type analyticsRow struct {
+	Payload string `db:"payload"`
+}
+
+// LoadAnalytics reads the model_analytics payloads of the arena.
 func (r *DBRepo) LoadAnalytics() (*types.AnalyticsResponse, error) {
-	return r.fallback.LoadAnalytics()
+	return load(r, "analytics", cache.ArenaAnalyticsKey(r.arena), r.ttls.Long, r.queryAnalytics, r.fallback.LoadAnalytics)
+}
+
+func (r *DBRepo) queryAnalytics(ctx context.Context) (*types.AnalyticsResponse, error) {
+	if err := r.imported(ctx); err != nil {
+		return nil, err
+	}
+	const q = `SELECT CAST(payload AS text) AS payload FROM model_analytics WHERE arena_id=$1 ORDER BY model_id`
+	var rows []analyticsRow
+	if err := r.conn.QueryRowsCtx(ctx, &rows, q, r.arena); err != nil {
+		return nil, err
+	}
+
+	resp := &types.AnalyticsResponse{Analytics: make([]types.ModelAnalytics, 0, len(rows)), ServerTime: time.Now().UnixMilli()}
+	for _, row := range rows {
+		var a types.ModelAnalytics
+		if err := json.Unmarshal([]byte(row.Payload), &a); err != nil {
+			return nil, fmt.Errorf("analytics payload: %w", err)
+		}
+		resp.Analytics = append(resp.Analytics, a)
+		resp.DataAsOf = max(resp.DataAsOf, data.ToMillis(a.UpdatedAt))
+	}
+	return resp, nil
 }
 
+// LoadModelAnalytics reads one model's payload; a model without one in an
+// imported arena is an errs.KindNotFound error.
 func (r *DBRepo) LoadModelAnalytics(modelId string) (*types.ModelAnalyticsResponse, error) {
 	if modelId == "" {
-		return nil, errors.New("modelId required")
+		return nil, errs.InvalidArgument("modelId required")
 	}
-	return r.fallback.LoadModelAnalytics(modelId)
+	query := func(ctx context.Context) (*types.ModelAnalyticsResponse, error) {
+		return r.queryModelAnalytics(ctx, modelId)
+	}
+	file := func() (*types.ModelAnalyticsResponse, error) { return r.fallback.LoadModelAnalytics(modelId) }
+	return load(r, "model_analytics", cache.AnalyticsKey(r.arena, modelId), r.ttls.Long, query, file)
 }
 
+func (r *DBRepo) queryModelAnalytics(ctx context.Context, modelId string) (*types.ModelAnalyticsResponse, error) {
+	if err := r.imported(ctx); err != nil {
+		return nil, err
+	}
+	const q = `SELECT CAST(payload AS text) AS payload FROM model_analytics WHERE arena_id=$1 AND model_id=$2`
+	var rows []analyticsRow
+	if err := r.conn.QueryRowsCtx(ctx, &rows, q, r.arena, modelId); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errs.NotFound("no analytics for model %s", modelId)
+	}
+
+	resp := &types.ModelAnalyticsResponse{ServerTime: time.Now().UnixMilli()}
+	if err := json.Unmarshal([]byte(rows[0].Payload), &resp.Analytics); err != nil {
+		return nil, fmt.Errorf("analytics payload: %w", err)
+	}
+	resp.DataAsOf = data.ToMillis(resp.Analytics.UpdatedAt)
+	return resp, nil
+}
+
+// ================= Positions =================
+
+type positionRow struct {
+	ModelId          string          `db:"model_id"`
+	Symbol           sql.NullString  `db:"symbol"` // NULL for a model without open positions
+	EntryPrice       sql.NullFloat64 `db:"entry_price"`
+	Quantity         sql.NullFloat64 `db:"quantity"`
+	Leverage         sql.NullFloat64 `db:"leverage"`
+	Confidence       sql.NullFloat64 `db:"confidence"`
+	EntryTsMs        sql.NullInt64   `db:"entry_ts_ms"`
+	CurrentPrice     sql.NullFloat64 `db:"current_price"`
+	LiquidationPrice sql.NullFloat64 `db:"liquidation_price"`
+	Commission       sql.NullFloat64 `db:"commission"`
+	Margin           sql.NullFloat64 `db:"margin"`
+	RiskUsd          sql.NullFloat64 `db:"risk_usd"`
+	ClosedPnl        sql.NullFloat64 `db:"closed_pnl"`
+	UnrealizedPnl    sql.NullFloat64 `db:"unrealized_pnl"`
+	Slippage         sql.NullFloat64 `db:"slippage"`
+	ExitPlan         sql.NullString  `db:"exit_plan"`
+	EntryOid         sql.NullInt64   `db:"entry_oid"`
+	TpOid            sql.NullInt64   `db:"tp_oid"`
+	SlOid            sql.NullInt64   `db:"sl_oid"`
+	Oid              sql.NullInt64   `db:"oid"`
+	WaitForFill      sql.NullBool    `db:"wait_for_fill"`
+	IndexCol         sql.NullString  `db:"index_col"`
+	ConversationId   sql.NullInt64   `db:"conversation_id"`
+}
+
+// LoadPositions reads the open positions of every model in the arena in the
+// shape of positions.json (entry_time in seconds).
 func (r *DBRepo) LoadPositions() (*types.PositionsResponse, error) {
-	return r.fallback.LoadPositions()
+	return load(r, "positions", cache.ArenaPositionsKey(r.arena), r.ttls.Short, r.queryPositions, r.fallback.LoadPositions)
+}
+
+func (r *DBRepo) queryPositions(ctx context.Context) (*types.PositionsResponse, error) {
+	const q = `SELECT ` + positionSelect + `
+          FROM arena_models am
+          LEFT JOIN positions p ON p.arena_id = am.arena_id AND p.model_id = am.model_id AND p.status IN ('open','reduced')
+          WHERE am.arena_id=$1
+          ORDER BY am.model_id, p.symbol`
+
+	var rows []positionRow
+	if err := r.conn.QueryRowsCtx(ctx, &rows, q, r.arena); err != nil {
+		return nil, err
+	}
+	byModel := groupPositions(rows)
+	if len(byModel) == 0 {
+		return nil, errNotImported
+	}
+	return &types.PositionsResponse{AccountTotals: byModel, ServerTime: time.Now().UnixMilli()}, nil
+}
+
+// positionSelect selects a positionRow from arena_models am joined to positions p.
+const positionSelect = `am.model_id, p.symbol, p.entry_price, p.quantity, p.leverage, p.confidence, p.entry_ts_ms,
+            p.current_price, p.liquidation_price, p.commission, p.margin, p.risk_usd, p.closed_pnl, p.unrealized_pnl,
+            p.slippage, CAST(p.exit_plan AS text) AS exit_plan, p.entry_oid, p.tp_oid, p.sl_oid, p.oid, p.wait_for_fill,
+            CAST(p.index_col AS text) AS index_col, p.conversation_id`
+
+// groupPositions folds rows ordered by model into one entry per model.
+func groupPositions(rows []positionRow) []types.PositionsByModel {
+	out := []types.PositionsByModel{}
+	for _, row := range rows {
+		n := len(out)
+		if n == 0 || out[n-1].ModelId != row.ModelId {
+			out = append(out, types.PositionsByModel{ModelId: row.ModelId, Positions: map[string]types.Position{}})
+			n++
+		}
+		if row.Symbol.Valid {
+			out[n-1].Positions[row.Symbol.String] = row.position()
+		}
+	}
+	return out
+}
+
+func (row positionRow) position() types.Position {
+	return types.Position{
+		EntryOid:         row.EntryOid.Int64,
+		RiskUsd:          row.RiskUsd.Float64,
+		Confidence:       row.Confidence.Float64,
+		IndexCol:         jsonValue(row.IndexCol),
+		ExitPlan:         jsonValue(row.ExitPlan),
+		EntryTime:        float64(row.EntryTsMs.Int64) / 1000,
+		Symbol:           row.Symbol.String,
+		EntryPrice:       row.EntryPrice.Float64,
+		TpOid:            row.TpOid.Int64,
+		Margin:           row.Margin.Float64,
+		WaitForFill:      row.WaitForFill.Bool,
+		SlOid:            row.SlOid.Int64,
+		Oid:              row.Oid.Int64,
+		CurrentPrice:     row.CurrentPrice.Float64,
+		ClosedPnl:        row.ClosedPnl.Float64,
+		LiquidationPrice: row.LiquidationPrice.Float64,
+		Commission:       row.Commission.Float64,
+		Leverage:         row.Leverage.Float64,
+		Slippage:         row.Slippage.Float64,
+		Quantity:         row.Quantity.Float64,
+		UnrealizedPnl:    row.UnrealizedPnl.Float64,
+		ConversationId:   row.ConversationId.Int64,
+	}
+}
+
+// jsonValue decodes a jsonb column read as text; NULL is nil.
+func jsonValue(s sql.NullString) interface{} {
+	if !s.Valid {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(s.String), &v); err != nil {
+		return nil
+	}
+	return v
 }
 
+// ================= History (?as_of=) =================
+
+// LoadRecordedAt reads the last price_ticks row of every symbol and the
+// positions whose status_history has them open or reduced at asOf (ms).
+// Arenas not imported have no history; query errors are logged and counted
+// as fallbacks so data.History approximates instead. Never cached.
+func (r *DBRepo) LoadRecordedAt(asOf int64) (*data.Recorded, error) {
+	defer metrics.ObserveLoad(metrics.SourceDB, "history", time.Now())
+	ctx := context.Background()
+	rec, err := r.queryRecordedAt(ctx, asOf)
+	switch {
+	case errors.Is(err, errNotImported):
+		return nil, nil
+	case err != nil:
+		logx.WithContext(ctx).Errorf("db history failed, falling back: %v", err)
+		metrics.DBFallbacks.Inc("history")
+		return nil, nil
+	}
+	return rec, nil
+}
+
+func (r *DBRepo) queryRecordedAt(ctx context.Context, asOf int64) (*data.Recorded, error) {
+	const pq = `SELECT ` + positionSelect + `
+          FROM arena_models am
+          LEFT JOIN positions p ON p.arena_id = am.arena_id AND p.model_id = am.model_id
+            AND p.entry_ts_ms <= $2 AND position_status_at(p.status_history, $2) IN ('open','reduced')
+          WHERE am.arena_id=$1
+          ORDER BY am.model_id, p.symbol`
+	var rows []positionRow
+	if err := r.conn.QueryRowsCtx(ctx, &rows, pq, r.arena, asOf); err != nil {
+		return nil, err
+	}
+	rec := &data.Recorded{AsOf: asOf, Prices: map[string]types.CryptoPrice{}, Positions: groupPositions(rows)}
+	if len(rec.Positions) == 0 {
+		return nil, errNotImported
+	}
+
+	const q = `SELECT DISTINCT ON (symbol) symbol, price, ts_ms AS timestamp_ms
+          FROM price_ticks
+          WHERE ts_ms <= $1
+          ORDER BY symbol, ts_ms DESC`
+	var ticks []cryptoRow
+	if err := r.conn.QueryRowsCtx(ctx, &ticks, q, asOf); err != nil {
+		return nil, err
+	}
+	for _, row := range ticks {
+		rec.Prices[row.Symbol] = types.CryptoPrice{Symbol: row.Symbol, Price: row.Price, Timestamp: row.Timestamp}
+	}
+	return rec, nil
+}
+
+// ================= Conversations =================
+
+type conversationRow struct {
+	Id      int64         `db:"id"`
+	ModelId string        `db:"model_id"`
+	Role    string        `db:"role"`
+	Content string        `db:"content"`
+	TsMs    sql.NullInt64 `db:"ts_ms"`
+}
+
+// LoadConversations reads the arena's conversations in insertion (import)
+// order with their messages; message timestamps are in seconds.
 func (r *DBRepo) LoadConversations() (*types.ConversationsResponse, error) {
-	return r.fallback.LoadConversations()
+	return load(r, "conversations", cache.ConversationsKey(r.arena), r.ttls.Medium, r.queryConversations, r.fallback.LoadConversations)
+}
+
+func (r *DBRepo) queryConversations(ctx context.Context) (*types.ConversationsResponse, error) {
+	if err := r.imported(ctx); err != nil {
+		return nil, err
+	}
+	const q = `SELECT c.id, c.model_id, m.role, m.content, m.ts_ms
+          FROM conversations c
+          JOIN conversation_messages m ON m.conversation_id = c.id
+          WHERE c.arena_id=$1
+          ORDER BY c.id, m.id`
+	var rows []conversationRow
+	if err := r.conn.QueryRowsCtx(ctx, &rows, q, r.arena); err != nil {
+		return nil, err
+	}
+
+	resp := &types.ConversationsResponse{Conversations: []types.Conversation{}, ServerTime: time.Now().UnixMilli()}
+	var last int64
+	for _, row := range rows {
+		if len(resp.Conversations) == 0 || row.Id != last {
+			resp.Conversations = append(resp.Conversations, types.Conversation{ModelId: row.ModelId})
+			last = row.Id
+		}
+		msg := types.ConversationMessage{Role: row.Role, Content: row.Content}
+		if row.TsMs.Int64 > 0 {
+			msg.Timestamp = float64(row.TsMs.Int64) / 1000
+			resp.DataAsOf = max(resp.DataAsOf, row.TsMs.Int64)
+		}
+		c := &resp.Conversations[len(resp.Conversations)-1]
+		c.Messages = append(c.Messages, msg)
+	}
+	return resp, nil
 }
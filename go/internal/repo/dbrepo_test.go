@@ -0,0 +1,217 @@
+package repo
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/types"
+)
+
+const testDataPath = "../../../mcp/data"
+
+var positionColumns = []string{"model_id", "symbol", "entry_price", "quantity", "leverage", "confidence", "entry_ts_ms",
+	"current_price", "liquidation_price", "commission", "margin", "risk_usd", "closed_pnl", "unrealized_pnl", "slippage",
+	"exit_plan", "entry_oid", "tp_oid", "sl_oid", "oid", "wait_for_fill", "index_col", "conversation_id"}
+
+// positionValues encodes p the way cmd/importer stores it: zero values as
+// NULL, jsonb as text, entry time in ms.
+func positionValues(t *testing.T, modelId string, p types.Position) []driver.Value {
+	orNull := func(f float64) driver.Value {
+		if f == 0 {
+			return nil
+		}
+		return f
+	}
+	idOrNull := func(id int64) driver.Value {
+		if id == 0 {
+			return nil
+		}
+		return id
+	}
+	jsonOrNull := func(v interface{}) driver.Value {
+		if v == nil {
+			return nil
+		}
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return string(b)
+	}
+	return []driver.Value{modelId, p.Symbol, p.EntryPrice, p.Quantity, orNull(p.Leverage), orNull(p.Confidence), int64(p.EntryTime * 1000),
+		orNull(p.CurrentPrice), orNull(p.LiquidationPrice), orNull(p.Commission), orNull(p.Margin), orNull(p.RiskUsd),
+		orNull(p.ClosedPnl), orNull(p.UnrealizedPnl), orNull(p.Slippage), jsonOrNull(p.ExitPlan),
+		idOrNull(p.EntryOid), idOrNull(p.TpOid), idOrNull(p.SlOid), idOrNull(p.Oid), p.WaitForFill, jsonOrNull(p.IndexCol), nil}
+}
+
+func TestDBRepoLoadPositionsRoundTrip(t *testing.T) {
+	fallback := data.NewDataLoader(testDataPath)
+	want, err := fallback.LoadPositions()
+	require.NoError(t, err)
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows(positionColumns)
+	for _, pm := range want.AccountTotals {
+		if len(pm.Positions) == 0 {
+			empty := make([]driver.Value, len(positionColumns))
+			empty[0] = pm.ModelId
+			rows.AddRow(empty...)
+		}
+		for _, p := range pm.Positions {
+			rows.AddRow(positionValues(t, pm.ModelId, p)...)
+		}
+	}
+	mock.ExpectQuery("FROM arena_models").WithArgs("season-2").WillReturnRows(rows)
+
+	r := NewDBRepo(sqlx.NewSqlConnFromDB(db), nil, fallback, TTLs{}).ForArena("season-2", fallback)
+	got, err := r.LoadPositions()
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	byModel := func(resp *types.PositionsResponse) map[string]map[string]types.Position {
+		out := map[string]map[string]types.Position{}
+		for _, pm := range resp.AccountTotals {
+			out[pm.ModelId] = pm.Positions
+		}
+		return out
+	}
+	assert.Equal(t, byModel(want), byModel(got), "Positions should round-trip through the DB")
+}
+
+func TestDBRepoLoadPositionsFallsBack(t *testing.T) {
+	fallback := data.NewDataLoader(testDataPath)
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("FROM arena_models").WillReturnError(assert.AnError)
+	mock.ExpectQuery("FROM arena_models").WillReturnRows(sqlmock.NewRows(positionColumns))
+
+	r := NewDBRepo(sqlx.NewSqlConnFromDB(db), nil, fallback, TTLs{})
+	got, err := r.LoadPositions()
+	require.NoError(t, err)
+	assert.NotEmpty(t, got.AccountTotals, "File data should be served when the query fails")
+	got, err = r.LoadPositions()
+	require.NoError(t, err)
+	assert.NotEmpty(t, got.AccountTotals, "File data should be served for arenas not imported")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBRepoLoadTrades(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery(`SELECT count\(\*\) FROM arena_models`).WithArgs("season-2").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery("FROM trades").WithArgs("season-2").WillReturnRows(sqlmock.NewRows([]string{
+		"id", "model_id", "symbol", "side", "trade_type", "quantity", "leverage", "confidence", "entry_price", "entry_ts_ms",
+		"exit_price", "exit_ts_ms", "realized_gross_pnl", "realized_net_pnl", "total_commission_dollars", "entry_oid",
+		"exit_oid", "conversation_id",
+	}).AddRow("t1", "gpt-5", "BTC", "long", nil, 0.5, 10.0, nil, 100000.0, int64(1760000000000),
+		101000.0, int64(1760003600500), 500.0, 480.0, 20.0, nil, nil, int64(3)))
+
+	fallback := data.NewDataLoader(testDataPath)
+	r := NewDBRepo(sqlx.NewSqlConnFromDB(db), nil, fallback, TTLs{}).ForArena("season-2", fallback)
+	got, err := r.LoadTrades()
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Len(t, got.Trades, 1)
+	assert.Equal(t, types.Trade{
+		Id: "t1", ModelId: "gpt-5", Symbol: "BTC", Side: "long", Quantity: 0.5, Leverage: 10, EntryPrice: 100000,
+		EntryTime: 1760000000, ExitPrice: 101000, ExitTime: 1760003600.5, RealizedGrossPnl: 500, RealizedNetPnl: 480,
+		TotalCommissionDollars: 20, ConversationId: 3,
+	}, got.Trades[0])
+	assert.Equal(t, int64(1760003600500), got.DataAsOf)
+}
+
+func TestDBRepoFallsBackForArenasNotImported(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery(`SELECT count\(\*\) FROM arena_models`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	}
+
+	fallback := data.NewDataLoader(testDataPath)
+	r := NewDBRepo(sqlx.NewSqlConnFromDB(db), nil, fallback, TTLs{})
+
+	trades, err := r.LoadTrades()
+	require.NoError(t, err)
+	wantTrades, err := fallback.LoadTrades()
+	require.NoError(t, err)
+	assert.Equal(t, len(wantTrades.Trades), len(trades.Trades))
+
+	convs, err := r.LoadConversations()
+	require.NoError(t, err)
+	wantConvs, err := fallback.LoadConversations()
+	require.NoError(t, err)
+	assert.Equal(t, len(wantConvs.Conversations), len(convs.Conversations))
+
+	analytics, err := r.LoadAnalytics()
+	require.NoError(t, err)
+	wantAnalytics, err := fallback.LoadAnalytics()
+	require.NoError(t, err)
+	assert.Equal(t, len(wantAnalytics.Analytics), len(analytics.Analytics))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBRepoLoadModelAnalyticsNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery(`SELECT count\(\*\) FROM arena_models`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery("FROM model_analytics").WithArgs(data.DefaultArena, "gpt-5").WillReturnRows(sqlmock.NewRows([]string{"payload"}))
+
+	fallback := data.NewDataLoader(testDataPath)
+	_, err = NewDBRepo(sqlx.NewSqlConnFromDB(db), nil, fallback, TTLs{}).LoadModelAnalytics("gpt-5")
+	assert.True(t, errs.Is(err, errs.KindNotFound), "An imported arena without the model's analytics should not fall back: %v", err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBRepoLoadRecordedAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	btc := types.Position{Symbol: "BTC", EntryOid: 7, EntryTime: 1760000000, EntryPrice: 100000, Quantity: 0.5}
+	mock.ExpectQuery(`position_status_at\(p.status_history, \$2\)`).WithArgs("season-2", int64(1760003600000)).
+		WillReturnRows(sqlmock.NewRows(positionColumns).AddRow(positionValues(t, "gpt-5", btc)...))
+	mock.ExpectQuery("FROM price_ticks").WithArgs(int64(1760003600000)).
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "price", "timestamp_ms"}).AddRow("BTC", 101000.0, int64(1760003500000)))
+
+	fallback := data.NewDataLoader(testDataPath)
+	r := NewDBRepo(sqlx.NewSqlConnFromDB(db), nil, fallback, TTLs{}).ForArena("season-2", fallback)
+	got, err := r.LoadRecordedAt(1760003600000)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	require.Len(t, got.Positions, 1)
+	assert.Equal(t, btc, got.Positions[0].Positions["BTC"])
+	assert.Equal(t, types.CryptoPrice{Symbol: "BTC", Price: 101000, Timestamp: 1760003500000}, got.Prices["BTC"])
+}
+
+func TestDBRepoLoadRecordedAtNotImported(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("FROM arena_models").WillReturnRows(sqlmock.NewRows(positionColumns))
+	mock.ExpectQuery("FROM arena_models").WillReturnError(assert.AnError)
+
+	fallback := data.NewDataLoader(testDataPath)
+	r := NewDBRepo(sqlx.NewSqlConnFromDB(db), nil, fallback, TTLs{})
+	got, err := r.LoadRecordedAt(1)
+	require.NoError(t, err)
+	assert.Nil(t, got, "Arenas not imported have no recorded history")
+	got, err = r.LoadRecordedAt(1)
+	require.NoError(t, err)
+	assert.Nil(t, got, "Query errors fall back to the approximation")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zeromicro/go-zero/core/stores/sqlx"
+
+	"nof0-api/internal/types"
+)
+
+// InvocationStats aggregates the invocations table in Postgres.
+type InvocationStats struct {
+	conn sqlx.SqlConn
+}
+
+func NewInvocationStats(conn sqlx.SqlConn) *InvocationStats {
+	return &InvocationStats{conn: conn}
+}
+
+type invocationDayRow struct {
+	Day                    string  `db:"day"`
+	ModelId                string  `db:"model_id"`
+	Provider               string  `db:"provider"`
+	Invocations            int     `db:"invocations"`
+	Errors                 int     `db:"errors"`
+	InputTokens            int     `db:"input_tokens"`
+	OutputTokens           int     `db:"output_tokens"`
+	CostUsd                float64 `db:"cost_usd"`
+	AvgLatencyMs           float64 `db:"avg_latency_ms"`
+	P95LatencyMs           float64 `db:"p95_latency_ms"`
+	MaxLatencyMs           int64   `db:"max_latency_ms"`
+	AvgInvocationBreakMins float64 `db:"avg_invocation_break_mins"`
+	Estimated              bool    `db:"estimated"`
+}
+
+// Daily returns per model per UTC day totals for arenaId, ordered by day then
+// model. modelId, from and to (epoch ms, inclusive) filter when set.
+func (s *InvocationStats) Daily(ctx context.Context, arenaId, modelId string, from, to int64) ([]types.InvocationDailyStats, error) {
+	args := []interface{}{arenaId}
+	where := []string{"arena_id = $1"}
+	if modelId != "" {
+		args = append(args, modelId)
+		where = append(where, fmt.Sprintf("model_id = $%d", len(args)))
+	}
+	if from > 0 {
+		args = append(args, from)
+		where = append(where, fmt.Sprintf("ts_ms >= $%d", len(args)))
+	}
+	if to > 0 {
+		args = append(args, to)
+		where = append(where, fmt.Sprintf("ts_ms <= $%d", len(args)))
+	}
+
+	q := `WITH inv AS (
+            SELECT *, to_char(to_timestamp(ts_ms / 1000.0) AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day
+            FROM invocations WHERE ` + strings.Join(where, " AND ") + `
+          ), gaps AS (
+            SELECT *, ts_ms - lag(ts_ms) OVER (PARTITION BY model_id, day ORDER BY ts_ms) AS gap_ms
+            FROM inv
+          )
+          SELECT day, model_id, coalesce(max(provider), '') AS provider,
+                 count(*) AS invocations,
+                 count(*) FILTER (WHERE status <> 'ok') AS errors,
+                 coalesce(sum(input_tokens), 0) AS input_tokens,
+                 coalesce(sum(output_tokens), 0) AS output_tokens,
+                 round(coalesce(sum(cost_usd), 0)::numeric, 6)::double precision AS cost_usd,
+                 coalesce(avg(latency_ms), 0)::double precision AS avg_latency_ms,
+                 coalesce(percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms), 0)::double precision AS p95_latency_ms,
+                 coalesce(max(latency_ms), 0) AS max_latency_ms,
+                 coalesce(avg(gap_ms) / 60000.0, 0)::double precision AS avg_invocation_break_mins,
+                 bool_or(estimated) AS estimated
+          FROM gaps
+          GROUP BY day, model_id
+          ORDER BY day, model_id`
+
+	var rows []invocationDayRow
+	if err := s.conn.QueryRowsCtx(ctx, &rows, q, args...); err != nil {
+		return nil, err
+	}
+	out := make([]types.InvocationDailyStats, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, types.InvocationDailyStats(r))
+	}
+	return out, nil
+}
@@ -0,0 +1,260 @@
+package snapshot
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"nof0-api/internal/data"
+)
+
+// capture is one DataSource response and the file it is stored in.
+type capture struct {
+	file string
+	load func() (any, error)
+}
+
+// captures lists what is recorded for an arena. serverTime and dataAsOf are
+// cleared where the loader stamps them on read (dataAsOf may be a file time),
+// so unchanged data records nothing new.
+func captures(dl *data.DataLoader) []capture {
+	cs := []capture{
+		{"crypto-prices.json", func() (any, error) {
+			r, err := dl.LoadCryptoPrices()
+			if err == nil {
+				r.DataAsOf = 0
+			}
+			return r, err
+		}},
+		{"account-totals.json", func() (any, error) {
+			r, err := dl.LoadAccountTotals()
+			if err == nil {
+				r.ServerTime, r.DataAsOf = 0, 0
+			}
+			return r, err
+		}},
+		{"trades.json", func() (any, error) {
+			r, err := dl.LoadTrades()
+			if err == nil {
+				r.ServerTime, r.DataAsOf = 0, 0
+			}
+			return r, err
+		}},
+		{"since-inception-values.json", func() (any, error) {
+			r, err := dl.LoadSinceInception()
+			if err == nil {
+				r.ServerTime, r.DataAsOf = 0, 0
+			}
+			return r, err
+		}},
+		{"leaderboard.json", func() (any, error) {
+			r, err := dl.LoadLeaderboard()
+			if err == nil {
+				r.DataAsOf = 0
+			}
+			return r, err
+		}},
+		{"analytics.json", func() (any, error) {
+			r, err := dl.LoadAnalytics()
+			if err == nil {
+				r.ServerTime, r.DataAsOf = 0, 0
+			}
+			return r, err
+		}},
+		{"positions.json", func() (any, error) {
+			r, err := dl.LoadPositions()
+			if err == nil {
+				r.ServerTime, r.DataAsOf = 0, 0
+			}
+			return r, err
+		}},
+		{"conversations.json", func() (any, error) {
+			r, err := dl.LoadConversations()
+			if err == nil {
+				r.ServerTime, r.DataAsOf = 0, 0
+			}
+			return r, err
+		}},
+		{"invocations.json", func() (any, error) {
+			invs, err := dl.LoadInvocations()
+			return map[string]any{"invocations": invs}, err
+		}},
+	}
+	if board, err := dl.LoadLeaderboard(); err == nil {
+		for _, e := range board.Leaderboard {
+			modelId := e.Id
+			if filepath.Base(modelId) != modelId {
+				continue
+			}
+			cs = append(cs, capture{"analytics-" + modelId + ".json", func() (any, error) {
+				r, err := dl.LoadModelAnalytics(modelId)
+				if err == nil {
+					r.ServerTime, r.DataAsOf = 0, 0
+				}
+				return r, err
+			}})
+		}
+	}
+	return cs
+}
+
+// Record captures every arena whose data changed since its latest snapshot,
+// then applies retention. It is the JobRecord job.
+func (s *Store) Record(ctx context.Context) (string, error) {
+	var recorded, unchanged, pruned int
+	var failures []error
+	for _, arena := range s.arenas.Ids() {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		dl, _ := s.arenas.Loader(arena)
+		ok, err := s.record(arena, dl)
+		switch {
+		case err != nil:
+			failures = append(failures, fmt.Errorf("arena %s: %w", arena, err))
+		case ok:
+			recorded++
+		default:
+			unchanged++
+		}
+		n, err := s.prune(arena)
+		pruned += n
+		if err != nil {
+			failures = append(failures, fmt.Errorf("arena %s: prune: %w", arena, err))
+		}
+	}
+	summary := fmt.Sprintf("recorded %d arenas, %d unchanged, pruned %d snapshots", recorded, unchanged, pruned)
+	if len(failures) > 0 {
+		return "", fmt.Errorf("%s: %w", summary, errors.Join(failures...))
+	}
+	return summary, nil
+}
+
+// record writes one snapshot of dl unless it equals the latest one.
+func (s *Store) record(arena string, dl *data.DataLoader) (bool, error) {
+	now := s.now().UTC()
+	m := manifest{Id: now.Format(idLayout), Arena: arena, CreatedAt: now.UnixMilli(), Files: map[string]string{}}
+	bodies := map[string][]byte{}
+	for _, c := range captures(dl) {
+		v, err := c.load()
+		if err != nil {
+			// Arenas need not have every file; missing ones stay missing.
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return false, err
+		}
+		sum := sha256.Sum256(b)
+		m.Files[c.file] = hex.EncodeToString(sum[:])
+		bodies[c.file] = b
+	}
+	if len(bodies) == 0 {
+		return false, errors.New("no data to record")
+	}
+	m.Hash = hashFiles(m.Files)
+
+	existing, err := s.manifests(arena)
+	if err != nil {
+		return false, err
+	}
+	if len(existing) > 0 && (existing[0].Hash == m.Hash || existing[0].Id >= m.Id) {
+		return false, nil
+	}
+
+	// Write to a hidden directory and rename, so readers never see a
+	// partial snapshot.
+	dir := filepath.Join(s.root, arena, m.Id)
+	tmp := filepath.Join(s.root, arena, ".tmp-"+m.Id)
+	if err := os.MkdirAll(tmp, 0o755); err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(tmp)
+	for name, b := range bodies {
+		n, err := writeGzip(filepath.Join(tmp, name+".gz"), b)
+		if err != nil {
+			return false, err
+		}
+		m.Bytes += n
+	}
+	mb, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, manifestFile), mb, 0o644); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp, dir)
+}
+
+// prune removes snapshots beyond the newest keep and older than maxAge; the
+// newest snapshot is always kept.
+func (s *Store) prune(arena string) (int, error) {
+	ms, err := s.manifests(arena)
+	if err != nil {
+		return 0, err
+	}
+	cutoff := int64(0)
+	if s.maxAge > 0 {
+		cutoff = s.now().Add(-s.maxAge).UnixMilli()
+	}
+	removed := 0
+	for i, m := range ms {
+		if i == 0 || ((s.keep <= 0 || i < s.keep) && m.CreatedAt >= cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(s.root, arena, m.Id)); err != nil {
+			return removed, err
+		}
+		s.mu.Lock()
+		delete(s.loaders, arena+"/"+m.Id)
+		s.mu.Unlock()
+		removed++
+	}
+	return removed, nil
+}
+
+func hashFiles(files map[string]string) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name + "=" + files[name] + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeGzip writes b compressed to path and returns the compressed size.
+func writeGzip(path string, b []byte) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	zw := gzip.NewWriter(f)
+	if _, err := zw.Write(b); err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return 0, err
+	}
+	info, err := f.Stat()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,144 @@
+// Package snapshot archives versions of every arena's data. The recorder
+// captures each DataSource response into Path/<arena>/<id>/ as gzipped files
+// in the DataLoader layout (trades.json.gz, ...), so a snapshot is served by
+// a plain DataLoader; ?snapshot=<id> selects one for any endpoint.
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nof0-api/internal/data"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/types"
+)
+
+// JobRecord is the scheduled job running Store.Record.
+const JobRecord = "snapshot"
+
+// idLayout names snapshot directories; ids sort by time.
+const idLayout = "20060102T150405Z"
+
+const manifestFile = "manifest.json"
+
+var ErrNotFound = errs.NotFound("snapshot not found")
+
+// manifest describes a recorded snapshot.
+type manifest struct {
+	Id        string            `json:"id"`
+	Arena     string            `json:"arena"`
+	CreatedAt int64             `json:"created_at"`
+	Hash      string            `json:"hash"`  // over every file's content
+	Files     map[string]string `json:"files"` // name -> sha256 of the JSON
+	Bytes     int64             `json:"bytes"` // compressed size
+}
+
+func (m manifest) info() types.SnapshotInfo {
+	files := make([]string, 0, len(m.Files))
+	for f := range m.Files {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return types.SnapshotInfo{Id: m.Id, Arena: m.Arena, CreatedAt: m.CreatedAt, Files: files, Bytes: m.Bytes}
+}
+
+// Store records snapshots under root and serves them.
+type Store struct {
+	root   string
+	arenas *data.ArenaSet
+	keep   int
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	loaders map[string]*data.DataLoader // by arena/id
+}
+
+// NewStore returns a store recording arenas under root, keeping at most keep
+// snapshots per arena no older than maxAge (zero disables either rule).
+func NewStore(root string, arenas *data.ArenaSet, keep int, maxAge time.Duration) *Store {
+	return &Store{root: root, arenas: arenas, keep: keep, maxAge: maxAge, now: time.Now,
+		loaders: map[string]*data.DataLoader{}}
+}
+
+// List returns arena's snapshots, newest first.
+func (s *Store) List(arena string) ([]types.SnapshotInfo, error) {
+	ms, err := s.manifests(arena)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]types.SnapshotInfo, 0, len(ms))
+	for _, m := range ms {
+		out = append(out, m.info())
+	}
+	return out, nil
+}
+
+// Loader returns a loader reading snapshot id of arena.
+func (s *Store) Loader(arena, id string) (*data.DataLoader, error) {
+	if _, err := time.Parse(idLayout, id); err != nil {
+		return nil, ErrNotFound
+	}
+	dir := filepath.Join(s.root, arena, id)
+	if _, err := os.Stat(filepath.Join(dir, manifestFile)); err != nil {
+		return nil, ErrNotFound
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := arena + "/" + id
+	dl, ok := s.loaders[key]
+	if !ok {
+		// Snapshots never change, so the loader's memos stay valid.
+		dl = data.NewDataLoader(dir)
+		s.loaders[key] = dl
+	}
+	return dl, nil
+}
+
+// manifests reads arena's snapshots, newest first; unreadable ones are
+// skipped.
+func (s *Store) manifests(arena string) ([]manifest, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, arena))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []manifest
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(s.root, arena, e.Name(), manifestFile))
+		if err != nil {
+			continue
+		}
+		var m manifest
+		if json.Unmarshal(b, &m) == nil {
+			out = append(out, m)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Id > out[j].Id })
+	return out, nil
+}
+
+type snapshotCtxKey struct{}
+
+// WithId stores the requested snapshot id in ctx.
+func WithId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, snapshotCtxKey{}, id)
+}
+
+// FromContext returns the snapshot id stored by WithId, or "".
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(snapshotCtxKey{}).(string)
+	return id
+}
@@ -0,0 +1,113 @@
+package snapshot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/data"
+)
+
+const testDataPath = "../../../mcp/data"
+
+// newArena copies a few fixture files into a fresh data root.
+func newArena(t *testing.T) string {
+	root := t.TempDir()
+	for _, f := range []string{"trades.json", "leaderboard.json", "crypto-prices.json", "analytics.json"} {
+		b, err := os.ReadFile(filepath.Join(testDataPath, f))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(root, f), b, 0o644))
+	}
+	return root
+}
+
+func TestRecordListAndLoad(t *testing.T) {
+	root := newArena(t)
+	arenas, err := data.NewArenaSet(root, "")
+	require.NoError(t, err)
+	store := NewStore(t.TempDir(), arenas, 0, 0)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	summary, err := store.Record(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "recorded 1 arenas, 0 unchanged, pruned 0 snapshots", summary)
+
+	now = now.Add(time.Minute)
+	summary, err = store.Record(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "recorded 0 arenas, 1 unchanged, pruned 0 snapshots", summary, "Unchanged data records nothing")
+
+	list, err := store.List(data.DefaultArena)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "20261019T120000Z", list[0].Id)
+	assert.Contains(t, list[0].Files, "trades.json")
+	assert.Contains(t, list[0].Files, "analytics-gpt-5.json")
+	assert.NotContains(t, list[0].Files, "positions.json", "Missing files stay missing")
+	assert.Positive(t, list[0].Bytes)
+
+	// Change the live data; the snapshot keeps the old version.
+	live := data.NewDataLoader(root)
+	before, err := live.LoadTrades()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, "trades.json"), []byte(`{"trades":[]}`), 0o644))
+	now = now.Add(time.Minute)
+	_, err = store.Record(ctx)
+	require.NoError(t, err)
+
+	list, err = store.List(data.DefaultArena)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "20261019T120200Z", list[0].Id, "Newest first")
+
+	old, err := store.Loader(data.DefaultArena, "20261019T120000Z")
+	require.NoError(t, err)
+	trades, err := old.LoadTrades()
+	require.NoError(t, err)
+	assert.Equal(t, before.Trades, trades.Trades)
+	analytics, err := old.LoadModelAnalytics("gpt-5")
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-5", analytics.Analytics.ModelId)
+
+	_, err = store.Loader(data.DefaultArena, "20200101T000000Z")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Loader(data.DefaultArena, "../"+list[0].Id)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRetention(t *testing.T) {
+	root := newArena(t)
+	dir := t.TempDir()
+	arenas, err := data.NewArenaSet(root, "")
+	require.NoError(t, err)
+	store := NewStore(dir, arenas, 2, 24*time.Hour)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	for i := range 4 {
+		body := []byte(`{"leaderboard":[{"id":"gpt-5","return_pct":` + string(rune('1'+i)) + `}]}`)
+		require.NoError(t, os.WriteFile(filepath.Join(root, "leaderboard.json"), body, 0o644))
+		_, err := store.Record(context.Background())
+		require.NoError(t, err)
+		now = now.Add(time.Hour)
+	}
+	list, err := store.List(data.DefaultArena)
+	require.NoError(t, err)
+	require.Len(t, list, 2, "Keep bounds the count")
+	assert.Equal(t, "20261019T150000Z", list[0].Id)
+
+	now = now.Add(48 * time.Hour)
+	n, err := store.prune(data.DefaultArena)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "MaxAge removes old snapshots but never the newest")
+	entries, err := os.ReadDir(filepath.Join(dir, data.DefaultArena))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -1,17 +1,84 @@
 package svc
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
 	_ "github.com/jackc/pgx/v5/stdlib" // register pgx driver
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
+	"github.com/zeromicro/go-zero/rest"
 
+	"nof0-api/internal/auth"
+	"nof0-api/internal/cache"
 	"nof0-api/internal/config"
 	"nof0-api/internal/data"
+	"nof0-api/internal/events"
+	"nof0-api/internal/health"
+	"nof0-api/internal/ingest"
+	"nof0-api/internal/jobs"
+	"nof0-api/internal/metrics"
+	"nof0-api/internal/middleware"
+	"nof0-api/internal/migrate"
 	"nof0-api/internal/model"
+	"nof0-api/internal/registry"
+	"nof0-api/internal/repo"
+	"nof0-api/internal/snapshot"
+	"nof0-api/internal/upstream"
 )
 
 type ServiceContext struct {
 	Config     config.Config
-	DataLoader *data.DataLoader
+	DataLoader *data.DataLoader // default arena; use Source(ctx) or Loader(ctx) in logic
+	Arenas     *data.ArenaSet
+	Arena      rest.Middleware
+	// DBRepo reads every resource from Postgres through the Redis
+	// cache when DSN provided; Source(ctx) scopes it to the request's arena.
+	DBRepo *repo.DBRepo
+
+	// IngesterAuth and AdminAuth require an API key with at least that role;
+	// ApiKeys manages the keys in Postgres (nil without DSN).
+	IngesterAuth rest.Middleware
+	AdminAuth    rest.Middleware
+	ApiKeys      *auth.KeyStore
+	// RateLimit wraps every route (the server installs it); ResponseCache
+	// caches the public GET routes.
+	RateLimit     rest.Middleware
+	ResponseCache rest.Middleware
+
+	// ModelRegistry holds model metadata; DB-backed when DSN provided, file otherwise.
+	ModelRegistry registry.Store
+
+	// ConversationSearch uses Postgres full-text search when DSN provided;
+	// nil means the in-memory index of the arena's data files is used.
+	ConversationSearch *repo.ConversationSearch
+	// Invocations aggregates recorded invocations when DSN provided; nil
+	// means invocations.json (or conversations) of the arena is used.
+	Invocations *repo.InvocationStats
+	// Jobs holds the scheduled jobs from config (none without a DSN),
+	// upstream_mirror and snapshot; the server starts and stops it.
+	Jobs *jobs.Scheduler
+
+	// Redis is set when Redis.Host is configured; Cache wraps it and is a
+	// no-op without it.
+	Redis *redis.Redis
+	Cache *cache.Cache
+	// Upstream mirrors Upstream.BaseURL into the DataPath/Upstream.Arena
+	// arena on the upstream_mirror job; nil when no BaseURL is configured.
+	Upstream *upstream.Source
+	// Snapshots records arena versions on the snapshot job and serves them
+	// for ?snapshot=; nil when Snapshots.Path is empty.
+	Snapshots *snapshot.Store
+	// Health checks the data files, Postgres and Redis for /healthz and
+	// /readyz.
+	Health *health.Checker
+	// Events fans out ingest change events; Ingest writes pushed data and is
+	// set when DSN provided.
+	Events *events.Bus
+	Ingest *ingest.Ingester
 
 	// Optional DB models (injected but unused by handlers/logic for now)
 	DBConn                      sqlx.SqlConn
@@ -29,13 +96,46 @@ type ServiceContext struct {
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
+	registryFile := registry.NewFileStore(c.Registry.File)
+	var mirror *upstream.Source
+	if c.Upstream.BaseURL != "" {
+		// Before scanning arenas, so the mirror is listed from the start.
+		mirror = upstream.New(c.Upstream, filepath.Join(c.DataPath, c.Upstream.Arena))
+		logx.Must(mirror.PrepareMirror(c.Upstream.Arena + " (mirror of " + c.Upstream.BaseURL + ")"))
+	}
+	arenas, err := data.NewArenaSet(c.DataPath, c.DefaultArena)
+	logx.Must(err)
+	defaultLoader, _ := arenas.Loader("")
 	svc := &ServiceContext{
-		Config:     c,
-		DataLoader: data.NewDataLoader(c.DataPath),
+		Config:        c,
+		DataLoader:    defaultLoader,
+		Arenas:        arenas,
+		ModelRegistry: registryFile,
+	}
+	if c.Snapshots.Path != "" {
+		svc.Snapshots = snapshot.NewStore(c.Snapshots.Path, arenas, c.Snapshots.Keep,
+			time.Duration(c.Snapshots.MaxAge)*24*time.Hour)
 	}
-	// Only inject DB models when DSN provided; business logic still uses DataLoader.
+	svc.Arena = middleware.NewArenaMiddleware(arenas, svc.Snapshots).Handle
+	if c.Redis.Host != "" {
+		svc.Redis = redis.MustNewRedis(c.Redis)
+	}
+	svc.Cache = cache.New(svc.Redis)
+	svc.Jobs = jobs.NewScheduler(svc.Redis)
+	svc.Events = events.NewBus(svc.Redis)
+	if mirror != nil {
+		svc.Upstream = mirror
+		logx.Must(svc.Jobs.Add(upstream.JobMirror, fmt.Sprintf("@every %ds", c.Upstream.Interval), time.Minute, mirror.Sync))
+	}
+	if svc.Snapshots != nil {
+		logx.Must(svc.Jobs.Add(snapshot.JobRecord, fmt.Sprintf("@every %ds", c.Snapshots.Interval), time.Minute, svc.Snapshots.Record))
+	}
+	// Only inject DB models when DSN provided; Source(ctx) reads through DBRepo.
 	if c.Postgres.DSN != "" {
 		conn := sqlx.NewSqlConn("pgx", c.Postgres.DSN)
+		if c.Postgres.AutoMigrate {
+			logx.Must(migrate.AutoUp(context.Background(), conn))
+		}
 		svc.DBConn = conn
 		svc.ModelsModel = model.NewModelsModel(conn)
 		svc.SymbolsModel = model.NewSymbolsModel(conn)
@@ -48,6 +148,93 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		svc.ModelAnalyticsModel = model.NewModelAnalyticsModel(conn)
 		svc.ConversationsModel = model.NewConversationsModel(conn)
 		svc.ConversationMessagesModel = model.NewConversationMessagesModel(conn)
+		svc.DBRepo = repo.NewDBRepo(conn, svc.Cache, defaultLoader, repo.TTLs{
+			Short: c.TTL.Short, Medium: c.TTL.Medium, Long: c.TTL.Long, Stale: c.TTL.Stale,
+		})
+		svc.ModelRegistry = registry.NewDBStore(conn, registryFile)
+		svc.ConversationSearch = repo.NewConversationSearch(conn)
+		svc.Invocations = repo.NewInvocationStats(conn)
+		svc.ApiKeys = auth.NewKeyStore(conn)
+		svc.Ingest = ingest.New(conn, svc.Cache, svc.Events, c.Ingest)
+		logx.Must(svc.Jobs.Configure(c.Jobs, jobs.Builtin(conn, svc.Cache)))
 	}
+	svc.Health = health.NewChecker(c.DataPath, svc.DBConn, svc.Redis)
+	metrics.SetDataFiles(arenas.FileTimes)
+	authenticator, err := auth.NewAuthenticator(c.Auth.Keys, svc.ApiKeys)
+	logx.Must(err)
+	svc.IngesterAuth = middleware.NewAuthMiddleware(authenticator, auth.RoleIngester).Handle
+	svc.AdminAuth = middleware.NewAuthMiddleware(authenticator, auth.RoleAdmin).Handle
+	rateLimit, err := middleware.NewRateLimitMiddleware(c.RateLimit, authenticator)
+	logx.Must(err)
+	svc.RateLimit = rateLimit.Handle
+	responseCache, err := middleware.NewResponseCacheMiddleware(c.ResponseCache)
+	logx.Must(err)
+	svc.ResponseCache = responseCache.Handle
 	return svc
 }
+
+// ArenaId returns the arena selected on ctx, or the default arena.
+func (s *ServiceContext) ArenaId(ctx context.Context) string {
+	if id := data.ArenaFromContext(ctx); id != "" {
+		return id
+	}
+	return s.Arenas.DefaultId()
+}
+
+// Loader returns the data loader for the arena and snapshot selected on ctx
+// (see middleware.ArenaMiddleware), falling back to the default arena.
+func (s *ServiceContext) Loader(ctx context.Context) *data.DataLoader {
+	if dl, ok := s.snapshotLoader(ctx); ok {
+		return dl
+	}
+	if dl, ok := s.Arenas.Loader(data.ArenaFromContext(ctx)); ok {
+		return dl
+	}
+	return s.DataLoader
+}
+
+func (s *ServiceContext) snapshotLoader(ctx context.Context) (*data.DataLoader, bool) {
+	if id := snapshot.FromContext(ctx); id != "" && s.Snapshots != nil {
+		if dl, err := s.Snapshots.Loader(s.ArenaId(ctx), id); err == nil {
+			return dl, true
+		}
+	}
+	return nil, false
+}
+
+// Source returns the data source for the arena and snapshot selected on ctx:
+// DBRepo for the arena, backed by its files, when DSN provided, otherwise
+// (and always for snapshots) Loader(ctx).
+func (s *ServiceContext) Source(ctx context.Context) data.DataSource {
+	if dl, ok := s.snapshotLoader(ctx); ok {
+		return dl
+	}
+	if s.DBRepo == nil {
+		return s.Loader(ctx)
+	}
+	return s.DBRepo.ForArena(s.ArenaId(ctx), s.Loader(ctx))
+}
+
+// FreshnessLimit is how old data of resource (a data.Resource* name) may be
+// before it is stale; 0 means it never is.
+func (s *ServiceContext) FreshnessLimit(resource string) time.Duration {
+	f := s.Config.Freshness
+	seconds := map[string]int{
+		data.ResourcePrices:         f.Prices,
+		data.ResourceAccountTotals:  f.AccountTotals,
+		data.ResourcePositions:      f.Positions,
+		data.ResourceTrades:         f.Trades,
+		data.ResourceSinceInception: f.SinceInception,
+		data.ResourceLeaderboard:    f.Leaderboard,
+		data.ResourceAnalytics:      f.Analytics,
+		data.ResourceConversations:  f.Conversations,
+	}[resource]
+	return time.Duration(max(seconds, 0)) * time.Second
+}
+
+// Stale reports whether data of resource last updated at dataAsOf (epoch ms)
+// is older than its FreshnessLimit. Unknown times are not stale.
+func (s *ServiceContext) Stale(resource string, dataAsOf int64) bool {
+	limit := s.FreshnessLimit(resource)
+	return limit > 0 && dataAsOf > 0 && time.Since(time.UnixMilli(dataAsOf)) > limit
+}
@@ -25,6 +25,7 @@ type Position struct {
 	Slippage         float64     `json:"slippage"`
 	Quantity         float64     `json:"quantity"`
 	UnrealizedPnl    float64     `json:"unrealized_pnl"`
+	ConversationId   int64       `json:"conversation_id,omitempty"`
 }
 
 type AccountTotal struct {
@@ -42,13 +43,18 @@ type AccountTotal struct {
 }
 
 type AccountTotalsRequest struct {
-	LastHourlyMarker int `form:"lastHourlyMarker,optional"`
+	LastHourlyMarker int   `form:"lastHourlyMarker,optional"`
+	AsOf             int64 `form:"as_of,optional"`
 }
 
 type AccountTotalsResponse struct {
-	AccountTotals        []AccountTotal `json:"accountTotals"`
-	LastHourlyMarkerRead int            `json:"lastHourlyMarkerRead"`
-	ServerTime           int64          `json:"serverTime"`
+	AccountTotals        []AccountTotal       `json:"accountTotals"`
+	LastHourlyMarkerRead int                  `json:"lastHourlyMarkerRead"`
+	ServerTime           int64                `json:"serverTime"`
+	AsOf                 int64                `json:"asOf,omitempty"`
+	DataAsOf             int64                `json:"dataAsOf,omitempty"`
+	Stale                bool                 `json:"stale"`
+	Models               map[string]ModelInfo `json:"models,omitempty"`
 }
 
 type AccountValue struct {
@@ -57,8 +63,11 @@ type AccountValue struct {
 }
 
 type AnalyticsResponse struct {
-	Analytics  []ModelAnalytics `json:"analytics"`
-	ServerTime int64            `json:"serverTime"`
+	Analytics  []ModelAnalytics     `json:"analytics"`
+	ServerTime int64                `json:"serverTime"`
+	DataAsOf   int64                `json:"dataAsOf,omitempty"`
+	Stale      bool                 `json:"stale"`
+	Models     map[string]ModelInfo `json:"models,omitempty"`
 }
 
 type BreakdownTable struct {
@@ -162,9 +171,16 @@ type CryptoPrice struct {
 	Timestamp int64   `json:"timestamp"`
 }
 
+type CryptoPricesRequest struct {
+	AsOf int64 `form:"as_of,optional"`
+}
+
 type CryptoPricesResponse struct {
 	Prices     map[string]CryptoPrice `json:"prices"`
 	ServerTime int64                  `json:"serverTime"`
+	AsOf       int64                  `json:"asOf,omitempty"`
+	DataAsOf   int64                  `json:"dataAsOf,omitempty"`
+	Stale      bool                   `json:"stale"`
 }
 
 type LeaderboardEntry struct {
@@ -179,8 +195,16 @@ type LeaderboardEntry struct {
 	NumWins     int     `json:"num_wins"`
 }
 
+type LeaderboardRequest struct {
+	AsOf int64 `form:"as_of,optional"`
+}
+
 type LeaderboardResponse struct {
-	Leaderboard []LeaderboardEntry `json:"leaderboard"`
+	Leaderboard []LeaderboardEntry   `json:"leaderboard"`
+	AsOf        int64                `json:"asOf,omitempty"`
+	DataAsOf    int64                `json:"dataAsOf,omitempty"`
+	Stale       bool                 `json:"stale"`
+	Models      map[string]ModelInfo `json:"models,omitempty"`
 }
 
 type ModelAnalytics struct {
@@ -200,8 +224,11 @@ type ModelAnalytics struct {
 }
 
 type ModelAnalyticsResponse struct {
-	Analytics  ModelAnalytics `json:"analytics"`
-	ServerTime int64          `json:"serverTime"`
+	Analytics  ModelAnalytics       `json:"analytics"`
+	ServerTime int64                `json:"serverTime"`
+	DataAsOf   int64                `json:"dataAsOf,omitempty"`
+	Stale      bool                 `json:"stale"`
+	Models     map[string]ModelInfo `json:"models,omitempty"`
 }
 
 type SinceInceptionValue struct {
@@ -212,9 +239,140 @@ type SinceInceptionValue struct {
 	ModelId           string  `json:"model_id"`
 }
 
+type ResourceFreshness struct {
+	Resource      string `json:"resource"`
+	DataAsOf      int64  `json:"dataAsOf,omitempty"`
+	AgeSeconds    int64  `json:"age_seconds"`
+	MaxAgeSeconds int64  `json:"max_age_seconds"`
+	Stale         bool   `json:"stale"`
+	Error         string `json:"error,omitempty"`
+}
+
+type StatusResponse struct {
+	Arena      string              `json:"arena"`
+	Stale      bool                `json:"stale"`
+	Resources  []ResourceFreshness `json:"resources"`
+	ServerTime int64               `json:"serverTime"`
+}
+
+type HealthCheck struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type HealthResponse struct {
+	Status     string        `json:"status"`
+	Checks     []HealthCheck `json:"checks"`
+	ServerTime int64         `json:"serverTime"`
+}
+
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type DiffRequest struct {
+	From      string  `form:"from,default=latest"`
+	To        string  `form:"to,default=live"`
+	Threshold float64 `form:"threshold,default=0.01"`
+	Format    string  `form:"format,options=json|text,default=json"`
+}
+
+type DiffPosition struct {
+	ModelId    string  `json:"model_id"`
+	Symbol     string  `json:"symbol"`
+	Side       string  `json:"side"`
+	Quantity   float64 `json:"quantity"`
+	EntryPrice float64 `json:"entry_price"`
+	Leverage   float64 `json:"leverage"`
+}
+
+type FieldDelta struct {
+	Field string  `json:"field"`
+	Old   float64 `json:"old"`
+	New   float64 `json:"new"`
+	Delta float64 `json:"delta"`
+}
+
+type PositionChange struct {
+	ModelId string       `json:"model_id"`
+	Symbol  string       `json:"symbol"`
+	Changes []FieldDelta `json:"changes"`
+}
+
+type RankChange struct {
+	ModelId      string  `json:"model_id"`
+	OldRank      int     `json:"old_rank"`
+	NewRank      int     `json:"new_rank"`
+	OldReturnPct float64 `json:"old_return_pct"`
+	NewReturnPct float64 `json:"new_return_pct"`
+}
+
+type AnalyticsDelta struct {
+	ModelId string  `json:"model_id"`
+	Metric  string  `json:"metric"`
+	Old     float64 `json:"old"`
+	New     float64 `json:"new"`
+	Delta   float64 `json:"delta"`
+}
+
+type DiffMessage struct {
+	ModelId   string      `json:"model_id"`
+	Role      string      `json:"role"`
+	Content   string      `json:"content"`
+	Timestamp interface{} `json:"timestamp,omitempty"`
+}
+
+type DiffSummary struct {
+	NewPositions      int `json:"new_positions"`
+	ClosedPositions   int `json:"closed_positions"`
+	ModifiedPositions int `json:"modified_positions"`
+	NewTrades         int `json:"new_trades"`
+	RankChanges       int `json:"rank_changes"`
+	AnalyticsDeltas   int `json:"analytics_deltas"`
+	NewMessages       int `json:"new_messages"`
+}
+
+type DiffResponse struct {
+	From              string           `json:"from"`
+	To                string           `json:"to"`
+	Threshold         float64          `json:"threshold"`
+	Summary           DiffSummary      `json:"summary"`
+	NewPositions      []DiffPosition   `json:"new_positions"`
+	ClosedPositions   []DiffPosition   `json:"closed_positions"`
+	ModifiedPositions []PositionChange `json:"modified_positions"`
+	NewTrades         []Trade          `json:"new_trades"`
+	RankChanges       []RankChange     `json:"rank_changes"`
+	AnalyticsDeltas   []AnalyticsDelta `json:"analytics_deltas"`
+	NewMessages       []DiffMessage    `json:"new_messages"`
+	ServerTime        int64            `json:"serverTime"`
+}
+
+type SnapshotInfo struct {
+	Id        string   `json:"id"`
+	Arena     string   `json:"arena"`
+	CreatedAt int64    `json:"created_at"`
+	Files     []string `json:"files"`
+	Bytes     int64    `json:"bytes"`
+}
+
+type SnapshotsResponse struct {
+	Snapshots  []SnapshotInfo `json:"snapshots"`
+	ServerTime int64          `json:"serverTime"`
+}
+
 type SinceInceptionResponse struct {
 	SinceInceptionValues []SinceInceptionValue `json:"sinceInceptionValues"`
 	ServerTime           int64                 `json:"serverTime"`
+	DataAsOf             int64                 `json:"dataAsOf,omitempty"`
+	Stale                bool                  `json:"stale"`
+	Models               map[string]ModelInfo  `json:"models,omitempty"`
 }
 
 type Trade struct {
@@ -251,15 +409,20 @@ type Trade struct {
 	RealizedGrossPnl       float64     `json:"realized_gross_pnl"`
 	RealizedNetPnl         float64     `json:"realized_net_pnl"`
 	TotalCommissionDollars float64     `json:"total_commission_dollars"`
+	ConversationId         int64       `json:"conversation_id,omitempty"`
 }
 
 type TradesResponse struct {
-	Trades     []Trade `json:"trades"`
-	ServerTime int64   `json:"serverTime"`
+	Trades     []Trade              `json:"trades"`
+	ServerTime int64                `json:"serverTime"`
+	DataAsOf   int64                `json:"dataAsOf,omitempty"`
+	Stale      bool                 `json:"stale"`
+	Models     map[string]ModelInfo `json:"models,omitempty"`
 }
 
 type PositionsRequest struct {
-	Limit int `form:"limit,optional,default=1000"`
+	Limit int   `form:"limit,optional,default=1000"`
+	AsOf  int64 `form:"as_of,optional"`
 }
 
 type PositionsByModel struct {
@@ -268,8 +431,12 @@ type PositionsByModel struct {
 }
 
 type PositionsResponse struct {
-	AccountTotals []PositionsByModel `json:"accountTotals"`
-	ServerTime    int64              `json:"serverTime"`
+	AccountTotals []PositionsByModel   `json:"accountTotals"`
+	ServerTime    int64                `json:"serverTime"`
+	AsOf          int64                `json:"asOf,omitempty"`
+	DataAsOf      int64                `json:"dataAsOf,omitempty"`
+	Stale         bool                 `json:"stale"`
+	Models        map[string]ModelInfo `json:"models,omitempty"`
 }
 
 type ConversationMessage struct {
@@ -279,11 +446,301 @@ type ConversationMessage struct {
 }
 
 type Conversation struct {
+	Id       int64                 `json:"id,omitempty"`
 	ModelId  string                `json:"model_id"`
 	Messages []ConversationMessage `json:"messages"`
+	TradeIds []string              `json:"trade_ids,omitempty"`
 }
 
 type ConversationsResponse struct {
-	Conversations []Conversation `json:"conversations"`
-	ServerTime    int64          `json:"serverTime"`
+	Conversations []Conversation       `json:"conversations"`
+	ServerTime    int64                `json:"serverTime"`
+	DataAsOf      int64                `json:"dataAsOf,omitempty"`
+	Stale         bool                 `json:"stale"`
+	Models        map[string]ModelInfo `json:"models,omitempty"`
+}
+
+type ConversationSearchRequest struct {
+	ModelId string `form:"model_id,optional"`
+	Role    string `form:"role,optional,options=system|user|assistant"`
+	From    int64  `form:"from,optional"`
+	To      int64  `form:"to,optional"`
+	Q       string `form:"q,optional"`
+	Limit   int    `form:"limit,optional,default=20"`
+	Offset  int    `form:"offset,optional"`
+}
+
+type ConversationHit struct {
+	ModelId        string  `json:"model_id"`
+	ConversationId int64   `json:"conversation_id"`
+	MessageId      int64   `json:"message_id"`
+	Role           string  `json:"role"`
+	Timestamp      int64   `json:"timestamp"`
+	Content        string  `json:"content"`
+	Snippet        string  `json:"snippet"`
+	Score          float64 `json:"score"`
+}
+
+type ConversationSearchResponse struct {
+	Query      string               `json:"query"`
+	Total      int                  `json:"total"`
+	Limit      int                  `json:"limit"`
+	Offset     int                  `json:"offset"`
+	Hits       []ConversationHit    `json:"hits"`
+	ServerTime int64                `json:"serverTime"`
+	Models     map[string]ModelInfo `json:"models,omitempty"`
+}
+
+type ConversationLink struct {
+	ConversationId int64    `json:"conversation_id"`
+	MessageId      int64    `json:"message_id"`
+	ModelId        string   `json:"model_id"`
+	Symbol         string   `json:"symbol"`
+	Side           string   `json:"side"`
+	TradeId        string   `json:"trade_id,omitempty"`
+	EntryOid       int64    `json:"entry_oid,omitempty"`
+	Position       bool     `json:"position,omitempty"`
+	DecisionTime   int64    `json:"decision_time"`
+	EntryTime      int64    `json:"entry_time"`
+	MatchedOn      []string `json:"matched_on"`
+	Score          float64  `json:"score"`
+}
+
+type ConversationLinksRequest struct {
+	ModelId        string `form:"model_id,optional"`
+	ConversationId int64  `form:"conversation_id,optional"`
+	TradeId        string `form:"trade_id,optional"`
+}
+
+type ConversationLinksResponse struct {
+	Links      []ConversationLink `json:"links"`
+	ServerTime int64              `json:"serverTime"`
+}
+
+type Invocation struct {
+	Id             string  `json:"id"`
+	ModelId        string  `json:"model_id"`
+	ConversationId int64   `json:"conversation_id,omitempty"`
+	Provider       string  `json:"provider,omitempty"`
+	PromptVersion  string  `json:"prompt_version,omitempty"`
+	Timestamp      int64   `json:"timestamp"`
+	LatencyMs      int64   `json:"latency_ms"`
+	InputTokens    int     `json:"input_tokens"`
+	OutputTokens   int     `json:"output_tokens"`
+	CostUsd        float64 `json:"cost_usd"`
+	Status         string  `json:"status"`
+	Error          string  `json:"error,omitempty"`
+	Estimated      bool    `json:"estimated,omitempty"`
+}
+
+type InvocationsRequest struct {
+	ModelId string `form:"model_id,optional"`
+	From    int64  `form:"from,optional"`
+	To      int64  `form:"to,optional"`
+}
+
+type InvocationDailyStats struct {
+	Day                    string  `json:"day"`
+	ModelId                string  `json:"model_id"`
+	Provider               string  `json:"provider,omitempty"`
+	Invocations            int     `json:"invocations"`
+	Errors                 int     `json:"errors"`
+	InputTokens            int     `json:"input_tokens"`
+	OutputTokens           int     `json:"output_tokens"`
+	CostUsd                float64 `json:"cost_usd"`
+	AvgLatencyMs           float64 `json:"avg_latency_ms"`
+	P95LatencyMs           float64 `json:"p95_latency_ms"`
+	MaxLatencyMs           int64   `json:"max_latency_ms"`
+	AvgInvocationBreakMins float64 `json:"avg_invocation_break_mins"`
+	Estimated              bool    `json:"estimated,omitempty"`
+}
+
+type InvocationsResponse struct {
+	Days       []InvocationDailyStats `json:"days"`
+	ServerTime int64                  `json:"serverTime"`
+	Models     map[string]ModelInfo   `json:"models,omitempty"`
+}
+
+type JobStatus struct {
+	Name           string `json:"name"`
+	Schedule       string `json:"schedule"`
+	Running        bool   `json:"running"`
+	LastStatus     string `json:"last_status,omitempty"`
+	LastRunAt      int64  `json:"last_run_at,omitempty"`
+	LastDurationMs int64  `json:"last_duration_ms,omitempty"`
+	LastResult     string `json:"last_result,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	NextRunAt      int64  `json:"next_run_at,omitempty"`
+	Runs           int    `json:"runs"`
+	Failures       int    `json:"failures"`
+	Skipped        int    `json:"skipped"`
+}
+
+type JobsResponse struct {
+	Jobs       []JobStatus `json:"jobs"`
+	ServerTime int64       `json:"serverTime"`
+}
+
+type IngestResponse struct {
+	Type       string   `json:"type"`
+	Received   int      `json:"received"`
+	Written    int      `json:"written"`
+	Duplicates int      `json:"duplicates"`
+	Ids        []string `json:"ids,omitempty"`
+	ServerTime int64    `json:"serverTime"`
+}
+
+type ChangeEvent struct {
+	Type     string   `json:"type"`
+	Arena    string   `json:"arena,omitempty"`
+	ModelIds []string `json:"model_ids,omitempty"`
+	Ids      []string `json:"ids,omitempty"`
+	Count    int      `json:"count"`
+	Ts       int64    `json:"ts"`
+}
+
+type ApiKey struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	Role       string `json:"role"`
+	CreatedAt  int64  `json:"created_at"`
+	LastUsedAt int64  `json:"last_used_at,omitempty"`
+	RevokedAt  int64  `json:"revoked_at,omitempty"`
+	Key        string `json:"key,omitempty"`
+}
+
+type ApiKeysResponse struct {
+	Keys       []ApiKey `json:"keys"`
+	ServerTime int64    `json:"serverTime"`
+}
+
+type ApiKeyResponse struct {
+	Key        ApiKey `json:"key"`
+	ServerTime int64  `json:"serverTime"`
+}
+
+type CreateApiKeyRequest struct {
+	Name string `json:"name"`
+	Role string `json:"role,options=reader|ingester|admin"`
+}
+
+type RevokeApiKeyRequest struct {
+	Id string `path:"id"`
+}
+
+type CorrelationRequest struct {
+	WindowMins int `form:"windowMins,optional,default=30"`
+}
+
+type ReturnCorrelation struct {
+	ModelA          string  `json:"model_a"`
+	ModelB          string  `json:"model_b"`
+	Correlation     float64 `json:"correlation"`
+	NumObservations int     `json:"num_observations"`
+}
+
+type DirectionOverlap struct {
+	Symbol                string  `json:"symbol"`
+	ModelA                string  `json:"model_a"`
+	ModelB                string  `json:"model_b"`
+	SameDirectionMins     float64 `json:"same_direction_mins"`
+	OppositeDirectionMins float64 `json:"opposite_direction_mins"`
+	OverlapPct            float64 `json:"overlap_pct"`
+}
+
+type CoincidentTrades struct {
+	ModelA string         `json:"model_a"`
+	ModelB string         `json:"model_b"`
+	Count  int            `json:"count"`
+	Counts map[string]int `json:"counts"`
+}
+
+type CorrelationResponse struct {
+	WindowMins         int                 `json:"windowMins"`
+	ReturnSource       string              `json:"returnSource"`
+	ReturnCorrelations []ReturnCorrelation `json:"returnCorrelations"`
+	DirectionOverlaps  []DirectionOverlap  `json:"directionOverlaps"`
+	CoincidentTrades   []CoincidentTrades  `json:"coincidentTrades"`
+	ServerTime         int64               `json:"serverTime"`
+}
+
+type ModelDetailRequest struct {
+	ModelId string `path:"modelId"`
+	Trades  int    `form:"trades,optional,default=20"`
+}
+
+type ModelDetailResponse struct {
+	ModelId        string                `json:"model_id"`
+	Model          *ModelInfo            `json:"model"`
+	AccountTotal   *AccountTotal         `json:"accountTotal"`
+	Positions      map[string]Position   `json:"positions"`
+	Trades         []Trade               `json:"trades"`
+	Analytics      *ModelAnalytics       `json:"analytics"`
+	SinceInception []SinceInceptionValue `json:"sinceInception"`
+	Rank           int                   `json:"rank"`
+	Leaderboard    *LeaderboardEntry     `json:"leaderboard"`
+	Conversation   *Conversation         `json:"conversation"`
+	Errors         map[string]string     `json:"errors,omitempty"`
+	ServerTime     int64                 `json:"serverTime"`
+}
+
+type ModelInfo struct {
+	Id              string  `json:"id"`
+	DisplayName     string  `json:"display_name"`
+	Provider        string  `json:"provider"`
+	Color           string  `json:"color"`
+	IconUrl         string  `json:"icon_url"`
+	StartingCapital float64 `json:"starting_capital"`
+	InceptionTime   int64   `json:"inception_time"`
+	Status          string  `json:"status"`
+	PromptVersion   string  `json:"prompt_version"`
+}
+
+type ModelsResponse struct {
+	Models     []ModelInfo `json:"models"`
+	ServerTime int64       `json:"serverTime"`
+}
+
+type ModelResponse struct {
+	Model      ModelInfo `json:"model"`
+	ServerTime int64     `json:"serverTime"`
+}
+
+type CreateModelRequest struct {
+	Id              string  `json:"id"`
+	DisplayName     string  `json:"display_name,optional"`
+	Provider        string  `json:"provider,optional"`
+	Color           string  `json:"color,optional"`
+	IconUrl         string  `json:"icon_url,optional"`
+	StartingCapital float64 `json:"starting_capital,optional"`
+	InceptionTime   int64   `json:"inception_time,optional"`
+	Status          string  `json:"status,optional,options=active|paused|retired"`
+	PromptVersion   string  `json:"prompt_version,optional"`
+}
+
+type UpdateModelRequest struct {
+	ModelId         string   `path:"modelId"`
+	DisplayName     *string  `json:"display_name,optional"`
+	Provider        *string  `json:"provider,optional"`
+	Color           *string  `json:"color,optional"`
+	IconUrl         *string  `json:"icon_url,optional"`
+	StartingCapital *float64 `json:"starting_capital,optional"`
+	InceptionTime   *int64   `json:"inception_time,optional"`
+	Status          *string  `json:"status,optional,options=active|paused|retired"`
+	PromptVersion   *string  `json:"prompt_version,optional"`
+}
+
+type ArenaInfo struct {
+	Id           string   `json:"id"`
+	Name         string   `json:"name"`
+	StartTime    int64    `json:"start_time"`
+	EndTime      int64    `json:"end_time"`
+	Status       string   `json:"status"`
+	Participants []string `json:"participants"`
+	Default      bool     `json:"default"`
+}
+
+type ArenasResponse struct {
+	Arenas     []ArenaInfo `json:"arenas"`
+	ServerTime int64       `json:"serverTime"`
 }
@@ -0,0 +1,68 @@
+package upstream
+
+import (
+	"sync"
+	"time"
+
+	"nof0-api/internal/errs"
+)
+
+// ErrOpen is returned without contacting upstream while the breaker is open.
+var ErrOpen = errs.Upstream("upstream circuit breaker is open")
+
+// breaker stops calling an upstream that keeps failing: after failures
+// consecutive failures it rejects calls for cooldown, then lets one probe
+// through; the probe's outcome closes or reopens it.
+type breaker struct {
+	failures int
+	cooldown time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	failed    int
+	openUntil time.Time
+	probing   bool
+}
+
+func newBreaker(failures int, cooldown time.Duration) *breaker {
+	if failures <= 0 {
+		failures = 1
+	}
+	return &breaker{failures: failures, cooldown: cooldown, now: time.Now}
+}
+
+// allow reports whether a call may go ahead; every allowed call must be
+// followed by done.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failed < b.failures {
+		return nil
+	}
+	if b.probing || b.now().Before(b.openUntil) {
+		return ErrOpen
+	}
+	b.probing = true
+	return nil
+}
+
+func (b *breaker) done(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if ok {
+		b.failed = 0
+		return
+	}
+	b.failed++
+	if b.failed >= b.failures {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// open reports whether calls are currently rejected.
+func (b *breaker) open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failed >= b.failures && (b.probing || b.now().Before(b.openUntil))
+}
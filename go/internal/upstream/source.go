@@ -0,0 +1,441 @@
+// Package upstream reads arena data from an API with nof1.ai's endpoints
+// (mcp/data/api-endpoints.json) and can mirror every response to disk as a
+// snapshot in the DataLoader file layout, so nof0 keeps serving the last good
+// data while the upstream is slow or down.
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+
+	"nof0-api/internal/config"
+	"nof0-api/internal/data"
+	"nof0-api/internal/errs"
+	"nof0-api/internal/metrics"
+	"nof0-api/internal/types"
+)
+
+// JobMirror is the scheduled job running Sync.
+const JobMirror = "upstream_mirror"
+
+// maxBody bounds a response; the largest snapshots are a few MB.
+const maxBody = 64 << 20
+
+// ErrInvalid wraps responses that decoded but failed validation.
+var ErrInvalid = errs.Upstream("invalid upstream response")
+
+// StatusError is a non-2xx upstream response.
+type StatusError struct {
+	Path string
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("upstream %s: HTTP %d", e.Path, e.Code)
+}
+
+// Source is a data.DataSource backed by the upstream API. Requests are
+// retried with exponential backoff on network errors, 5xx and 429, and go
+// through a circuit breaker. With a mirror directory, each valid response is
+// written there (crypto-prices.json, trades.json, ...) and served from there
+// when the upstream cannot answer.
+type Source struct {
+	base    string
+	client  *http.Client
+	retries int
+	backoff time.Duration
+	breaker *breaker
+
+	mirror   string
+	snapshot *data.DataLoader // reads mirror; nil without one
+}
+
+var _ data.DataSource = (*Source)(nil)
+
+// New returns a source for c.BaseURL; mirror is the snapshot directory, or
+// "" to only proxy.
+func New(c config.UpstreamConf, mirror string) *Source {
+	s := &Source{
+		base:    strings.TrimRight(c.BaseURL, "/"),
+		client:  &http.Client{Timeout: time.Duration(c.Timeout) * time.Millisecond},
+		retries: c.Retries,
+		backoff: time.Duration(c.Backoff) * time.Millisecond,
+		breaker: newBreaker(c.BreakerFailures, time.Duration(c.BreakerCooldown)*time.Second),
+		mirror:  mirror,
+	}
+	if mirror != "" {
+		s.snapshot = data.NewDataLoader(mirror)
+	}
+	return s
+}
+
+// resource is one upstream endpoint, the value it decodes into and the
+// snapshot file it is mirrored to.
+type resource struct {
+	path  string // relative to the base URL, with query
+	file  string
+	v     any
+	check func() error
+}
+
+func cryptoPrices(resp *types.CryptoPricesResponse) resource {
+	return resource{"/crypto-prices", "crypto-prices.json", resp, func() error {
+		return requireField(len(resp.Prices) > 0, "prices")
+	}}
+}
+
+func accountTotals(resp *types.AccountTotalsResponse) resource {
+	return resource{"/account-totals", "account-totals.json", resp, func() error {
+		return requireField(resp.AccountTotals != nil, "accountTotals")
+	}}
+}
+
+func trades(resp *types.TradesResponse) resource {
+	return resource{"/trades", "trades.json", resp, func() error {
+		return requireField(resp.Trades != nil, "trades")
+	}}
+}
+
+func sinceInception(resp *types.SinceInceptionResponse) resource {
+	return resource{"/since-inception-values", "since-inception-values.json", resp, func() error {
+		return requireField(resp.SinceInceptionValues != nil, "sinceInceptionValues")
+	}}
+}
+
+func leaderboard(resp *types.LeaderboardResponse) resource {
+	return resource{"/leaderboard", "leaderboard.json", resp, func() error {
+		return requireField(resp.Leaderboard != nil, "leaderboard")
+	}}
+}
+
+func analytics(resp *types.AnalyticsResponse) resource {
+	return resource{"/analytics", "analytics.json", resp, func() error {
+		return requireField(resp.Analytics != nil, "analytics")
+	}}
+}
+
+// modelAnalytics is /analytics/{modelId}, which upstream answers with an
+// analytics list like /analytics.
+func modelAnalytics(modelId string, resp *types.AnalyticsResponse) resource {
+	return resource{"/analytics/" + url.PathEscape(modelId), "analytics-" + modelId + ".json", resp, func() error {
+		return requireField(resp.Analytics != nil, "analytics")
+	}}
+}
+
+func positions(resp *types.PositionsResponse) resource {
+	return resource{"/positions?limit=1000", "positions.json", resp, func() error {
+		return requireField(resp.AccountTotals != nil, "accountTotals")
+	}}
+}
+
+func conversations(resp *types.ConversationsResponse) resource {
+	return resource{"/conversations", "conversations.json", resp, func() error {
+		return requireField(resp.Conversations != nil, "conversations")
+	}}
+}
+
+func (s *Source) LoadCryptoPrices() (*types.CryptoPricesResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceUpstream, "crypto_prices", time.Now())
+	var resp types.CryptoPricesResponse
+	if err := s.fetch(context.Background(), cryptoPrices(&resp)); err != nil {
+		return fallback(s, err, s.snapshot.LoadCryptoPrices)
+	}
+	stamp(&resp.ServerTime)
+	return &resp, nil
+}
+
+func (s *Source) LoadAccountTotals() (*types.AccountTotalsResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceUpstream, "account_totals", time.Now())
+	var resp types.AccountTotalsResponse
+	if err := s.fetch(context.Background(), accountTotals(&resp)); err != nil {
+		return fallback(s, err, s.snapshot.LoadAccountTotals)
+	}
+	stamp(&resp.ServerTime)
+	return &resp, nil
+}
+
+func (s *Source) LoadTrades() (*types.TradesResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceUpstream, "trades", time.Now())
+	var resp types.TradesResponse
+	if err := s.fetch(context.Background(), trades(&resp)); err != nil {
+		return fallback(s, err, s.snapshot.LoadTrades)
+	}
+	stamp(&resp.ServerTime)
+	return &resp, nil
+}
+
+func (s *Source) LoadSinceInception() (*types.SinceInceptionResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceUpstream, "since_inception", time.Now())
+	var resp types.SinceInceptionResponse
+	if err := s.fetch(context.Background(), sinceInception(&resp)); err != nil {
+		return fallback(s, err, s.snapshot.LoadSinceInception)
+	}
+	stamp(&resp.ServerTime)
+	return &resp, nil
+}
+
+func (s *Source) LoadLeaderboard() (*types.LeaderboardResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceUpstream, "leaderboard", time.Now())
+	var resp types.LeaderboardResponse
+	if err := s.fetch(context.Background(), leaderboard(&resp)); err != nil {
+		return fallback(s, err, s.snapshot.LoadLeaderboard)
+	}
+	return &resp, nil
+}
+
+func (s *Source) LoadAnalytics() (*types.AnalyticsResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceUpstream, "analytics", time.Now())
+	var resp types.AnalyticsResponse
+	if err := s.fetch(context.Background(), analytics(&resp)); err != nil {
+		return fallback(s, err, s.snapshot.LoadAnalytics)
+	}
+	stamp(&resp.ServerTime)
+	return &resp, nil
+}
+
+func (s *Source) LoadModelAnalytics(modelId string) (*types.ModelAnalyticsResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceUpstream, "model_analytics", time.Now())
+	var resp types.AnalyticsResponse
+	if err := s.fetch(context.Background(), modelAnalytics(modelId, &resp)); err != nil {
+		return fallback(s, err, func() (*types.ModelAnalyticsResponse, error) {
+			return s.snapshot.LoadModelAnalytics(modelId)
+		})
+	}
+	for _, a := range resp.Analytics {
+		if a.ModelId == modelId {
+			out := &types.ModelAnalyticsResponse{Analytics: a, ServerTime: resp.ServerTime}
+			stamp(&out.ServerTime)
+			return out, nil
+		}
+	}
+	return nil, errs.NotFound("no analytics for model %s", modelId)
+}
+
+func (s *Source) LoadPositions() (*types.PositionsResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceUpstream, "positions", time.Now())
+	var resp types.PositionsResponse
+	if err := s.fetch(context.Background(), positions(&resp)); err != nil {
+		return fallback(s, err, s.snapshot.LoadPositions)
+	}
+	stamp(&resp.ServerTime)
+	return &resp, nil
+}
+
+func (s *Source) LoadConversations() (*types.ConversationsResponse, error) {
+	defer metrics.ObserveLoad(metrics.SourceUpstream, "conversations", time.Now())
+	var resp types.ConversationsResponse
+	if err := s.fetch(context.Background(), conversations(&resp)); err != nil {
+		return fallback(s, err, s.snapshot.LoadConversations)
+	}
+	stamp(&resp.ServerTime)
+	return &resp, nil
+}
+
+// Sync mirrors every resource, and the analytics of each leaderboard model,
+// into the mirror directory. It is the JobMirror job: resources that
+// fail keep their previous snapshot and are reported in the error.
+func (s *Source) Sync(ctx context.Context) (string, error) {
+	if s.mirror == "" {
+		return "", errors.New("upstream: no mirror directory")
+	}
+	if s.BreakerOpen() {
+		return "", ErrOpen
+	}
+	var (
+		board    types.LeaderboardResponse
+		failed   []string
+		failures []error
+	)
+	rs := []resource{
+		cryptoPrices(&types.CryptoPricesResponse{}),
+		accountTotals(&types.AccountTotalsResponse{}),
+		trades(&types.TradesResponse{}),
+		sinceInception(&types.SinceInceptionResponse{}),
+		leaderboard(&board),
+		analytics(&types.AnalyticsResponse{}),
+		positions(&types.PositionsResponse{}),
+		conversations(&types.ConversationsResponse{}),
+	}
+	for i := 0; i < len(rs); i++ {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if err := s.fetch(ctx, rs[i]); err != nil {
+			failed = append(failed, rs[i].file)
+			failures = append(failures, err)
+			continue
+		}
+		if rs[i].file == "leaderboard.json" {
+			for _, e := range board.Leaderboard {
+				rs = append(rs, modelAnalytics(e.Id, &types.AnalyticsResponse{}))
+			}
+		}
+	}
+	summary := fmt.Sprintf("mirrored %d of %d resources from %s", len(rs)-len(failed), len(rs), s.base)
+	if len(failures) > 0 {
+		return "", fmt.Errorf("%s; failed %s: %w", summary, strings.Join(failed, ", "), errors.Join(failures...))
+	}
+	return summary, nil
+}
+
+// PrepareMirror creates the mirror directory with an arena.json naming it,
+// so data.NewArenaSet lists the arena before the first sync.
+func (s *Source) PrepareMirror(name string) error {
+	if err := os.MkdirAll(s.mirror, 0o755); err != nil {
+		return err
+	}
+	meta := filepath.Join(s.mirror, "arena.json")
+	if _, err := os.Stat(meta); err == nil {
+		return nil
+	}
+	body, err := json.Marshal(map[string]string{"name": name})
+	if err != nil {
+		return err
+	}
+	return writeFile(meta, body)
+}
+
+// BreakerOpen reports whether the upstream is currently skipped.
+func (s *Source) BreakerOpen() bool {
+	return s.breaker.open()
+}
+
+// fallback serves the last snapshot when the upstream failed; the upstream
+// error is returned when there is none.
+func fallback[T any](s *Source, err error, load func() (*T, error)) (*T, error) {
+	if s.snapshot == nil {
+		return nil, clientError(err)
+	}
+	v, ferr := load()
+	if ferr != nil {
+		return nil, clientError(fmt.Errorf("%w (no snapshot: %v)", err, ferr))
+	}
+	logx.Errorf("%v; serving the last snapshot", err)
+	return v, nil
+}
+
+// clientError classifies an upstream failure for API clients: a 404 stays a
+// 404, anything else is an errs.KindUpstream.
+func clientError(err error) error {
+	var se *StatusError
+	if errors.As(err, &se) && se.Code == http.StatusNotFound {
+		return errs.Wrap(errs.KindNotFound, err, "not found upstream")
+	}
+	return errs.Wrap(errs.KindUpstream, err, "upstream unavailable")
+}
+
+// fetch GETs r, validates it and mirrors the body.
+func (s *Source) fetch(ctx context.Context, r resource) error {
+	body, err := s.get(ctx, r.path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, r.v); err != nil {
+		return fmt.Errorf("upstream %s: %w: %v", r.path, ErrInvalid, err)
+	}
+	if err := r.check(); err != nil {
+		return fmt.Errorf("upstream %s: %w: %v", r.path, ErrInvalid, err)
+	}
+	// Model ids name files; never let one reach outside the mirror.
+	if s.mirror != "" && filepath.Base(r.file) == r.file {
+		if err := writeFile(filepath.Join(s.mirror, r.file), body); err != nil {
+			logx.WithContext(ctx).Errorf("mirror %s: %v", r.file, err)
+		}
+	}
+	return nil
+}
+
+// get performs the request with retries through the breaker.
+func (s *Source) get(ctx context.Context, path string) ([]byte, error) {
+	if err := s.breaker.allow(); err != nil {
+		return nil, err
+	}
+	var (
+		body []byte
+		err  error
+	)
+	wait := s.backoff
+	for attempt := 0; ; attempt++ {
+		body, err = s.getOnce(ctx, path)
+		if err == nil || !retryable(err) || attempt >= s.retries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			s.breaker.done(false)
+			return nil, err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+	// A 4xx is an answer, not an outage.
+	s.breaker.done(err == nil || !retryable(err))
+	return body, err
+}
+
+func (s *Source) getOnce(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.base+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		return nil, &StatusError{Path: path, Code: resp.StatusCode}
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxBody))
+}
+
+func retryable(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code >= 500 || se.Code == http.StatusTooManyRequests
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+func requireField(ok bool, field string) error {
+	if !ok {
+		return fmt.Errorf("missing %q", field)
+	}
+	return nil
+}
+
+func stamp(serverTime *int64) {
+	if *serverTime == 0 {
+		*serverTime = time.Now().UnixMilli()
+	}
+}
+
+// writeFile replaces path atomically, so readers never see a partial file.
+func writeFile(path string, body []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upstream-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
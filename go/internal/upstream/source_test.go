@@ -0,0 +1,162 @@
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"nof0-api/internal/config"
+	"nof0-api/internal/data"
+)
+
+const testDataPath = "../../../mcp/data"
+
+var testConf = config.UpstreamConf{Timeout: 2000, Retries: 2, Backoff: 1, BreakerFailures: 2, BreakerCooldown: 30}
+
+// newStandIn serves the snapshot files the way nof1.ai serves its API:
+// /api/trades from trades.json, /api/analytics/gpt-5 from analytics-gpt-5.json.
+func newStandIn(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		name := strings.TrimPrefix(r.URL.Path, "/api/")
+		name = strings.Replace(name, "analytics/", "analytics-", 1)
+		http.ServeFile(w, r, filepath.Join(testDataPath, name+".json"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestSourceMatchesSnapshots(t *testing.T) {
+	srv, _ := newStandIn(t)
+	mirror := t.TempDir()
+	src := New(config.UpstreamConf{BaseURL: srv.URL + "/api/", Timeout: 2000}, mirror)
+	files := data.NewDataLoader(testDataPath)
+
+	got, err := src.LoadTrades()
+	require.NoError(t, err)
+	want, err := files.LoadTrades()
+	require.NoError(t, err)
+	assert.Equal(t, want.Trades, got.Trades)
+	assert.FileExists(t, filepath.Join(mirror, "trades.json"))
+
+	prices, err := src.LoadCryptoPrices()
+	require.NoError(t, err)
+	assert.Contains(t, prices.Prices, "BTC")
+
+	analytics, err := src.LoadModelAnalytics("gpt-5")
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-5", analytics.Analytics.ModelId)
+	assert.NotZero(t, analytics.ServerTime)
+}
+
+func TestSourceSync(t *testing.T) {
+	srv, _ := newStandIn(t)
+	mirror := filepath.Join(t.TempDir(), "nof1")
+	src := New(config.UpstreamConf{BaseURL: srv.URL + "/api", Timeout: 2000}, mirror)
+	require.NoError(t, src.PrepareMirror("nof1 mirror"))
+
+	// The fixtures have no account-totals.json: that resource fails and the
+	// rest are mirrored.
+	_, err := src.Sync(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed account-totals.json")
+	for _, f := range []string{"arena.json", "crypto-prices.json", "trades.json", "positions.json", "leaderboard.json",
+		"conversations.json", "analytics.json", "analytics-gpt-5.json"} {
+		assert.FileExists(t, filepath.Join(mirror, f))
+	}
+
+	arenas, err := data.NewArenaSet(filepath.Dir(mirror), "")
+	require.NoError(t, err)
+	dl, ok := arenas.Loader("nof1")
+	require.True(t, ok, "The mirror is an arena")
+	resp, err := dl.LoadPositions()
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccountTotals)
+}
+
+func TestSourceRetriesAndFallsBack(t *testing.T) {
+	var failing atomic.Bool
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failing.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		http.ServeFile(w, r, filepath.Join(testDataPath, "leaderboard.json"))
+	}))
+	defer srv.Close()
+	c := testConf
+	c.BaseURL = srv.URL
+	src := New(c, t.TempDir())
+
+	live, err := src.LoadLeaderboard()
+	require.NoError(t, err)
+
+	failing.Store(true)
+	calls.Store(0)
+	cached, err := src.LoadLeaderboard()
+	require.NoError(t, err, "The last snapshot is served")
+	assert.Equal(t, live.Leaderboard, cached.Leaderboard)
+	assert.Equal(t, int32(3), calls.Load(), "One attempt and two retries")
+
+	_, err = src.LoadLeaderboard()
+	require.NoError(t, err)
+	assert.True(t, src.BreakerOpen(), "Two failed requests open the breaker")
+	calls.Store(0)
+	_, err = src.LoadLeaderboard()
+	require.NoError(t, err)
+	assert.Zero(t, calls.Load(), "An open breaker skips the upstream")
+
+	src.breaker.now = func() time.Time { return time.Now().Add(time.Minute) }
+	failing.Store(false)
+	_, err = src.LoadLeaderboard()
+	require.NoError(t, err)
+	assert.False(t, src.BreakerOpen(), "A successful probe closes the breaker")
+}
+
+func TestSourceRejectsInvalidResponses(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/crypto-prices" {
+			w.Write([]byte(`{"prices":{}}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+	c := testConf
+	c.BaseURL = srv.URL
+	mirror := t.TempDir()
+	src := New(c, mirror)
+
+	_, err := src.LoadCryptoPrices()
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.NoFileExists(t, filepath.Join(mirror, "crypto-prices.json"))
+
+	calls.Store(0)
+	for range 3 {
+		_, err = src.LoadTrades()
+		var se *StatusError
+		require.ErrorAs(t, err, &se)
+		assert.Equal(t, http.StatusNotFound, se.Code)
+	}
+	assert.Equal(t, int32(3), calls.Load(), "4xx is not retried")
+	assert.False(t, src.BreakerOpen(), "4xx does not open the breaker")
+
+	_, err = New(c, "").LoadTrades()
+	assert.Error(t, err, "Without a mirror there is no fallback")
+	_, err = os.Stat(filepath.Join(mirror, "trades.json"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,15 @@
+DROP MATERIALIZED VIEW IF EXISTS v_since_inception;
+DROP MATERIALIZED VIEW IF EXISTS v_leaderboard;
+DROP MATERIALIZED VIEW IF EXISTS v_crypto_prices_latest;
+
+DROP TABLE IF EXISTS conversation_messages;
+DROP TABLE IF EXISTS conversations;
+DROP TABLE IF EXISTS model_analytics;
+DROP TABLE IF EXISTS trades;
+DROP TABLE IF EXISTS positions;
+DROP TABLE IF EXISTS account_equity_snapshots;
+DROP TABLE IF EXISTS accounts;
+DROP TABLE IF EXISTS price_latest;
+DROP TABLE IF EXISTS price_ticks;
+DROP TABLE IF EXISTS symbols;
+DROP TABLE IF EXISTS models;
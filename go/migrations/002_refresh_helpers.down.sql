@@ -0,0 +1,2 @@
+DROP FUNCTION IF EXISTS refresh_views_nof0();
+DROP INDEX IF EXISTS ux_v_crypto_prices_latest;
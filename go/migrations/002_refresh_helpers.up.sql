@@ -0,0 +1,19 @@
+-- Helper functions to refresh materialized views.
+-- CONCURRENTLY needs a unique index on the view; views without one (e.g.
+-- v_since_inception before 005) are refreshed with a plain, locking refresh.
+CREATE UNIQUE INDEX IF NOT EXISTS ux_v_crypto_prices_latest ON v_crypto_prices_latest(symbol);
+
+CREATE OR REPLACE FUNCTION refresh_views_nof0()
+RETURNS void LANGUAGE plpgsql AS $$
+DECLARE
+  v text;
+BEGIN
+  FOREACH v IN ARRAY ARRAY['v_crypto_prices_latest', 'v_leaderboard', 'v_since_inception'] LOOP
+    IF EXISTS (SELECT 1 FROM pg_index WHERE indrelid = v::regclass AND indisunique) THEN
+      EXECUTE format('REFRESH MATERIALIZED VIEW CONCURRENTLY %I', v);
+    ELSE
+      EXECUTE format('REFRESH MATERIALIZED VIEW %I', v);
+    END IF;
+  END LOOP;
+END;
+$$;
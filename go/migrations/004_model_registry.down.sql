@@ -0,0 +1,10 @@
+ALTER TABLE models DROP CONSTRAINT IF EXISTS models_status_check;
+
+ALTER TABLE models DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE models DROP COLUMN IF EXISTS prompt_version;
+ALTER TABLE models DROP COLUMN IF EXISTS status;
+ALTER TABLE models DROP COLUMN IF EXISTS inception_ts_ms;
+ALTER TABLE models DROP COLUMN IF EXISTS starting_capital;
+ALTER TABLE models DROP COLUMN IF EXISTS icon_url;
+ALTER TABLE models DROP COLUMN IF EXISTS color;
+ALTER TABLE models DROP COLUMN IF EXISTS provider;
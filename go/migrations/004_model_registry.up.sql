@@ -0,0 +1,17 @@
+-- Model registry attributes on top of the bare models(id, display_name) table
+ALTER TABLE models ADD COLUMN IF NOT EXISTS provider         text;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS color            text;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS icon_url         text;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS starting_capital double precision NOT NULL DEFAULT 10000;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS inception_ts_ms  bigint;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS status           text NOT NULL DEFAULT 'active';
+ALTER TABLE models ADD COLUMN IF NOT EXISTS prompt_version   text;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS updated_at       timestamptz DEFAULT now();
+
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'models_status_check') THEN
+    ALTER TABLE models ADD CONSTRAINT models_status_check CHECK (status IN ('active','paused','retired'));
+  END IF;
+END;
+$$;
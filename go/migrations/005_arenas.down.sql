@@ -0,0 +1,46 @@
+-- Back to a single arena: facts from other arenas are dropped.
+DROP MATERIALIZED VIEW IF EXISTS v_since_inception;
+DROP MATERIALIZED VIEW IF EXISTS v_leaderboard;
+
+DELETE FROM model_analytics WHERE arena_id <> 'default';
+ALTER TABLE model_analytics DROP CONSTRAINT IF EXISTS model_analytics_pkey;
+ALTER TABLE model_analytics ADD PRIMARY KEY (model_id);
+
+DROP INDEX IF EXISTS idx_conversations_arena_model;
+DROP INDEX IF EXISTS idx_trades_arena_model_ts;
+CREATE INDEX IF NOT EXISTS idx_trades_model_ts ON trades(model_id, entry_ts_ms DESC);
+DROP INDEX IF EXISTS idx_positions_arena_model;
+CREATE INDEX IF NOT EXISTS idx_positions_model ON positions(model_id);
+DROP INDEX IF EXISTS idx_equity_arena_model_ts;
+CREATE INDEX IF NOT EXISTS idx_equity_model_ts ON account_equity_snapshots(model_id, ts_ms DESC);
+
+ALTER TABLE conversations            DROP COLUMN IF EXISTS arena_id;
+ALTER TABLE model_analytics          DROP COLUMN IF EXISTS arena_id;
+ALTER TABLE trades                   DROP COLUMN IF EXISTS arena_id;
+ALTER TABLE positions                DROP COLUMN IF EXISTS arena_id;
+ALTER TABLE account_equity_snapshots DROP COLUMN IF EXISTS arena_id;
+
+DROP TABLE IF EXISTS arena_models;
+DROP TABLE IF EXISTS arenas;
+
+CREATE MATERIALIZED VIEW v_leaderboard AS
+WITH last_eq AS (
+    SELECT DISTINCT ON (model_id) model_id, ts_ms, equity_usd
+    FROM account_equity_snapshots
+    ORDER BY model_id, ts_ms DESC
+)
+SELECT m.id AS model_id,
+       l.equity_usd AS equity,
+       0.0::double precision AS sharpe, -- placeholder until stat job fills
+       0    ::int AS num_trades,
+       0    ::int AS num_wins,
+       0    ::int AS num_losses,
+       0.0  ::double precision AS win_dollars,
+       0.0  ::double precision AS lose_dollars,
+       0.0  ::double precision AS return_pct
+FROM models m
+LEFT JOIN last_eq l ON l.model_id = m.id;
+
+CREATE MATERIALIZED VIEW v_since_inception AS
+SELECT model_id, ts_ms AS timestamp, equity_usd AS value
+FROM account_equity_snapshots;
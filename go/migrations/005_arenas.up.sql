@@ -0,0 +1,65 @@
+-- Arenas (competitions/seasons). Every arena-scoped fact carries arena_id;
+-- rows written before arenas existed belong to the 'default' arena.
+CREATE TABLE IF NOT EXISTS arenas (
+    id          text PRIMARY KEY, -- e.g., season-1
+    name        text NOT NULL,
+    start_ts_ms bigint,
+    end_ts_ms   bigint, -- NULL while the arena is live
+    created_at  timestamptz DEFAULT now()
+);
+INSERT INTO arenas(id, name) VALUES ('default', 'default') ON CONFLICT (id) DO NOTHING;
+
+-- Which models take part in which arena
+CREATE TABLE IF NOT EXISTS arena_models (
+    arena_id text NOT NULL REFERENCES arenas(id) ON DELETE CASCADE,
+    model_id text NOT NULL REFERENCES models(id),
+    PRIMARY KEY (arena_id, model_id)
+);
+
+ALTER TABLE account_equity_snapshots ADD COLUMN IF NOT EXISTS arena_id text NOT NULL DEFAULT 'default' REFERENCES arenas(id);
+ALTER TABLE positions                ADD COLUMN IF NOT EXISTS arena_id text NOT NULL DEFAULT 'default' REFERENCES arenas(id);
+ALTER TABLE trades                   ADD COLUMN IF NOT EXISTS arena_id text NOT NULL DEFAULT 'default' REFERENCES arenas(id);
+ALTER TABLE model_analytics          ADD COLUMN IF NOT EXISTS arena_id text NOT NULL DEFAULT 'default' REFERENCES arenas(id);
+ALTER TABLE conversations            ADD COLUMN IF NOT EXISTS arena_id text NOT NULL DEFAULT 'default' REFERENCES arenas(id);
+
+DROP INDEX IF EXISTS idx_equity_model_ts;
+CREATE INDEX IF NOT EXISTS idx_equity_arena_model_ts ON account_equity_snapshots(arena_id, model_id, ts_ms DESC);
+DROP INDEX IF EXISTS idx_positions_model;
+CREATE INDEX IF NOT EXISTS idx_positions_arena_model ON positions(arena_id, model_id);
+DROP INDEX IF EXISTS idx_trades_model_ts;
+CREATE INDEX IF NOT EXISTS idx_trades_arena_model_ts ON trades(arena_id, model_id, entry_ts_ms DESC);
+CREATE INDEX IF NOT EXISTS idx_conversations_arena_model ON conversations(arena_id, model_id);
+
+-- Analytics are per model per arena
+ALTER TABLE model_analytics DROP CONSTRAINT IF EXISTS model_analytics_pkey;
+ALTER TABLE model_analytics ADD PRIMARY KEY (arena_id, model_id);
+
+-- Rebuild arena-aware views
+DROP MATERIALIZED VIEW IF EXISTS v_leaderboard;
+CREATE MATERIALIZED VIEW v_leaderboard AS
+WITH last_eq AS (
+    SELECT DISTINCT ON (arena_id, model_id) arena_id, model_id, ts_ms, equity_usd
+    FROM account_equity_snapshots
+    ORDER BY arena_id, model_id, ts_ms DESC
+)
+SELECT am.arena_id,
+       m.id AS model_id,
+       l.equity_usd AS equity,
+       0.0::double precision AS sharpe, -- placeholder until stat job fills
+       0    ::int AS num_trades,
+       0    ::int AS num_wins,
+       0    ::int AS num_losses,
+       0.0  ::double precision AS win_dollars,
+       0.0  ::double precision AS lose_dollars,
+       0.0  ::double precision AS return_pct
+FROM arena_models am
+JOIN models m ON m.id = am.model_id
+LEFT JOIN last_eq l ON l.arena_id = am.arena_id AND l.model_id = am.model_id;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_v_leaderboard ON v_leaderboard(arena_id, model_id);
+
+DROP MATERIALIZED VIEW IF EXISTS v_since_inception;
+CREATE MATERIALIZED VIEW v_since_inception AS
+SELECT id, arena_id, model_id, ts_ms AS timestamp, equity_usd AS value
+FROM account_equity_snapshots;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_v_since_inception ON v_since_inception(id);
+CREATE INDEX IF NOT EXISTS idx_v_since_inception ON v_since_inception(arena_id, model_id, timestamp);
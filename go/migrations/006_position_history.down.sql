@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS idx_trades_arena_exit;
+DROP INDEX IF EXISTS idx_positions_arena_entry;
+
+DROP FUNCTION IF EXISTS position_status_at(jsonb, bigint);
+DROP TRIGGER IF EXISTS trg_positions_status ON positions;
+DROP FUNCTION IF EXISTS positions_track_status();
+
+-- Collapse the richer statuses back onto open/closed
+ALTER TABLE positions DROP CONSTRAINT IF EXISTS positions_status_check;
+UPDATE positions SET status = 'open'   WHERE status IN ('pending','reduced');
+UPDATE positions SET status = 'closed' WHERE status = 'liquidated';
+ALTER TABLE positions ADD CONSTRAINT positions_status_check CHECK (status IN ('open','closed'));
+
+ALTER TABLE positions DROP COLUMN IF EXISTS status_history;
+ALTER TABLE positions DROP COLUMN IF EXISTS exit_ts_ms;
+ALTER TABLE positions DROP COLUMN IF EXISTS exit_price;
+ALTER TABLE positions DROP COLUMN IF EXISTS status_ts_ms;
@@ -0,0 +1,58 @@
+-- Position lifecycle: richer statuses plus a history of every transition so the
+-- state at any moment (?as_of=) can be reconstructed, not just open/closed.
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS status_ts_ms   bigint;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS exit_price     double precision;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS exit_ts_ms     bigint;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS status_history jsonb NOT NULL DEFAULT '[]'::jsonb; -- [{status, ts_ms}], oldest first
+
+ALTER TABLE positions DROP CONSTRAINT IF EXISTS positions_status_check;
+ALTER TABLE positions ADD CONSTRAINT positions_status_check
+    CHECK (status IN ('pending','open','reduced','closed','liquidated'));
+
+-- Append to status_history whenever a row is inserted or its status changes.
+-- Writers set status_ts_ms to the transition time; entry/exit times are used otherwise.
+CREATE OR REPLACE FUNCTION positions_track_status() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.status IS NOT DISTINCT FROM OLD.status THEN
+        RETURN NEW;
+    END IF;
+    IF TG_OP = 'UPDATE' AND NEW.status_ts_ms IS NOT DISTINCT FROM OLD.status_ts_ms THEN
+        NEW.status_ts_ms := NULL;
+    END IF;
+    NEW.status_ts_ms := coalesce(
+        NEW.status_ts_ms,
+        CASE WHEN NEW.status IN ('closed','liquidated') THEN NEW.exit_ts_ms END,
+        CASE WHEN TG_OP = 'INSERT' THEN NEW.entry_ts_ms END,
+        (extract(epoch FROM clock_timestamp()) * 1000)::bigint);
+    NEW.status_history := coalesce(NEW.status_history, '[]'::jsonb)
+        || jsonb_build_array(jsonb_build_object('status', NEW.status, 'ts_ms', NEW.status_ts_ms));
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_positions_status ON positions;
+CREATE TRIGGER trg_positions_status
+    BEFORE INSERT OR UPDATE OF status ON positions
+    FOR EACH ROW EXECUTE FUNCTION positions_track_status();
+
+-- Backfill history for rows that predate tracking
+UPDATE positions
+SET status_ts_ms   = coalesce(status_ts_ms, CASE WHEN status = 'closed' THEN exit_ts_ms END, entry_ts_ms),
+    status_history = jsonb_build_array(jsonb_build_object('status', 'open', 'ts_ms', entry_ts_ms))
+        || CASE WHEN status <> 'open'
+                THEN jsonb_build_array(jsonb_build_object('status', status, 'ts_ms', coalesce(exit_ts_ms, entry_ts_ms)))
+                ELSE '[]'::jsonb END
+WHERE status_history = '[]'::jsonb;
+
+-- Status of a position at a point in time: the last transition at or before ts.
+CREATE OR REPLACE FUNCTION position_status_at(history jsonb, ts bigint) RETURNS text AS $$
+    SELECT h->>'status'
+    FROM jsonb_array_elements(history) AS h
+    WHERE (h->>'ts_ms')::bigint <= ts
+    ORDER BY (h->>'ts_ms')::bigint DESC
+    LIMIT 1;
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Time-travel helpers for ?as_of=
+CREATE INDEX IF NOT EXISTS idx_positions_arena_entry ON positions(arena_id, entry_ts_ms);
+CREATE INDEX IF NOT EXISTS idx_trades_arena_exit ON trades(arena_id, exit_ts_ms);
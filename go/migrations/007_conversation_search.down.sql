@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_conversations_model;
+DROP INDEX IF EXISTS idx_conv_msgs_ts;
+DROP INDEX IF EXISTS idx_conv_msgs_content_tsv;
+ALTER TABLE conversation_messages DROP COLUMN IF EXISTS content_tsv;
@@ -0,0 +1,9 @@
+-- Full-text search over conversation messages (GET /api/conversations/search)
+ALTER TABLE conversation_messages
+    ADD COLUMN IF NOT EXISTS content_tsv tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', coalesce(content, ''))) STORED;
+CREATE INDEX IF NOT EXISTS idx_conv_msgs_content_tsv ON conversation_messages USING gin(content_tsv);
+
+-- Filters: time range across all conversations, and model lookups
+CREATE INDEX IF NOT EXISTS idx_conv_msgs_ts ON conversation_messages(ts_ms DESC);
+CREATE INDEX IF NOT EXISTS idx_conversations_model ON conversations(model_id);
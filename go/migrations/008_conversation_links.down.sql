@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS conversation_links;
+
+DROP INDEX IF EXISTS idx_positions_conversation;
+DROP INDEX IF EXISTS idx_trades_conversation;
+ALTER TABLE positions DROP COLUMN IF EXISTS conversation_id;
+ALTER TABLE trades    DROP COLUMN IF EXISTS conversation_id;
@@ -0,0 +1,19 @@
+-- Which conversation (decision) produced which trade/position; filled by the
+-- linking step of cmd/importer (see internal/data/linker.go for matching).
+ALTER TABLE trades    ADD COLUMN IF NOT EXISTS conversation_id bigint REFERENCES conversations(id) ON DELETE SET NULL;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS conversation_id bigint REFERENCES conversations(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_trades_conversation ON trades(conversation_id);
+CREATE INDEX IF NOT EXISTS idx_positions_conversation ON positions(conversation_id);
+
+CREATE TABLE IF NOT EXISTS conversation_links (
+    id              bigserial PRIMARY KEY,
+    conversation_id bigint NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    message_id      bigint REFERENCES conversation_messages(id) ON DELETE SET NULL,
+    trade_id        text UNIQUE REFERENCES trades(id) ON DELETE CASCADE,
+    position_id     text UNIQUE REFERENCES positions(id) ON DELETE CASCADE,
+    score           double precision NOT NULL,
+    matched_on      text[] NOT NULL DEFAULT '{}',
+    linked_at       timestamptz NOT NULL DEFAULT now(),
+    CHECK (trade_id IS NOT NULL OR position_id IS NOT NULL)
+);
+CREATE INDEX IF NOT EXISTS idx_conversation_links_conv ON conversation_links(conversation_id);
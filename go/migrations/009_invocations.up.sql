@@ -0,0 +1,19 @@
+-- Model invocations: one row per call with token accounting, cost, latency
+-- and outcome. Aggregated per model per day by GET /api/invocations.
+CREATE TABLE IF NOT EXISTS invocations (
+    id              text PRIMARY KEY,
+    arena_id        text NOT NULL DEFAULT 'default' REFERENCES arenas(id),
+    model_id        text NOT NULL REFERENCES models(id),
+    conversation_id bigint REFERENCES conversations(id) ON DELETE SET NULL,
+    provider        text,
+    prompt_version  text,
+    ts_ms           bigint NOT NULL, -- invocation start
+    latency_ms      bigint NOT NULL DEFAULT 0,
+    input_tokens    int NOT NULL DEFAULT 0,
+    output_tokens   int NOT NULL DEFAULT 0,
+    cost_usd        double precision NOT NULL DEFAULT 0,
+    status          text NOT NULL DEFAULT 'ok' CHECK (status IN ('ok','error')),
+    error           text,
+    estimated       boolean NOT NULL DEFAULT false -- tokens approximated from message text
+);
+CREATE INDEX IF NOT EXISTS idx_invocations_arena_model_ts ON invocations(arena_id, model_id, ts_ms);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS ux_conversations_arena_hash;
+
+ALTER TABLE invocations        DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE conversation_links DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE conversations      DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE model_analytics    DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE positions          DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE trades             DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE price_latest       DROP COLUMN IF EXISTS content_hash;
@@ -0,0 +1,14 @@
+-- Content hashes written by cmd/importer: unchanged rows are skipped, changed
+-- ones updated, and rows missing from the source are removed (or closed).
+ALTER TABLE price_latest       ADD COLUMN IF NOT EXISTS content_hash text;
+ALTER TABLE trades             ADD COLUMN IF NOT EXISTS content_hash text;
+ALTER TABLE positions          ADD COLUMN IF NOT EXISTS content_hash text;
+ALTER TABLE model_analytics    ADD COLUMN IF NOT EXISTS content_hash text;
+ALTER TABLE conversations      ADD COLUMN IF NOT EXISTS content_hash text;
+ALTER TABLE conversation_links ADD COLUMN IF NOT EXISTS content_hash text;
+ALTER TABLE invocations        ADD COLUMN IF NOT EXISTS content_hash text;
+
+-- Conversations have no id in the source: the hash of the model and its
+-- messages is the natural key. Rows imported before hashing (NULL) are
+-- replaced on the next import.
+CREATE UNIQUE INDEX IF NOT EXISTS ux_conversations_arena_hash ON conversations(arena_id, content_hash);
@@ -0,0 +1,14 @@
+ALTER TABLE positions DROP CONSTRAINT IF EXISTS positions_side_quantity_check;
+
+ALTER TABLE positions DROP COLUMN IF EXISTS index_col;
+ALTER TABLE positions DROP COLUMN IF EXISTS wait_for_fill;
+ALTER TABLE positions DROP COLUMN IF EXISTS oid;
+ALTER TABLE positions DROP COLUMN IF EXISTS sl_oid;
+ALTER TABLE positions DROP COLUMN IF EXISTS tp_oid;
+ALTER TABLE positions DROP COLUMN IF EXISTS entry_oid;
+ALTER TABLE positions DROP COLUMN IF EXISTS exit_plan;
+ALTER TABLE positions DROP COLUMN IF EXISTS slippage;
+ALTER TABLE positions DROP COLUMN IF EXISTS unrealized_pnl;
+ALTER TABLE positions DROP COLUMN IF EXISTS closed_pnl;
+ALTER TABLE positions DROP COLUMN IF EXISTS risk_usd;
+ALTER TABLE positions DROP COLUMN IF EXISTS margin;
@@ -0,0 +1,22 @@
+-- Positions carry every field of the API Position so the DB can serve
+-- /positions exactly like positions.json. Direction is the sign of quantity.
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS margin         double precision;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS risk_usd       double precision;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS closed_pnl     double precision;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS unrealized_pnl double precision;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS slippage       double precision;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS exit_plan      jsonb; -- {profit_target, stop_loss, invalidation_condition}
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS entry_oid      bigint;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS tp_oid         bigint;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS sl_oid         bigint;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS oid            bigint;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS wait_for_fill  boolean NOT NULL DEFAULT false;
+ALTER TABLE positions ADD COLUMN IF NOT EXISTS index_col      jsonb;
+
+-- Rows imported before this were all stored as long
+UPDATE positions SET side = 'short' WHERE quantity < 0 AND side <> 'short';
+UPDATE positions SET side = 'long'  WHERE quantity > 0 AND side <> 'long';
+
+ALTER TABLE positions DROP CONSTRAINT IF EXISTS positions_side_quantity_check;
+ALTER TABLE positions ADD CONSTRAINT positions_side_quantity_check
+    CHECK (quantity = 0 OR (side = 'short') = (quantity < 0));
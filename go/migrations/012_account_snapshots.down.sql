@@ -0,0 +1,33 @@
+-- Restore the placeholder leaderboard from 005 before its inputs go away
+DROP MATERIALIZED VIEW IF EXISTS v_leaderboard;
+CREATE MATERIALIZED VIEW v_leaderboard AS
+WITH last_eq AS (
+    SELECT DISTINCT ON (arena_id, model_id) arena_id, model_id, ts_ms, equity_usd
+    FROM account_equity_snapshots
+    ORDER BY arena_id, model_id, ts_ms DESC
+)
+SELECT am.arena_id,
+       m.id AS model_id,
+       l.equity_usd AS equity,
+       0.0::double precision AS sharpe, -- placeholder until stat job fills
+       0    ::int AS num_trades,
+       0    ::int AS num_wins,
+       0    ::int AS num_losses,
+       0.0  ::double precision AS win_dollars,
+       0.0  ::double precision AS lose_dollars,
+       0.0  ::double precision AS return_pct
+FROM arena_models am
+JOIN models m ON m.id = am.model_id
+LEFT JOIN last_eq l ON l.arena_id = am.arena_id AND l.model_id = am.model_id;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_v_leaderboard ON v_leaderboard(arena_id, model_id);
+
+DROP TABLE IF EXISTS account_position_snapshots;
+
+DROP INDEX IF EXISTS idx_equity_arena_hourly;
+DROP INDEX IF EXISTS ux_equity_arena_source;
+ALTER TABLE account_equity_snapshots DROP COLUMN IF EXISTS content_hash;
+ALTER TABLE account_equity_snapshots DROP COLUMN IF EXISTS since_inception_minute_marker;
+ALTER TABLE account_equity_snapshots DROP COLUMN IF EXISTS since_inception_hourly_marker;
+ALTER TABLE account_equity_snapshots DROP COLUMN IF EXISTS sharpe_ratio;
+ALTER TABLE account_equity_snapshots DROP COLUMN IF EXISTS cum_pnl_pct;
+ALTER TABLE account_equity_snapshots DROP COLUMN IF EXISTS source_id;
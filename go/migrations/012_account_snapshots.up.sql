@@ -0,0 +1,57 @@
+-- Account totals (account-totals.json) land in account_equity_snapshots with
+-- every AccountTotal field; their nested positions are kept per snapshot.
+ALTER TABLE account_equity_snapshots ADD COLUMN IF NOT EXISTS source_id                     text; -- AccountTotal.id
+ALTER TABLE account_equity_snapshots ADD COLUMN IF NOT EXISTS cum_pnl_pct                   double precision;
+ALTER TABLE account_equity_snapshots ADD COLUMN IF NOT EXISTS sharpe_ratio                  double precision;
+ALTER TABLE account_equity_snapshots ADD COLUMN IF NOT EXISTS since_inception_hourly_marker int;
+ALTER TABLE account_equity_snapshots ADD COLUMN IF NOT EXISTS since_inception_minute_marker int;
+ALTER TABLE account_equity_snapshots ADD COLUMN IF NOT EXISTS content_hash                  text;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_equity_arena_source ON account_equity_snapshots(arena_id, source_id);
+CREATE INDEX IF NOT EXISTS idx_equity_arena_hourly ON account_equity_snapshots(arena_id, since_inception_hourly_marker);
+
+CREATE TABLE IF NOT EXISTS account_position_snapshots (
+    snapshot_id    bigint NOT NULL REFERENCES account_equity_snapshots(id) ON DELETE CASCADE,
+    symbol         text NOT NULL REFERENCES symbols(symbol),
+    side           text NOT NULL CHECK (side IN ('long','short')),
+    quantity       double precision NOT NULL,
+    entry_price    double precision NOT NULL,
+    current_price  double precision,
+    unrealized_pnl double precision,
+    leverage       double precision,
+    payload        jsonb NOT NULL, -- full Position as in the source
+    PRIMARY KEY (snapshot_id, symbol)
+);
+
+-- Leaderboard from the latest snapshot (equity, sharpe, cumulative return)
+-- and closed trades (counts and dollars won/lost).
+DROP MATERIALIZED VIEW IF EXISTS v_leaderboard;
+CREATE MATERIALIZED VIEW v_leaderboard AS
+WITH last_eq AS (
+    SELECT DISTINCT ON (arena_id, model_id) arena_id, model_id, ts_ms, equity_usd, sharpe_ratio, cum_pnl_pct
+    FROM account_equity_snapshots
+    ORDER BY arena_id, model_id, ts_ms DESC
+), closed AS (
+    SELECT arena_id, model_id,
+           count(*)                                                        AS num_trades,
+           count(*) FILTER (WHERE realized_net_pnl > 0)                    AS num_wins,
+           count(*) FILTER (WHERE realized_net_pnl < 0)                    AS num_losses,
+           coalesce(sum(realized_net_pnl) FILTER (WHERE realized_net_pnl > 0), 0) AS win_dollars,
+           coalesce(sum(realized_net_pnl) FILTER (WHERE realized_net_pnl < 0), 0) AS lose_dollars
+    FROM trades
+    GROUP BY arena_id, model_id
+)
+SELECT am.arena_id,
+       m.id AS model_id,
+       l.equity_usd AS equity,
+       coalesce(l.sharpe_ratio, 0)::double precision AS sharpe,
+       coalesce(c.num_trades, 0)::int AS num_trades,
+       coalesce(c.num_wins, 0)::int AS num_wins,
+       coalesce(c.num_losses, 0)::int AS num_losses,
+       coalesce(c.win_dollars, 0)::double precision AS win_dollars,
+       coalesce(c.lose_dollars, 0)::double precision AS lose_dollars,
+       coalesce(l.cum_pnl_pct, 0)::double precision AS return_pct
+FROM arena_models am
+JOIN models m ON m.id = am.model_id
+LEFT JOIN last_eq l ON l.arena_id = am.arena_id AND l.model_id = am.model_id
+LEFT JOIN closed c ON c.arena_id = am.arena_id AND c.model_id = am.model_id;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_v_leaderboard ON v_leaderboard(arena_id, model_id);
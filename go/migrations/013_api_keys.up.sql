@@ -0,0 +1,12 @@
+-- API keys for the ingest, admin and model write routes. Only the sha256 of
+-- the secret part of a key (nof0_<id>_<secret>) is stored; the full key is
+-- shown once, when it is created.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           text PRIMARY KEY,
+    name         text NOT NULL,
+    role         text NOT NULL CHECK (role IN ('reader','ingester','admin')),
+    secret_hash  text NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    last_used_at timestamptz,
+    revoked_at   timestamptz
+);
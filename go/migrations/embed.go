@@ -0,0 +1,9 @@
+// Package migrations embeds the versioned schema migrations applied by
+// `nof0 migrate` (see internal/migrate). Files are NNN_name.up.sql with a
+// matching NNN_name.down.sql that reverts them.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -4,15 +4,21 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"os"
 
+	"nof0-api/internal/auth"
 	"nof0-api/internal/config"
+	"nof0-api/internal/errs"
 	"nof0-api/internal/handler"
+	"nof0-api/internal/migrate"
 	"nof0-api/internal/svc"
 
 	"github.com/zeromicro/go-zero/core/conf"
 	"github.com/zeromicro/go-zero/rest"
+	"github.com/zeromicro/go-zero/rest/httpx"
 )
 
 var configFile = flag.String("f", "etc/nof0.yaml", "the config file")
@@ -23,11 +29,32 @@ func main() {
 	var c config.Config
 	conf.MustLoad(*configFile, &c)
 
+	// nof0 -f etc/nof0.yaml migrate [-dsn DSN] up|down|redo|status
+	if flag.Arg(0) == "migrate" {
+		if err := migrate.Command(context.Background(), c.Postgres.DSN, flag.Args()[1:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "migrate:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	// nof0 -f etc/nof0.yaml keys [-dsn DSN] create|list|revoke
+	if flag.Arg(0) == "keys" {
+		if err := auth.Command(context.Background(), c.Postgres.DSN, flag.Args()[1:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "keys:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	server := rest.MustNewServer(c.RestConf)
 	defer server.Stop()
+	httpx.SetErrorHandlerCtx(errs.Handler)
 
 	ctx := svc.NewServiceContext(c)
+	server.Use(ctx.RateLimit)
 	handler.RegisterHandlers(server, ctx)
+	ctx.Jobs.Start()
+	defer ctx.Jobs.Stop()
 
 	fmt.Printf("Starting server at %s:%d...\n", c.Host, c.Port)
 	server.Start()